@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/labstack/echo/v4"
+)
+
+var (
+	// ErrPeerNotFound is returned by NodeAdmin when no peer is connected
+	// from the address
+	ErrPeerNotFound = errors.New("peer not found")
+	// ErrNotValidator is returned by NodeAdmin when block production is
+	// controlled on a node that doesn't produce blocks
+	ErrNotValidator = errors.New("node is not a validator")
+
+	errNotNodeAdmin = errors.New("caller is not the node admin")
+	errNoNodeAdmin  = errors.New("node administration is not available on this node")
+)
+
+// NodeAdmin inspects and controls the node behind the API for its operator.
+type NodeAdmin interface {
+	NodeStatus() (*NodeStatus, error)
+	Peers() []*PeerInfo
+	DisconnectPeer(addr string) error
+	Mempool() *Mempool
+	PauseBlockProduction() error
+	ResumeBlockProduction() error
+}
+
+// NodeStatus is the state of the node.
+type NodeStatus struct {
+	ID        string `json:"id"`
+	Validator bool   `json:"validator"`
+	// Height is the height of the head of the chain, -1 before the node has
+	// any block
+	Height                *big.Int     `json:"height"`
+	Head                  *common.Hash `json:"head,omitempty"`
+	BlockProductionPaused bool         `json:"blockProductionPaused"`
+	Peers                 int          `json:"peers"`
+	Mempool               MempoolCount `json:"mempool"`
+	AuctionQueue          AuctionQueue `json:"auctionQueue"`
+	// DatabaseRecords is the number of keys in the node's database
+	DatabaseRecords int64 `json:"databaseRecords"`
+}
+
+// MempoolCount counts the transactions of the mempool. Pending transactions
+// go in the next block, All also holds those already included and is pruned
+// oldest first once it reaches MaxLength.
+type MempoolCount struct {
+	Pending   int `json:"pending"`
+	All       int `json:"all"`
+	MaxLength int `json:"maxLength"`
+}
+
+// Mempool is the mempool's counts and its pending transactions, in the order
+// they were added.
+type Mempool struct {
+	MempoolCount
+	Transactions []*types.Transaction `json:"transactions"`
+}
+
+// AuctionQueue is the number of open auctions waiting to close and when the
+// next one closes, in unix milliseconds.
+type AuctionQueue struct {
+	Depth      int   `json:"depth"`
+	NextExpiry int64 `json:"nextExpiry,omitempty"`
+}
+
+// PeerInfo describes a connection to another node.
+type PeerInfo struct {
+	Addr string `json:"addr"`
+	// Outgoing is set for connections this node dialled
+	Outgoing         bool      `json:"outgoing"`
+	ConnectedAt      time.Time `json:"connectedAt"`
+	MessagesSent     uint64    `json:"messagesSent"`
+	MessagesReceived uint64    `json:"messagesReceived"`
+	BytesSent        uint64    `json:"bytesSent"`
+	BytesReceived    uint64    `json:"bytesReceived"`
+}
+
+// requireAdmin only lets requests signed by the admin address through to the
+// admin endpoints.
+func (s *Server) requireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if s.NodeAdmin == nil {
+			return writeError(c, http.StatusNotImplemented, errNoNodeAdmin)
+		}
+		caller := callerFrom(c)
+		if caller == nil {
+			return writeError(c, http.StatusUnauthorized, errAuthRequired)
+		}
+		if s.AdminAddress == (common.Address{}) || caller.Address != s.AdminAddress {
+			return writeError(c, http.StatusForbidden, errNotNodeAdmin)
+		}
+		return next(c)
+	}
+}
+
+func (s *Server) handleGetNodeStatus(c echo.Context) error {
+	status, err := s.NodeAdmin.NodeStatus()
+	if err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, status)
+}
+
+func (s *Server) handleGetPeers(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.NodeAdmin.Peers())
+}
+
+func (s *Server) handleDisconnectPeer(c echo.Context) error {
+	// IPv6 addresses have their brackets and colons escaped
+	addr, err := url.PathUnescape(c.Param("addr"))
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	if err := s.NodeAdmin.DisconnectPeer(addr); err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) handleGetMempool(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.NodeAdmin.Mempool())
+}
+
+func (s *Server) handlePauseBlockProduction(c echo.Context) error {
+	if err := s.NodeAdmin.PauseBlockProduction(); err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) handleResumeBlockProduction(c echo.Context) error {
+	if err := s.NodeAdmin.ResumeBlockProduction(); err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/go-kit/log"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNodeAdmin is a node with one peer that records the admin actions taken
+// on it.
+type fakeNodeAdmin struct {
+	validator    bool
+	paused       bool
+	disconnected []string
+}
+
+func (a *fakeNodeAdmin) NodeStatus() (*NodeStatus, error) {
+	return &NodeStatus{ID: "node", Validator: a.validator, Height: big.NewInt(7), BlockProductionPaused: a.paused, Peers: 1}, nil
+}
+
+func (a *fakeNodeAdmin) Peers() []*PeerInfo {
+	return []*PeerInfo{{Addr: "[::1]:4000", Outgoing: true, MessagesSent: 3}}
+}
+
+func (a *fakeNodeAdmin) DisconnectPeer(addr string) error {
+	if addr != "[::1]:4000" {
+		return ErrPeerNotFound
+	}
+	a.disconnected = append(a.disconnected, addr)
+	return nil
+}
+
+func (a *fakeNodeAdmin) Mempool() *Mempool {
+	return &Mempool{MempoolCount: MempoolCount{Pending: 0, All: 2, MaxLength: 1000}}
+}
+
+func (a *fakeNodeAdmin) PauseBlockProduction() error {
+	if !a.validator {
+		return ErrNotValidator
+	}
+	a.paused = true
+	return nil
+}
+
+func (a *fakeNodeAdmin) ResumeBlockProduction() error {
+	if !a.validator {
+		return ErrNotValidator
+	}
+	a.paused = false
+	return nil
+}
+
+func newAdminServer(admin NodeAdmin, adminKey cryptoocax.PrivateKey) *echo.Echo {
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, assert.AnError)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger(), NodeAdmin: admin, AdminAddress: adminKey.PublicKey().Address()}, mockChain, nil)
+
+	e := echo.New()
+	e.Use(s.authenticate)
+	g := e.Group("/admin", s.requireAdmin)
+	g.GET("/status", s.handleGetNodeStatus)
+	g.GET("/peers", s.handleGetPeers)
+	g.DELETE("/peers/:addr", s.handleDisconnectPeer)
+	g.GET("/mempool", s.handleGetMempool)
+	g.POST("/blockProduction/pause", s.handlePauseBlockProduction)
+	g.POST("/blockProduction/resume", s.handleResumeBlockProduction)
+	return e
+}
+
+func TestAdminAPI(t *testing.T) {
+	adminKey := cryptoocax.GeneratePrivateKey()
+	node := &fakeNodeAdmin{validator: true}
+	e := newAdminServer(node, adminKey)
+
+	// only the admin gets in
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/status", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	var res APIError
+	assert.Equal(t, http.StatusForbidden, serveSigned(t, e, cryptoocax.GeneratePrivateKey(), http.MethodGet, "/admin/status", nil, &res))
+	assert.Equal(t, CodeNotNodeAdmin, res.Code)
+
+	var status NodeStatus
+	require.Equal(t, http.StatusOK, serveSigned(t, e, adminKey, http.MethodGet, "/admin/status", nil, &status))
+	assert.Equal(t, int64(7), status.Height.Int64())
+	assert.False(t, status.BlockProductionPaused)
+
+	var peers []*PeerInfo
+	require.Equal(t, http.StatusOK, serveSigned(t, e, adminKey, http.MethodGet, "/admin/peers", nil, &peers))
+	require.Len(t, peers, 1)
+	assert.Equal(t, uint64(3), peers[0].MessagesSent)
+
+	var mempool Mempool
+	require.Equal(t, http.StatusOK, serveSigned(t, e, adminKey, http.MethodGet, "/admin/mempool", nil, &mempool))
+	assert.Equal(t, 2, mempool.All)
+
+	// peer addresses are path escaped
+	assert.Equal(t, http.StatusNotFound, serveSigned(t, e, adminKey, http.MethodDelete, "/admin/peers/127.0.0.1:4000", nil, &res))
+	assert.Equal(t, CodePeerNotFound, res.Code)
+	assert.Equal(t, http.StatusNoContent, serveSigned(t, e, adminKey, http.MethodDelete, "/admin/peers/%5B::1%5D:4000", nil, nil))
+	assert.Equal(t, []string{"[::1]:4000"}, node.disconnected)
+
+	assert.Equal(t, http.StatusNoContent, serveSigned(t, e, adminKey, http.MethodPost, "/admin/blockProduction/pause", nil, nil))
+	assert.True(t, node.paused)
+	assert.Equal(t, http.StatusNoContent, serveSigned(t, e, adminKey, http.MethodPost, "/admin/blockProduction/resume", nil, nil))
+	assert.False(t, node.paused)
+
+	node.validator = false
+	assert.Equal(t, http.StatusConflict, serveSigned(t, e, adminKey, http.MethodPost, "/admin/blockProduction/pause", nil, &res))
+	assert.Equal(t, CodeNotValidator, res.Code)
+}
+
+func TestAdminAPIDisabled(t *testing.T) {
+	adminKey := cryptoocax.GeneratePrivateKey()
+	e := newAdminServer(nil, adminKey)
+
+	var res APIError
+	assert.Equal(t, http.StatusNotImplemented, serveSigned(t, e, adminKey, http.MethodGet, "/admin/status", nil, &res))
+	assert.Equal(t, CodeNotImplemented, res.Code)
+}
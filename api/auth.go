@@ -0,0 +1,369 @@
+package api
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/labstack/echo/v4"
+)
+
+// Headers carrying a signed request. The signature is over RequestHash and
+// binds the request to the caller's address.
+const (
+	HeaderAuthAddress   = "X-OCAX-Address"
+	HeaderAuthTimestamp = "X-OCAX-Timestamp"
+	HeaderAuthSignature = "X-OCAX-Signature"
+)
+
+const (
+	defaultAuthWindow = 5 * time.Minute
+	callerContextKey  = "caller"
+	// maxSignedBodySize bounds the body read to hash a signed request
+	maxSignedBodySize = 1 << 20
+)
+
+var (
+	errAuthRequired  = errors.New("request must be signed")
+	errAuthHeaders   = errors.New("signed requests need address, timestamp and signature headers")
+	errAuthTimestamp = errors.New("request timestamp is outside the allowed window")
+	errAuthReplayed  = errors.New("request has already been used")
+	errAuthSignature = errors.New("request signature does not match address")
+	errBodyTooLarge  = errors.New("request body is too large")
+)
+
+// Caller is the authenticated identity behind a signed request.
+type Caller struct {
+	Address common.Address
+	// Participant is the caller's registry record, nil if not onboarded
+	Participant *types.Participant
+}
+
+// HasRole reports whether the caller is an active participant in role.
+func (c *Caller) HasRole(role types.ParticipantRole) bool {
+	return c.Participant != nil && c.Participant.CheckRole(role, nowMs()) == nil
+}
+
+// RequestHash returns the hash a caller signs to authenticate a request. It
+// covers the method, the path including the query string, the body and the
+// unix time in milliseconds the request was made.
+func RequestHash(method, requestURI string, body []byte, timestamp int64) common.Hash {
+	return common.BytesToHash(cryptoocax.Keccak256Hash(bytes.Join([][]byte{
+		[]byte(method),
+		[]byte(requestURI),
+		cryptoocax.Keccak256Hash(body),
+		[]byte(strconv.FormatInt(timestamp, 10)),
+	}, []byte("\n"))))
+}
+
+// SignRequest adds the authentication headers for key to req, body must be
+// the request body.
+func SignRequest(req *http.Request, body []byte, key cryptoocax.PrivateKey) error {
+	timestamp := time.Now().UnixNano() / int64(time.Millisecond)
+	sig, err := key.Sign(RequestHash(req.Method, req.URL.RequestURI(), body, timestamp).Bytes())
+	if err != nil {
+		return err
+	}
+	req.Header.Set(HeaderAuthAddress, key.PublicKey().Address().Hex())
+	req.Header.Set(HeaderAuthTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderAuthSignature, hexutil.Encode(sig.ToBytes()))
+	return nil
+}
+
+// replayGuard remembers the signed requests seen within the replay window so
+// a captured request can't be sent again. The requests are also queued by
+// timestamp so expired ones are dropped without scanning the rest.
+type replayGuard struct {
+	mu      sync.Mutex
+	window  time.Duration
+	seen    map[common.Hash]int64
+	expires seenRequests
+}
+
+func newReplayGuard(window time.Duration) *replayGuard {
+	return &replayGuard{window: window, seen: make(map[common.Hash]int64)}
+}
+
+type seenRequest struct {
+	hash      common.Hash
+	timestamp int64
+}
+
+// seenRequests is a min-heap of requests ordered by timestamp.
+type seenRequests []seenRequest
+
+func (q seenRequests) Len() int           { return len(q) }
+func (q seenRequests) Less(i, j int) bool { return q[i].timestamp < q[j].timestamp }
+func (q seenRequests) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *seenRequests) Push(x interface{}) {
+	*q = append(*q, x.(seenRequest))
+}
+
+func (q *seenRequests) Pop() interface{} {
+	old := *q
+	n := len(old)
+	x := old[n-1]
+	*q = old[0 : n-1]
+	return x
+}
+
+// check reports whether a request signed at timestamp (ms) is fresh and has
+// not been seen before, and remembers it.
+func (g *replayGuard) check(hash common.Hash, timestamp int64, now time.Time) error {
+	windowMs := g.window.Milliseconds()
+	nowMs := now.UnixNano() / int64(time.Millisecond)
+	if timestamp < nowMs-windowMs || timestamp > nowMs+windowMs {
+		return errAuthTimestamp
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for len(g.expires) > 0 && g.expires[0].timestamp < nowMs-windowMs {
+		delete(g.seen, heap.Pop(&g.expires).(seenRequest).hash)
+	}
+	if _, ok := g.seen[hash]; ok {
+		return errAuthReplayed
+	}
+	g.seen[hash] = timestamp
+	heap.Push(&g.expires, seenRequest{hash: hash, timestamp: timestamp})
+	return nil
+}
+
+// authenticate verifies signed requests and stores the caller in the echo
+// context. Unsigned requests pass through as anonymous unless RequireAuth is
+// set, the websocket endpoint is always open since browsers can't set headers.
+func (s *Server) authenticate(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		address := req.Header.Get(HeaderAuthAddress)
+		if address == "" && req.Header.Get(HeaderAuthSignature) == "" {
+			if s.RequireAuth && c.Path() != "/ws" {
+				return writeError(c, http.StatusUnauthorized, errAuthRequired)
+			}
+			return next(c)
+		}
+
+		timestamp, err := strconv.ParseInt(req.Header.Get(HeaderAuthTimestamp), 10, 64)
+		if err != nil || !common.IsHexAddress(address) {
+			return writeError(c, http.StatusUnauthorized, errAuthHeaders)
+		}
+		sig, err := hexutil.Decode(req.Header.Get(HeaderAuthSignature))
+		if err != nil {
+			return writeError(c, http.StatusUnauthorized, errAuthHeaders)
+		}
+
+		// the body is read to hash it and put back for the handler
+		var body []byte
+		if req.Body != nil {
+			if body, err = io.ReadAll(http.MaxBytesReader(c.Response(), req.Body, maxSignedBodySize)); err != nil {
+				var tooLarge *http.MaxBytesError
+				if errors.As(err, &tooLarge) {
+					err = errBodyTooLarge
+				}
+				return writeError(c, http.StatusBadRequest, err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		hash := RequestHash(req.Method, req.URL.RequestURI(), body, timestamp)
+		caller, err := s.verifyCaller(common.HexToAddress(address), hash, timestamp, sig)
+		if err != nil {
+			return writeError(c, http.StatusUnauthorized, err)
+		}
+		c.Set(callerContextKey, caller)
+
+		return next(c)
+	}
+}
+
+// verifyCaller checks that sig signs the request hash by address and that
+// the request is fresh, and returns the caller it authenticates.
+func (s *Server) verifyCaller(address common.Address, hash common.Hash, timestamp int64, sig []byte) (*Caller, error) {
+	signer, err := types.RecoverAddress(hash, sig)
+	if err != nil || signer != address {
+		return nil, errAuthSignature
+	}
+	if err := s.replays.check(hash, timestamp, time.Now()); err != nil {
+		return nil, err
+	}
+
+	caller := &Caller{Address: signer}
+	if participant, err := s.bc.GetParticipant(signer); err == nil {
+		caller.Participant = participant
+	}
+	return caller, nil
+}
+
+// callerFrom returns the authenticated caller of a request, nil for anonymous
+// requests.
+func callerFrom(c echo.Context) *Caller {
+	caller, _ := c.Get(callerContextKey).(*Caller)
+	return caller
+}
+
+// quoteVisible reports whether caller may see quote in the auction opened by
+// requestor. Quoters only see their own quotes, while the requestor and
+// auditors see every quote. Anonymous callers see no quotes.
+func quoteVisible(caller *Caller, requestor common.Address, quote *types.Quote) bool {
+	if caller == nil {
+		return false
+	}
+	if caller.HasRole(types.RoleAuditor) || caller.Address == requestor {
+		return true
+	}
+	return quote.From == caller.Address
+}
+
+// filterQuotes returns the quotes of an auction visible to caller.
+func (s *Server) filterQuotes(caller *Caller, rfqTxHash common.Hash, quotes []*types.Quote) []*types.Quote {
+	if len(quotes) == 0 {
+		return quotes
+	}
+	if caller == nil {
+		return []*types.Quote{}
+	}
+	requestor := s.rfqRequestor(rfqTxHash)
+	visible := make([]*types.Quote, 0, len(quotes))
+	for _, quote := range quotes {
+		if quoteVisible(caller, requestor, quote) {
+			visible = append(visible, quote)
+		}
+	}
+	return visible
+}
+
+// rfqRequestor returns the address that requested the RFQ, the zero address
+// when the request is not known to this node.
+func (s *Server) rfqRequestor(rfqTxHash common.Hash) common.Address {
+	rfqRequest, err := s.bc.GetRFQRequestByHash(rfqTxHash)
+	if err != nil {
+		return common.Address{}
+	}
+	return rfqRequest.From
+}
+
+// filterRFQ returns a copy of an open or closed RFQ holding only the quotes
+// visible to caller. The RFQ itself may be shared so it is never modified.
+func (s *Server) filterRFQ(caller *Caller, rfq *types.OpenRFQ) *types.OpenRFQ {
+	if rfq.Data == nil {
+		return rfq
+	}
+	data := *rfq.Data
+	data.Quotes = s.filterQuotes(caller, data.RFQTxHash, data.Quotes)
+	filtered := *rfq
+	filtered.Data = &data
+	return &filtered
+}
+
+// rfqVisible reports whether caller may see an RFQ. Public RFQs are visible
+// to everyone, private ones only to their requestor, their recipients and
+// auditors, so anonymous callers never see them.
+func (s *Server) rfqVisible(caller *Caller, data *types.RFQData) bool {
+	if data == nil || !data.IsPrivate() {
+		return true
+	}
+	if caller == nil {
+		return false
+	}
+	if caller.HasRole(types.RoleAuditor) || data.CheckQuoter(caller.Address) == nil {
+		return true
+	}
+	return caller.Address == s.rfqRequestor(data.RFQTxHash)
+}
+
+// rfqRequestVisible applies the visibility of rfqVisible to an RFQ request
+// whose recipients have not yet been resolved by a validator.
+func (s *Server) rfqRequestVisible(caller *Caller, rfqRequest *types.RFQRequest) bool {
+	if rfqRequest.Data == nil || !rfqRequest.Data.IsPrivate() {
+		return true
+	}
+	if caller == nil {
+		return false
+	}
+	if caller.HasRole(types.RoleAuditor) || caller.Address == rfqRequest.From {
+		return true
+	}
+	recipients, err := s.DealerGroups.Recipients(rfqRequest.Data)
+	if err != nil {
+		return false
+	}
+	for _, recipient := range recipients {
+		if recipient == caller.Address {
+			return true
+		}
+	}
+	return false
+}
+
+// auctionVisible reports whether caller may see the auction of rfqTxHash:
+// the open or closed RFQ as rfqVisible judges it, or the RFQ request before
+// a validator has opened it. Auctions unknown to this node are hidden.
+func (s *Server) auctionVisible(caller *Caller, rfqTxHash common.Hash) bool {
+	if rfq, err := s.bc.GetOpenRFQByHash(rfqTxHash); err == nil {
+		return s.rfqVisible(caller, rfq.Data)
+	}
+	if rfq, err := s.bc.GetClosedRFQByHash(rfqTxHash); err == nil {
+		return s.rfqVisible(caller, rfq.Data)
+	}
+	rfqRequest, err := s.bc.GetRFQRequestByHash(rfqTxHash)
+	if err != nil {
+		return false
+	}
+	return s.rfqRequestVisible(caller, rfqRequest)
+}
+
+// txVisible reports whether caller may see tx: RFQ requests, RFQs and
+// quotes are only shown to the callers that may see them through the RFQ
+// and quote endpoints. A transaction can't be narrowed to the quotes a
+// caller may see without changing its hash, so RFQs holding quotes are only
+// shown to the callers that may see all of them.
+func (s *Server) txVisible(caller *Caller, tx *types.Transaction) bool {
+	switch tx.Type() {
+	case types.RFQRequestTxType:
+		return s.rfqRequestVisible(caller, &types.RFQRequest{From: *tx.From(), Data: tx.EmbeddedData().(*types.SignableData)})
+	case types.OpenRFQTxType:
+		data := tx.EmbeddedData().(*types.RFQData)
+		if !s.rfqVisible(caller, data) {
+			return false
+		}
+		return len(s.filterQuotes(caller, data.RFQTxHash, data.Quotes)) == len(data.Quotes)
+	case types.QuoteTxType:
+		data := tx.EmbeddedData().(*types.QuoteData)
+		if !s.auctionVisible(caller, data.RFQTxHash) {
+			return false
+		}
+		return quoteVisible(caller, s.rfqRequestor(data.RFQTxHash), &types.Quote{From: *tx.From(), Data: data})
+	}
+	return true
+}
+
+// rfqAudience returns the filter selecting the websocket clients an RFQ and
+// its quotes may be pushed to.
+func (s *Server) rfqAudience(data *types.RFQData) func(*Caller) bool {
+	return func(caller *Caller) bool {
+		return s.rfqVisible(caller, data)
+	}
+}
+
+// visibleRFQs returns the RFQs visible to caller, each filtered to the
+// quotes the caller may see.
+func (s *Server) visibleRFQs(caller *Caller, rfqs []*types.OpenRFQ) []*types.OpenRFQ {
+	visible := make([]*types.OpenRFQ, 0, len(rfqs))
+	for _, rfq := range rfqs {
+		if s.rfqVisible(caller, rfq.Data) {
+			visible = append(visible, s.filterRFQ(caller, rfq))
+		}
+	}
+	return visible
+}
@@ -0,0 +1,316 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/go-kit/log"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticate(t *testing.T) {
+	key := cryptoocax.GeneratePrivateKey()
+
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, assert.AnError)
+	s := NewServer(ServerConfig{RequireAuth: true}, mockChain, nil)
+
+	e := echo.New()
+	e.Use(s.authenticate)
+	var caller *Caller
+	e.GET("/closedRFQs", func(c echo.Context) error {
+		caller = callerFrom(c)
+		return c.NoContent(http.StatusOK)
+	})
+	serve := func(req *http.Request) int {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// unsigned requests are rejected when auth is required
+	assert.Equal(t, http.StatusUnauthorized, serve(httptest.NewRequest(http.MethodGet, "/closedRFQs", nil)))
+
+	req := httptest.NewRequest(http.MethodGet, "/closedRFQs", nil)
+	require.NoError(t, SignRequest(req, nil, key))
+	assert.Equal(t, http.StatusOK, serve(req))
+	require.NotNil(t, caller)
+	assert.Equal(t, key.PublicKey().Address(), caller.Address)
+
+	// the same signed request can't be sent twice
+	assert.Equal(t, http.StatusUnauthorized, serve(req))
+
+	// the signature covers the path
+	req = httptest.NewRequest(http.MethodGet, "/closedRFQs", nil)
+	require.NoError(t, SignRequest(req, nil, key))
+	req.URL.RawQuery = "all=true"
+	req.RequestURI = req.URL.RequestURI()
+	assert.Equal(t, http.StatusUnauthorized, serve(req))
+
+	// stale requests are rejected
+	stale := time.Now().Add(-2*defaultAuthWindow).UnixNano() / int64(time.Millisecond)
+	sig, err := key.Sign(RequestHash(http.MethodGet, "/closedRFQs", nil, stale).Bytes())
+	require.NoError(t, err)
+	req = httptest.NewRequest(http.MethodGet, "/closedRFQs", nil)
+	req.Header.Set(HeaderAuthAddress, key.PublicKey().Address().Hex())
+	req.Header.Set(HeaderAuthTimestamp, strconv.FormatInt(stale, 10))
+	req.Header.Set(HeaderAuthSignature, "0x"+common.Bytes2Hex(sig.ToBytes()))
+	assert.Equal(t, http.StatusUnauthorized, serve(req))
+
+	// signed bodies are bounded
+	body := make([]byte, maxSignedBodySize+1)
+	req = httptest.NewRequest(http.MethodGet, "/closedRFQs", bytes.NewReader(body))
+	require.NoError(t, SignRequest(req, body, key))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, serve(req))
+}
+
+func TestReplayGuard(t *testing.T) {
+	g := newReplayGuard(time.Minute)
+	now := time.Now()
+	nowMs := now.UnixNano() / int64(time.Millisecond)
+	first, second := common.HexToHash("0x01"), common.HexToHash("0x02")
+
+	require.NoError(t, g.check(first, nowMs-time.Minute.Milliseconds(), now))
+	require.NoError(t, g.check(second, nowMs, now))
+	assert.ErrorIs(t, g.check(first, nowMs-time.Minute.Milliseconds(), now), errAuthReplayed)
+
+	// once past the window a request is forgotten, later ones are kept
+	later := now.Add(time.Second)
+	assert.ErrorIs(t, g.check(first, nowMs-time.Minute.Milliseconds(), later), errAuthTimestamp)
+	require.NoError(t, g.check(common.HexToHash("0x03"), nowMs, later))
+	assert.NotContains(t, g.seen, first)
+	assert.Len(t, g.expires, 2)
+	assert.ErrorIs(t, g.check(second, nowMs, later), errAuthReplayed)
+}
+
+func TestGetAuctionQuotesFiltersByCaller(t *testing.T) {
+	requestor := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	quoterA := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	quoterB := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	auditor := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	rfqTxHash := common.HexToHash("0x8ee43f9f2704982d627ae8fedf688b8fb0d80739667d9bb4b438215913a7ec7f")
+
+	quotes := []*types.Quote{
+		{From: quoterA, Data: &types.QuoteData{RFQTxHash: rfqTxHash, BidPrice: big.NewInt(1)}},
+		{From: quoterB, Data: &types.QuoteData{RFQTxHash: rfqTxHash, BidPrice: big.NewInt(2)}},
+	}
+
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetAuctionQuotes", rfqTxHash).Return(quotes, nil)
+	mockChain.On("GetOpenRFQByHash", rfqTxHash).Return(nil, core.ErrRFQNotFound)
+	mockChain.On("GetClosedRFQByHash", rfqTxHash).Return(nil, core.ErrRFQNotFound)
+	mockChain.On("GetRFQRequestByHash", rfqTxHash).Return(&types.RFQRequest{From: requestor, Data: &types.SignableData{}}, nil)
+	s := NewServer(ServerConfig{}, mockChain, nil)
+
+	tests := []struct {
+		name   string
+		caller *Caller
+		want   int
+	}{
+		{"anonymous", nil, 0},
+		{"quoter", &Caller{Address: quoterA}, 1},
+		{"requestor", &Caller{Address: requestor}, 2},
+		{"auditor", &Caller{Address: auditor, Participant: &types.Participant{
+			Address: auditor, Role: types.RoleAuditor, Status: types.ParticipantActive,
+		}}, 2},
+		{"other", &Caller{Address: auditor}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/quotes/"+rfqTxHash.Hex()[2:], nil)
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(req, rec)
+			c.SetParamNames("rfqTxHash")
+			c.SetParamValues(rfqTxHash.Hex()[2:])
+			if tt.caller != nil {
+				c.Set(callerContextKey, tt.caller)
+			}
+
+			require.NoError(t, s.handleGetAuctionQuotes(c))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var got []*types.Quote
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			assert.Len(t, got, tt.want)
+			if tt.caller != nil && tt.want == 1 {
+				assert.Equal(t, tt.caller.Address, got[0].From)
+			}
+		})
+	}
+}
+
+func TestGetOpenRFQsHidesPrivateRFQs(t *testing.T) {
+	requestor := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	dealer := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	other := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	publicHash := common.HexToHash("0x01")
+	privateHash := common.HexToHash("0x02")
+
+	rfqs := []*types.OpenRFQ{
+		{Data: &types.RFQData{RFQTxHash: publicHash, RFQRequest: &types.SignableData{}}},
+		{Data: &types.RFQData{
+			RFQTxHash:  privateHash,
+			RFQRequest: &types.SignableData{Recipients: []common.Address{dealer}},
+			Recipients: []common.Address{dealer},
+		}},
+	}
+
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetOpenRFQRequests").Return(rfqs, nil)
+	mockChain.On("GetRFQRequestByHash", privateHash).Return(&types.RFQRequest{From: requestor}, nil)
+	s := NewServer(ServerConfig{}, mockChain, nil)
+
+	tests := []struct {
+		name   string
+		caller *Caller
+		want   []common.Hash
+	}{
+		{"anonymous", nil, []common.Hash{publicHash}},
+		{"recipient", &Caller{Address: dealer}, []common.Hash{publicHash, privateHash}},
+		{"requestor", &Caller{Address: requestor}, []common.Hash{publicHash, privateHash}},
+		{"other", &Caller{Address: other}, []common.Hash{publicHash}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/openRFQs", nil), rec)
+			if tt.caller != nil {
+				c.Set(callerContextKey, tt.caller)
+			}
+
+			require.NoError(t, s.handleGetOpenRFQRequests(c))
+			require.Equal(t, http.StatusOK, rec.Code)
+
+			var got []struct {
+				Data struct {
+					RFQTxHash common.Hash `json:"rfqTxHash"`
+				} `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			hashes := make([]common.Hash, len(got))
+			for i, rfq := range got {
+				hashes[i] = rfq.Data.RFQTxHash
+			}
+			assert.Equal(t, tt.want, hashes)
+		})
+	}
+}
+
+func TestPrivateAuctionIsHidden(t *testing.T) {
+	requestor := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	dealer := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	other := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	rfqTxHash := common.HexToHash("0x01")
+
+	request := &types.SignableData{Recipients: []common.Address{dealer}}
+	rfq := types.NewOpenRFQ(common.Address{}, &types.RFQData{RFQTxHash: rfqTxHash, RFQRequest: request, Recipients: []common.Address{dealer}})
+	quote := types.NewQuote(dealer, &types.QuoteData{RFQTxHash: rfqTxHash, BidPrice: big.NewInt(1)})
+	requestTx := types.NewTx(types.NewRFQRequest(requestor, request))
+	quoteTx := types.NewTx(quote)
+
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetOpenRFQByHash", rfqTxHash).Return(rfq, nil)
+	mockChain.On("GetRFQRequestByHash", rfqTxHash).Return(&types.RFQRequest{From: requestor, Data: request}, nil)
+	mockChain.On("GetAuctionQuotes", rfqTxHash).Return([]*types.Quote{quote}, nil)
+	for _, tx := range []*types.Transaction{requestTx, quoteTx} {
+		mockChain.On("GetIncludedTx", tx.Hash()).Return(tx, &types.TxInclusion{}, nil)
+	}
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, mockChain, nil)
+
+	serve := func(handler echo.HandlerFunc, caller *Caller, param, value string) int {
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.SetParamNames(param)
+		c.SetParamValues(value)
+		if caller != nil {
+			c.Set(callerContextKey, caller)
+		}
+		require.NoError(t, handler(c))
+		return rec.Code
+	}
+	tests := []struct {
+		name   string
+		caller *Caller
+		want   int
+	}{
+		{"anonymous", nil, http.StatusNotFound},
+		{"other", &Caller{Address: other}, http.StatusNotFound},
+		{"recipient", &Caller{Address: dealer}, http.StatusOK},
+		{"requestor", &Caller{Address: requestor}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, serve(s.handleGetAuctionQuotes, tt.caller, "rfqTxHash", rfqTxHash.Hex()[2:]))
+			assert.Equal(t, tt.want, serve(s.handleGetTx, tt.caller, "hash", requestTx.Hash().Hex()))
+			assert.Equal(t, tt.want, serve(s.handleGetTx, tt.caller, "hash", quoteTx.Hash().Hex()))
+		})
+	}
+
+	rec := postRPC(t, s, `{"jsonrpc": "2.0", "id": 1, "method": "rfq_getQuotes", "params": ["`+rfqTxHash.Hex()+`"]}`)
+	var res RPCResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.NotNil(t, res.Error)
+	assert.Equal(t, RPCNotFound, res.Error.Code)
+}
+
+func TestClosedRFQTxHidesQuotes(t *testing.T) {
+	requestor := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	quoter := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	rival := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	rfqTxHash := common.HexToHash("0x01")
+
+	// a public auction closed with the quotes of two market makers
+	request := &types.SignableData{}
+	closedTx := types.NewTx(types.NewOpenRFQ(common.Address{}, &types.RFQData{
+		RFQTxHash:  rfqTxHash,
+		RFQRequest: request,
+		Status:     types.RFQStatusClosed,
+		Quotes: []*types.Quote{
+			types.NewQuote(quoter, &types.QuoteData{RFQTxHash: rfqTxHash, BidPrice: big.NewInt(1)}),
+			types.NewQuote(rival, &types.QuoteData{RFQTxHash: rfqTxHash, BidPrice: big.NewInt(2)}),
+		},
+	}))
+
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetRFQRequestByHash", rfqTxHash).Return(&types.RFQRequest{From: requestor, Data: request}, nil)
+	mockChain.On("GetIncludedTx", closedTx.Hash()).Return(closedTx, &types.TxInclusion{}, nil)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, mockChain, nil)
+
+	tests := []struct {
+		name   string
+		caller *Caller
+		want   int
+	}{
+		{"anonymous", nil, http.StatusNotFound},
+		{"quoter", &Caller{Address: quoter}, http.StatusNotFound},
+		{"requestor", &Caller{Address: requestor}, http.StatusOK},
+		{"auditor", &Caller{Address: cryptoocax.GeneratePrivateKey().PublicKey().Address(), Participant: &types.Participant{Role: types.RoleAuditor, Status: types.ParticipantActive}}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+			c.SetParamNames("hash")
+			c.SetParamValues(closedTx.Hash().Hex())
+			if tt.caller != nil {
+				c.Set(callerContextKey, tt.caller)
+			}
+			require.NoError(t, s.handleGetTx(c))
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/OCAX-labs/rfqrelayer/risk"
+	"github.com/OCAX-labs/rfqrelayer/tokens"
+	"github.com/labstack/echo/v4"
+)
+
+// Codes of the errors reported by the API. Requests that fail validation
+// are reported with CodeValidationFailed and the codes of core/types in
+// their details, other malformed values with those codes directly.
+const (
+	CodeInvalidRequest     types.ErrorCode = "INVALID_REQUEST"
+	CodeValidationFailed   types.ErrorCode = "VALIDATION_FAILED"
+	CodeInvalidCursor      types.ErrorCode = "INVALID_CURSOR"
+	CodeTokenNotListed     types.ErrorCode = "TOKEN_NOT_LISTED"
+	CodeTokenMismatch      types.ErrorCode = "TOKEN_MISMATCH"
+	CodeQuoteTokenMismatch types.ErrorCode = "QUOTE_TOKEN_MISMATCH"
+	CodeQuoteAmount        types.ErrorCode = "QUOTE_AMOUNT_EXCEEDED"
+	CodeQuoteNotSealed     types.ErrorCode = "QUOTE_NOT_SEALED"
+	CodeUnknownDealerGroup types.ErrorCode = "UNKNOWN_DEALER_GROUP"
+	CodeNoRecipients       types.ErrorCode = "NO_RECIPIENTS"
+
+	CodeAuthRequired types.ErrorCode = "AUTH_REQUIRED"
+	CodeAuthInvalid  types.ErrorCode = "AUTH_INVALID"
+	CodeAuthExpired  types.ErrorCode = "AUTH_EXPIRED"
+	CodeAuthReplayed types.ErrorCode = "AUTH_REPLAYED"
+
+	CodeForbidden             types.ErrorCode = "FORBIDDEN"
+	CodeParticipantNotAllowed types.ErrorCode = "PARTICIPANT_NOT_ALLOWED"
+	CodeParticipantLimit      types.ErrorCode = "PARTICIPANT_LIMIT_EXCEEDED"
+	CodeRiskLimit             types.ErrorCode = "RISK_LIMIT_EXCEEDED"
+	CodeNotRecipient          types.ErrorCode = "NOT_RECIPIENT"
+	CodeNotRegistryAdmin      types.ErrorCode = "NOT_REGISTRY_ADMIN"
+	CodeNotNodeAdmin          types.ErrorCode = "NOT_NODE_ADMIN"
+
+	CodeNotFound            types.ErrorCode = "NOT_FOUND"
+	CodeRFQNotFound         types.ErrorCode = "RFQ_NOT_FOUND"
+	CodeQuoteNotFound       types.ErrorCode = "QUOTE_NOT_FOUND"
+	CodeBlockNotFound       types.ErrorCode = "BLOCK_NOT_FOUND"
+	CodeTxNotFound          types.ErrorCode = "TX_NOT_FOUND"
+	CodeParticipantNotFound types.ErrorCode = "PARTICIPANT_NOT_FOUND"
+	CodeWebhookNotFound     types.ErrorCode = "WEBHOOK_NOT_FOUND"
+	CodeDeadLetterNotFound  types.ErrorCode = "DEAD_LETTER_NOT_FOUND"
+	CodePeerNotFound        types.ErrorCode = "PEER_NOT_FOUND"
+	CodeNotEnabled          types.ErrorCode = "NOT_ENABLED"
+
+	CodeRFQClosed          types.ErrorCode = "RFQ_CLOSED"
+	CodeStaleParticipant   types.ErrorCode = "STALE_PARTICIPANT"
+	CodeIdempotencyReused  types.ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyPending types.ErrorCode = "IDEMPOTENCY_KEY_IN_USE"
+	CodeDuplicateQuote     types.ErrorCode = "DUPLICATE_QUOTE"
+	CodeWebhookLimit       types.ErrorCode = "WEBHOOK_LIMIT_EXCEEDED"
+	CodeNotValidator       types.ErrorCode = "NOT_VALIDATOR"
+
+	CodeRateLimited    types.ErrorCode = "RATE_LIMITED"
+	CodeInternal       types.ErrorCode = "INTERNAL"
+	CodeNotImplemented types.ErrorCode = "NOT_IMPLEMENTED"
+)
+
+var (
+	errRFQClosed          = errors.New("RFQ is no longer open")
+	errPricesNotSealed    = errors.New("quote prices must be sealed to the encryption key")
+	errRiskNotEnabled     = errors.New("risk checks are not enabled")
+	errRiskNotVisible     = errors.New("risk utilisation is only visible to the requestor")
+	errEncryptionDisabled = errors.New("quote encryption is not enabled")
+	errQuoteNotVisible    = errors.New("quote is not visible to the caller")
+	errUnknownParticipant = errors.New("participant not found")
+)
+
+// APIError is the body of error responses. Code is one of the stable codes
+// of the catalogue, Error is meant for people and keeps the key of earlier
+// releases, and Details lists the fields of a request that failed validation.
+type APIError struct {
+	Code    types.ErrorCode     `json:"code"`
+	Error   string              `json:"Error"`
+	Details []*types.FieldError `json:"details,omitempty"`
+}
+
+// catalogueEntry is the code and HTTP status an error is reported with.
+type catalogueEntry struct {
+	err    error
+	code   types.ErrorCode
+	status int
+}
+
+// errorCatalogue lists the errors the API reports with their own code, it is
+// matched with errors.Is in order.
+var errorCatalogue = []catalogueEntry{
+	{errInvalidCursor, CodeInvalidCursor, http.StatusBadRequest},
+	{core.ErrInvalidCursor, CodeInvalidCursor, http.StatusBadRequest},
+	{tokens.ErrUnknownToken, CodeTokenNotListed, http.StatusBadRequest},
+	{tokens.ErrTokenMismatch, CodeTokenMismatch, http.StatusBadRequest},
+	{types.ErrQuoteTokenMismatch, CodeQuoteTokenMismatch, http.StatusBadRequest},
+	{types.ErrQuoteAmount, CodeQuoteAmount, http.StatusBadRequest},
+	{types.ErrQuoteNotSealed, CodeQuoteNotSealed, http.StatusBadRequest},
+	{errPricesNotSealed, CodeQuoteNotSealed, http.StatusBadRequest},
+	{types.ErrUnknownDealerGroup, CodeUnknownDealerGroup, http.StatusBadRequest},
+	{types.ErrNoRecipients, CodeNoRecipients, http.StatusBadRequest},
+	{errWebhookURL, CodeInvalidRequest, http.StatusBadRequest},
+	{errWebhookEvent, CodeInvalidRequest, http.StatusBadRequest},
+	{errBodyTooLarge, CodeInvalidRequest, http.StatusRequestEntityTooLarge},
+
+	{errAuthRequired, CodeAuthRequired, http.StatusUnauthorized},
+	{errAuthHeaders, CodeAuthInvalid, http.StatusUnauthorized},
+	{errAuthSignature, CodeAuthInvalid, http.StatusUnauthorized},
+	{errAuthTimestamp, CodeAuthExpired, http.StatusUnauthorized},
+	{errAuthReplayed, CodeAuthReplayed, http.StatusUnauthorized},
+
+	{core.ErrParticipantNotFound, CodeParticipantNotAllowed, http.StatusForbidden},
+	{types.ErrParticipantInactive, CodeParticipantNotAllowed, http.StatusForbidden},
+	{types.ErrParticipantExpired, CodeParticipantNotAllowed, http.StatusForbidden},
+	{types.ErrParticipantRole, CodeParticipantNotAllowed, http.StatusForbidden},
+	{types.ErrParticipantLimit, CodeParticipantLimit, http.StatusForbidden},
+	{risk.ErrRFQAmount, CodeRiskLimit, http.StatusForbidden},
+	{risk.ErrOpenAmount, CodeRiskLimit, http.StatusForbidden},
+	{risk.ErrDailyAmount, CodeRiskLimit, http.StatusForbidden},
+	{risk.ErrPairAmount, CodeRiskLimit, http.StatusForbidden},
+	{types.ErrNotRecipient, CodeNotRecipient, http.StatusForbidden},
+	{core.ErrNotRegistryAdmin, CodeNotRegistryAdmin, http.StatusForbidden},
+	{types.ErrParticipantNotSigned, CodeNotRegistryAdmin, http.StatusForbidden},
+	{errRiskNotVisible, CodeForbidden, http.StatusForbidden},
+	{errQuoteNotVisible, CodeForbidden, http.StatusForbidden},
+	{errNotNodeAdmin, CodeNotNodeAdmin, http.StatusForbidden},
+
+	{core.ErrRFQNotFound, CodeRFQNotFound, http.StatusNotFound},
+	{types.ErrQuoteNotFound, CodeQuoteNotFound, http.StatusNotFound},
+	{core.ErrBlockNotFound, CodeBlockNotFound, http.StatusNotFound},
+	{core.ErrTxNotFound, CodeTxNotFound, http.StatusNotFound},
+	{errUnknownParticipant, CodeParticipantNotFound, http.StatusNotFound},
+	{errWebhookNotFound, CodeWebhookNotFound, http.StatusNotFound},
+	{errDeadLetterMissing, CodeDeadLetterNotFound, http.StatusNotFound},
+	{ErrPeerNotFound, CodePeerNotFound, http.StatusNotFound},
+	{errRiskNotEnabled, CodeNotEnabled, http.StatusNotFound},
+	{errEncryptionDisabled, CodeNotEnabled, http.StatusNotFound},
+
+	{errRFQClosed, CodeRFQClosed, http.StatusConflict},
+	{core.ErrStaleParticipant, CodeStaleParticipant, http.StatusConflict},
+	{errIdempotencyMismatch, CodeIdempotencyReused, http.StatusConflict},
+	{errIdempotencyInFlight, CodeIdempotencyPending, http.StatusConflict},
+	{errDuplicateQuote, CodeDuplicateQuote, http.StatusConflict},
+	{ErrNotValidator, CodeNotValidator, http.StatusConflict},
+	{errWebhookLimit, CodeWebhookLimit, http.StatusConflict},
+
+	{errRateLimited, CodeRateLimited, http.StatusTooManyRequests},
+	{errNoRFQIndex, CodeNotImplemented, http.StatusNotImplemented},
+	{errNoMarketData, CodeNotImplemented, http.StatusNotImplemented},
+	{errNoNodeAdmin, CodeNotImplemented, http.StatusNotImplemented},
+}
+
+// apiError returns the status and body of the response reporting err. Errors
+// that are not in the catalogue or carry no code of core/types are reported
+// with status and the code of that status.
+func apiError(status int, err error) (int, APIError) {
+	res := APIError{Error: err.Error()}
+	for _, entry := range errorCatalogue {
+		if errors.Is(err, entry.err) {
+			res.Code = entry.code
+			return entry.status, res
+		}
+	}
+
+	var (
+		invalid  types.ValidationErrors
+		fieldErr *types.FieldError
+		coded    *types.Error
+	)
+	switch {
+	case errors.As(err, &invalid):
+		res.Code, res.Details = CodeValidationFailed, invalid
+		return http.StatusBadRequest, res
+	case errors.As(err, &fieldErr):
+		res.Code, res.Details = CodeValidationFailed, []*types.FieldError{fieldErr}
+		return http.StatusBadRequest, res
+	case errors.As(err, &coded):
+		res.Code = coded.Code
+		return http.StatusBadRequest, res
+	}
+	res.Code = statusCode(status)
+	return status, res
+}
+
+// statusCode is the code of errors outside the catalogue reported with
+// status.
+func statusCode(status int) types.ErrorCode {
+	switch {
+	case status == http.StatusUnauthorized:
+		return CodeAuthInvalid
+	case status == http.StatusForbidden:
+		return CodeForbidden
+	case status == http.StatusNotFound:
+		return CodeNotFound
+	case status == http.StatusTooManyRequests:
+		return CodeRateLimited
+	case status == http.StatusNotImplemented:
+		return CodeNotImplemented
+	case status >= http.StatusInternalServerError:
+		return CodeInternal
+	}
+	return CodeInvalidRequest
+}
+
+// writeError writes the response reporting err, status is used when err is
+// not in the catalogue.
+func writeError(c echo.Context, status int, err error) error {
+	status, res := apiError(status, err)
+	return c.JSON(status, res)
+}
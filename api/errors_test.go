@@ -0,0 +1,79 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/OCAX-labs/rfqrelayer/risk"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIError(t *testing.T) {
+	for _, tc := range []struct {
+		err    error
+		status int
+		code   types.ErrorCode
+	}{
+		{fmt.Errorf("openRFQ with hash [01]: %w", core.ErrRFQNotFound), http.StatusNotFound, CodeRFQNotFound},
+		{fmt.Errorf("%w: 10 > 5", risk.ErrRFQAmount), http.StatusForbidden, CodeRiskLimit},
+		{errAuthReplayed, http.StatusUnauthorized, CodeAuthReplayed},
+		{errRFQClosed, http.StatusConflict, CodeRFQClosed},
+		{fmt.Errorf("%w: 0x01", types.ErrInvalidChecksum), http.StatusBadRequest, types.CodeInvalidChecksum},
+		{types.ErrInvalidSig, http.StatusBadRequest, types.CodeInvalidSignature},
+		{errors.New("unexpected EOF"), http.StatusBadRequest, CodeInvalidRequest},
+	} {
+		status, res := apiError(http.StatusBadRequest, tc.err)
+		assert.Equal(t, tc.status, status, tc.err.Error())
+		assert.Equal(t, tc.code, res.Code, tc.err.Error())
+		assert.Equal(t, tc.err.Error(), res.Error)
+	}
+
+	// errors outside the catalogue take the code of the fallback status
+	status, res := apiError(http.StatusInternalServerError, errors.New("db closed"))
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.Equal(t, CodeInternal, res.Code)
+}
+
+func TestValidationErrorDetails(t *testing.T) {
+	s := NewServer(ServerConfig{}, &chainmocks.ChainInterface{}, nil)
+	e := echo.New()
+	e.POST("/rfqs", s.handlePostRFQRequest)
+
+	body, _ := json.Marshal(RFQRequestBody{Data: &types.SignableData{RFQDurationMs: 5000}})
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rfqs", bytes.NewReader(body)))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var res struct {
+		Code    types.ErrorCode `json:"code"`
+		Error   string          `json:"Error"`
+		Details []struct {
+			Field string          `json:"field"`
+			Code  types.ErrorCode `json:"code"`
+		} `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, CodeValidationFailed, res.Code)
+	assert.NotEmpty(t, res.Error)
+
+	fields := make(map[string]types.ErrorCode)
+	for _, detail := range res.Details {
+		fields[detail.Field] = detail.Code
+	}
+	assert.Equal(t, map[string]types.ErrorCode{
+		"requestorId":     types.CodeMissingField,
+		"baseTokenAmount": types.CodeMissingField,
+		"baseToken":       types.CodeMissingField,
+		"quoteToken":      types.CodeMissingField,
+	}, fields)
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+// defaultEventLogSize is how many events are kept for clients to resume
+// from when the server is not configured with a size.
+const defaultEventLogSize = 10000
+
+// EventStore persists the event log so it survives restarts.
+type EventStore interface {
+	WriteEvent(seq uint64, data []byte) error
+	DeleteEvent(seq uint64) error
+	GetEvents() ([][]byte, error)
+}
+
+// eventLog numbers lifecycle events and keeps the most recent ones so
+// clients that reconnect can catch up on the events they missed. Appending
+// and attaching clients share a lock so a resuming client gets every event
+// exactly once, either from the log or live.
+type eventLog struct {
+	mu     sync.Mutex
+	store  EventStore
+	size   int
+	seq    uint64
+	events []*event
+}
+
+func newEventLog(store EventStore, size int) *eventLog {
+	return &eventLog{store: store, size: size}
+}
+
+// append numbers ev, stores it and publishes it to the hub.
+func (l *eventLog) append(ev *event, hub *eventHub) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ev.Seq = l.seq + 1
+	msg, err := json.Marshal(ev.Event)
+	if err != nil {
+		return err
+	}
+	ev.msg = msg
+	if l.store != nil {
+		if err := l.store.WriteEvent(ev.Seq, msg); err != nil {
+			return err
+		}
+	}
+	l.seq = ev.Seq
+
+	l.events = append(l.events, ev)
+	if len(l.events) > l.size {
+		oldest := l.events[0]
+		l.events[0] = nil
+		l.events = l.events[1:]
+		if l.store != nil {
+			if err := l.store.DeleteEvent(oldest.Seq); err != nil {
+				return err
+			}
+		}
+	}
+
+	hub.publish(ev)
+	return nil
+}
+
+// attach registers sub with the hub once it has been sent the events after
+// since it subscribed to. A nil since attaches it to live events only.
+func (l *eventLog) attach(sub *subscriber, since *uint64, hub *eventHub) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if since != nil {
+		if len(l.events) > 0 && *since+1 < l.events[0].Seq {
+			sub.queueJSON(&WSAck{
+				Type:  messageError,
+				Op:    opResume,
+				Error: fmt.Sprintf("events before %d are no longer available", l.events[0].Seq),
+			})
+		}
+		for _, ev := range l.events {
+			if ev.Seq > *since && sub.receives(ev) {
+				sub.queueEvent(ev)
+			}
+		}
+	}
+	hub.register(sub)
+}
+
+// backlog counts the events after since, an upper bound of what attach
+// replays.
+func (l *eventLog) backlog(since uint64) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.seq <= since {
+		return 0
+	}
+	if n := l.seq - since; n < uint64(len(l.events)) {
+		return int(n)
+	}
+	return len(l.events)
+}
+
+// load restores the log from the store, rebuilding each event's routing from
+// its data.
+func (s *Server) loadEvents() error {
+	if s.EventStore == nil {
+		return nil
+	}
+	stored, err := s.EventStore.GetEvents()
+	if err != nil {
+		return err
+	}
+	for _, data := range stored {
+		var e Event
+		if err := json.Unmarshal(data, &e); err != nil {
+			return fmt.Errorf("error decoding event: %w", err)
+		}
+		ev, err := s.restoreEvent(&e)
+		if err != nil {
+			return fmt.Errorf("error restoring event %d: %w", e.Seq, err)
+		}
+		ev.Seq = e.Seq
+		ev.msg = data
+		s.events.events = append(s.events.events, ev)
+		s.events.seq = e.Seq
+	}
+	if n := len(s.events.events); n > s.events.size {
+		s.events.events = s.events.events[n-s.events.size:]
+	}
+	return nil
+}
+
+func (s *Server) restoreEvent(e *Event) (*event, error) {
+	switch e.Kind {
+	case EventRFQOpened, EventRFQClosed, EventRFQSettled:
+		data := new(types.RFQData)
+		if err := json.Unmarshal(e.Data, data); err != nil {
+			return nil, err
+		}
+		return s.rfqEvent(data)
+	case EventQuoteReceived:
+		data := new(types.QuoteData)
+		if err := json.Unmarshal(e.Data, data); err != nil {
+			return nil, err
+		}
+		if e.From == nil {
+			return nil, fmt.Errorf("quote event without sender")
+		}
+		return s.quoteEvent(*e.From, data)
+	case EventAuctionMatched:
+		result := new(types.MatchResult)
+		if err := json.Unmarshal(e.Data, result); err != nil {
+			return nil, err
+		}
+		return s.matchEvent(result)
+	}
+	return nil, fmt.Errorf("unknown event kind %q", e.Kind)
+}
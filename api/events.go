@@ -0,0 +1,225 @@
+package api
+
+import (
+	"encoding/json"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+// EventKind identifies an RFQ lifecycle event.
+type EventKind string
+
+const (
+	EventRFQOpened      EventKind = "rfq.opened"
+	EventQuoteReceived  EventKind = "quote.received"
+	EventRFQClosed      EventKind = "rfq.closed"
+	EventAuctionMatched EventKind = "auction.matched"
+	EventRFQSettled     EventKind = "rfq.settled"
+)
+
+// Event is the envelope lifecycle events are pushed to clients in. Data is
+// the RFQData of RFQ events, the QuoteData of quotes and the MatchResult of
+// matched auctions.
+type Event struct {
+	Type string `json:"type"`
+	// Seq numbers the events in the order they happened, clients resume
+	// from the last one they received
+	Seq       uint64      `json:"seq"`
+	Kind      EventKind   `json:"kind"`
+	RFQTxHash common.Hash `json:"rfqTxHash"`
+	// From is the address that sent the quote of quote events
+	From *common.Address `json:"from,omitempty"`
+	Data json.RawMessage `json:"data"`
+}
+
+// event is a lifecycle event with what is needed to route it to
+// subscribers.
+type event struct {
+	Event
+	pair pair
+	// audience selects the callers allowed to receive the event
+	audience func(*Caller) bool
+	// msg is the encoded Event, set once the event is numbered
+	msg []byte
+	// payload is the value encoded in Data
+	payload interface{}
+}
+
+type pair struct {
+	base, quote common.Address
+}
+
+func tokenPair(base, quote *types.Token) pair {
+	var p pair
+	if base != nil {
+		p.base = base.Address
+	}
+	if quote != nil {
+		p.quote = quote.Address
+	}
+	return p
+}
+
+func newEvent(kind EventKind, rfqTxHash common.Hash, data interface{}) (*event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &event{
+		Event:    Event{Type: messageEvent, Kind: kind, RFQTxHash: rfqTxHash, Data: raw},
+		audience: func(*Caller) bool { return true },
+		payload:  data,
+	}, nil
+}
+
+// rfqEvent returns the event for an open, closed or settled RFQ.
+func (s *Server) rfqEvent(data *types.RFQData) (*event, error) {
+	kind := EventRFQClosed
+	switch data.Status {
+	case types.RFQStatusOpen:
+		kind = EventRFQOpened
+	case types.RFQStatusSettled:
+		kind = EventRFQSettled
+	}
+	ev, err := newEvent(kind, data.RFQTxHash, data)
+	if err != nil {
+		return nil, err
+	}
+	if data.RFQRequest != nil {
+		ev.pair = tokenPair(data.RFQRequest.BaseToken, data.RFQRequest.QuoteToken)
+	}
+	ev.audience = s.rfqAudience(data)
+	return ev, nil
+}
+
+// quoteEvent returns the event for a quote sent by from. Quotes on private
+// RFQs only go to the RFQ's audience, and signed clients only receive the
+// quotes quoteVisible lets them see.
+func (s *Server) quoteEvent(from common.Address, data *types.QuoteData) (*event, error) {
+	ev, err := newEvent(EventQuoteReceived, data.RFQTxHash, data)
+	if err != nil {
+		return nil, err
+	}
+	ev.From = &from
+	ev.pair = tokenPair(data.BaseToken, data.QuoteToken)
+
+	rfqAudience := func(*Caller) bool { return true }
+	if openRFQ, err := s.bc.GetOpenRFQByHash(data.RFQTxHash); err == nil {
+		rfqAudience = s.rfqAudience(openRFQ.Data)
+	}
+	requestor := s.rfqRequestor(data.RFQTxHash)
+	quote := &types.Quote{From: from, Data: data}
+	ev.audience = func(caller *Caller) bool {
+		return rfqAudience(caller) && quoteVisible(caller, requestor, quote)
+	}
+	return ev, nil
+}
+
+// matchEvent returns the event for the result of a matched auction.
+func (s *Server) matchEvent(result *types.MatchResult) (*event, error) {
+	ev, err := newEvent(EventAuctionMatched, result.RFQTxHash, result)
+	if err != nil {
+		return nil, err
+	}
+	if closedRFQ, err := s.bc.GetClosedRFQByHash(result.RFQTxHash); err == nil && closedRFQ.Data != nil {
+		if closedRFQ.Data.RFQRequest != nil {
+			ev.pair = tokenPair(closedRFQ.Data.RFQRequest.BaseToken, closedRFQ.Data.RFQRequest.QuoteToken)
+		}
+		ev.audience = s.rfqAudience(closedRFQ.Data)
+	}
+	return ev, nil
+}
+
+// filterEvent returns ev as caller may see it: the quotes of a closed or
+// settled RFQ are narrowed to those visible to caller, as are the winning
+// quotes of a match result. It returns nil if the filtered event can't be
+// encoded.
+func (s *Server) filterEvent(caller *Caller, ev *event) *event {
+	var payload interface{}
+	switch data := ev.payload.(type) {
+	case *types.RFQData:
+		if len(data.Quotes) == 0 {
+			return ev
+		}
+		quotes := s.filterQuotes(caller, data.RFQTxHash, data.Quotes)
+		if len(quotes) == len(data.Quotes) {
+			return ev
+		}
+		filtered := *data
+		filtered.Quotes = quotes
+		payload = &filtered
+	case *types.MatchResult:
+		requestor := s.rfqRequestor(data.RFQTxHash)
+		visible := func(side *types.MatchedQuote) *types.MatchedQuote {
+			if side == nil || quoteVisible(caller, requestor, &types.Quote{From: side.Quoter}) {
+				return side
+			}
+			return nil
+		}
+		bestBid, bestAsk := visible(data.BestBid), visible(data.BestAsk)
+		if bestBid == data.BestBid && bestAsk == data.BestAsk {
+			return ev
+		}
+		filtered := *data
+		filtered.BestBid, filtered.BestAsk = bestBid, bestAsk
+		payload = &filtered
+	default:
+		return ev
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		s.Logger.Log("level", "error", "msg", "error encoding filtered event", "err", err)
+		return nil
+	}
+	filtered := *ev
+	filtered.Data = raw
+	filtered.payload = payload
+	if filtered.msg, err = json.Marshal(filtered.Event); err != nil {
+		s.Logger.Log("level", "error", "msg", "error encoding filtered event", "err", err)
+		return nil
+	}
+	return &filtered
+}
+
+// BroadcastTx publishes the lifecycle event of an RFQ or quote transaction
+// to the subscribed clients.
+func (s *Server) BroadcastTx(tx *types.Transaction, txType byte) {
+	var ev *event
+	var err error
+	switch txType {
+	case types.OpenRFQTxType:
+		ev, err = s.rfqEvent(tx.EmbeddedData().(*types.RFQData))
+	case types.QuoteTxType:
+		ev, err = s.quoteEvent(*tx.From(), tx.EmbeddedData().(*types.QuoteData))
+	default:
+		s.Logger.Log("level", "error", "broadcast error", "invalid tx type")
+		return
+	}
+	if err != nil {
+		s.Logger.Log("level", "error", "broadcast error", err)
+		return
+	}
+	s.publish(ev)
+}
+
+// BroadcastMatchResult publishes the result of a matched auction.
+func (s *Server) BroadcastMatchResult(result *types.MatchResult) {
+	ev, err := s.matchEvent(result)
+	if err != nil {
+		s.Logger.Log("level", "error", "broadcast error", err)
+		return
+	}
+	s.publish(ev)
+}
+
+// publish appends ev to the event log and sends it to the subscribed
+// clients.
+func (s *Server) publish(ev *event) {
+	if err := s.events.append(ev, s.hub); err != nil {
+		s.Logger.Log("level", "error", "broadcast error", err)
+		return
+	}
+	s.dispatchWebhooks(ev)
+}
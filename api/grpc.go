@@ -0,0 +1,364 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/api/pb"
+	"github.com/OCAX-labs/rfqrelayer/common"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/runtime/protoiface"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Metadata keys carrying a signed gRPC call, the auth headers in lower case.
+var (
+	metadataAuthAddress   = strings.ToLower(HeaderAuthAddress)
+	metadataAuthTimestamp = strings.ToLower(HeaderAuthTimestamp)
+	metadataAuthSignature = strings.ToLower(HeaderAuthSignature)
+)
+
+type grpcCallerKey struct{}
+
+// GRPCRequestHash returns the hash a caller signs to authenticate a gRPC
+// call: the RequestHash of a POST to the full method name with the
+// deterministic encoding of the request message as the body.
+func GRPCRequestHash(fullMethod string, req proto.Message, timestamp int64) (common.Hash, error) {
+	body, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return RequestHash(http.MethodPost, fullMethod, body, timestamp), nil
+}
+
+// SignGRPCRequest returns ctx with the metadata signing a call of fullMethod
+// with req by key.
+func SignGRPCRequest(ctx context.Context, fullMethod string, req proto.Message, key cryptoocax.PrivateKey) (context.Context, error) {
+	timestamp := time.Now().UnixNano() / int64(time.Millisecond)
+	hash, err := GRPCRequestHash(fullMethod, req, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.Sign(hash.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx,
+		metadataAuthAddress, key.PublicKey().Address().Hex(),
+		metadataAuthTimestamp, strconv.FormatInt(timestamp, 10),
+		metadataAuthSignature, hexutil.Encode(sig.ToBytes()),
+	), nil
+}
+
+// grpcCaller authenticates a call from its metadata, returning nil for
+// anonymous calls.
+func (s *Server) grpcCaller(ctx context.Context, fullMethod string, req interface{}) (*Caller, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	get := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	address := get(metadataAuthAddress)
+	if address == "" && get(metadataAuthSignature) == "" {
+		if s.RequireAuth {
+			return nil, status.Error(codes.Unauthenticated, errAuthRequired.Error())
+		}
+		return nil, nil
+	}
+
+	timestamp, err := strconv.ParseInt(get(metadataAuthTimestamp), 10, 64)
+	if err != nil || !common.IsHexAddress(address) {
+		return nil, status.Error(codes.Unauthenticated, errAuthHeaders.Error())
+	}
+	sig, err := hexutil.Decode(get(metadataAuthSignature))
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, errAuthHeaders.Error())
+	}
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, status.Error(codes.Internal, "request is not a protobuf message")
+	}
+	hash, err := GRPCRequestHash(fullMethod, msg, timestamp)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	caller, err := s.verifyCaller(common.HexToAddress(address), hash, timestamp, sig)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	return caller, nil
+}
+
+func (s *Server) grpcAuthUnary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	caller, err := s.grpcCaller(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	return handler(context.WithValue(ctx, grpcCallerKey{}, caller), req)
+}
+
+func (s *Server) grpcAuthStream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	return handler(srv, &authServerStream{ServerStream: ss, s: s, fullMethod: info.FullMethod, ctx: ss.Context()})
+}
+
+// authServerStream authenticates a server-streaming call once its request
+// has been received, the signature covering the request.
+type authServerStream struct {
+	grpc.ServerStream
+	s          *Server
+	fullMethod string
+	ctx        context.Context
+	authed     bool
+}
+
+func (ss *authServerStream) Context() context.Context {
+	return ss.ctx
+}
+
+func (ss *authServerStream) RecvMsg(m interface{}) error {
+	if err := ss.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if !ss.authed {
+		caller, err := ss.s.grpcCaller(ss.ctx, ss.fullMethod, m)
+		if err != nil {
+			return err
+		}
+		ss.ctx = context.WithValue(ss.ctx, grpcCallerKey{}, caller)
+		ss.authed = true
+	}
+	return nil
+}
+
+func grpcCallerFrom(ctx context.Context) *Caller {
+	caller, _ := ctx.Value(grpcCallerKey{}).(*Caller)
+	return caller
+}
+
+func grpcPeerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+		return host
+	}
+	return p.Addr.String()
+}
+
+// errorDomain is the domain of the ErrorInfo of rejected submissions.
+const errorDomain = "rfqrelayer"
+
+// grpcSubmitError reports a rejected submission with the code matching its
+// HTTP status, and the error code of the catalogue as the reason of its
+// ErrorInfo.
+func grpcSubmitError(err *submitError) error {
+	code := codes.InvalidArgument
+	switch err.status {
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusConflict:
+		code = codes.FailedPrecondition
+	}
+	details := []protoiface.MessageV1{&errdetails.ErrorInfo{Reason: string(err.res.Code), Domain: errorDomain}}
+	if err.status == http.StatusTooManyRequests {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(err.retryAfter)})
+	}
+	st, detailsErr := status.New(code, err.Error()).WithDetails(details...)
+	if detailsErr != nil {
+		return status.Error(code, err.Error())
+	}
+	return st.Err()
+}
+
+// NewGRPCServer returns a gRPC server serving the Relayer service.
+func (s *Server) NewGRPCServer() *grpc.Server {
+	gs := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.grpcAuthUnary),
+		grpc.ChainStreamInterceptor(s.grpcAuthStream),
+	)
+	pb.RegisterRelayerServer(gs, &relayerService{s: s})
+	return gs
+}
+
+// StartGRPC serves the gRPC API on GRPCListenAddr until Shutdown.
+func (s *Server) StartGRPC() error {
+	lis, err := net.Listen("tcp", s.GRPCListenAddr)
+	if err != nil {
+		return err
+	}
+	gs := s.NewGRPCServer()
+	s.mu.Lock()
+	if s.shuttingDown() {
+		s.mu.Unlock()
+		lis.Close()
+		return nil
+	}
+	s.grpc = gs
+	s.mu.Unlock()
+	return gs.Serve(lis)
+}
+
+// relayerService implements pb.RelayerServer over the API server, sharing
+// its submission checks, visibility rules and event feed.
+type relayerService struct {
+	pb.UnimplementedRelayerServer
+	s *Server
+}
+
+func (r *relayerService) SubmitRFQ(ctx context.Context, req *pb.SubmitRFQRequest) (*pb.SubmitResponse, error) {
+	from, err := addressFromPB(req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	data, err := rfqFromPB(req.Data)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	const endpoint = http.MethodPost + " /rfqs"
+	if err := r.s.limitSubmissionIP(endpoint, grpcPeerIP(ctx)); err != nil {
+		return nil, grpcSubmitError(err)
+	}
+	tx, serr := r.s.submitRFQRequest(endpoint, &RFQRequestBody{
+		From:            from.Hex(),
+		Data:            data,
+		SignatureString: hex.EncodeToString(req.Signature),
+	})
+	if serr != nil {
+		return nil, grpcSubmitError(serr)
+	}
+	return &pb.SubmitResponse{TxHash: tx.Hash().Bytes()}, nil
+}
+
+func (r *relayerService) SubmitQuote(ctx context.Context, req *pb.SubmitQuoteRequest) (*pb.SubmitResponse, error) {
+	from, err := addressFromPB(req.From)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	data, err := quoteDataFromPB(req.Data)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	const endpoint = http.MethodPost + " /quotes"
+	if err := r.s.limitSubmissionIP(endpoint, grpcPeerIP(ctx)); err != nil {
+		return nil, grpcSubmitError(err)
+	}
+	tx, _, serr := r.s.submitQuote(endpoint, &QuoteBody{
+		From:            from.Hex(),
+		Data:            data,
+		SignatureString: hex.EncodeToString(req.Signature),
+	})
+	if serr != nil {
+		return nil, grpcSubmitError(serr)
+	}
+	return &pb.SubmitResponse{TxHash: tx.Hash().Bytes()}, nil
+}
+
+func (r *relayerService) GetOpenRFQs(ctx context.Context, req *pb.GetOpenRFQsRequest) (*pb.GetOpenRFQsResponse, error) {
+	rfqs, err := r.s.bc.GetOpenRFQRequests()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	res := new(pb.GetOpenRFQsResponse)
+	for _, rfq := range r.s.visibleRFQs(grpcCallerFrom(ctx), rfqs) {
+		res.Rfqs = append(res.Rfqs, openRFQToPB(rfq.From, rfq.Data))
+	}
+	return res, nil
+}
+
+func (r *relayerService) GetQuotes(ctx context.Context, req *pb.GetQuotesRequest) (*pb.GetQuotesResponse, error) {
+	rfqTxHash, err := hashFromPB(req.RfqTxHash)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	quotes, err := r.s.bc.GetAuctionQuotes(rfqTxHash)
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return &pb.GetQuotesResponse{Quotes: quotesToPB(r.s.filterQuotes(grpcCallerFrom(ctx), rfqTxHash, quotes))}, nil
+}
+
+func (r *relayerService) GetBlock(ctx context.Context, req *pb.GetBlockRequest) (*pb.Block, error) {
+	block, err := r.s.bc.GetBlock(new(big.Int).SetUint64(req.Height))
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return blockToPB(block), nil
+}
+
+func (r *relayerService) SubscribeRFQs(req *pb.SubscribeRequest, stream pb.Relayer_SubscribeRFQsServer) error {
+	return r.stream(TopicRFQs, req, stream.Context(), func(ev *event) error {
+		msg, err := rfqEventToPB(ev)
+		if err != nil {
+			return err
+		}
+		return stream.Send(msg)
+	})
+}
+
+func (r *relayerService) SubscribeResults(req *pb.SubscribeRequest, stream pb.Relayer_SubscribeResultsServer) error {
+	return r.stream(TopicResults, req, stream.Context(), func(ev *event) error {
+		msg, err := resultEventToPB(ev)
+		if err != nil {
+			return err
+		}
+		return stream.Send(msg)
+	})
+}
+
+// stream subscribes the caller to topic and sends the events with send until
+// the client goes away. A gap in a resumed stream shows as a jump in seq.
+func (r *relayerService) stream(topic string, req *pb.SubscribeRequest, ctx context.Context, send func(*event) error) error {
+	sub := &WSRequest{Op: opSubscribe, Topic: topic}
+	var err error
+	if sub.BaseToken, err = optionalAddressFromPB(req.BaseToken); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if sub.QuoteToken, err = optionalAddressFromPB(req.QuoteToken); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	caller := grpcCallerFrom(ctx)
+	subscriber := r.s.subscribe(caller, []*WSRequest{sub}, req.Since, false, nil)
+	defer r.s.hub.unregister(subscriber)
+	defer subscriber.close()
+
+	for {
+		select {
+		case msg := <-subscriber.send:
+			if msg.ev == nil {
+				continue
+			}
+			if err := send(msg.ev); err != nil {
+				return err
+			}
+		case <-subscriber.done:
+			if r.s.shuttingDown() {
+				return status.Error(codes.Unavailable, errShuttingDown.Error())
+			}
+			return status.Error(codes.ResourceExhausted, errSlowClient.Error())
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
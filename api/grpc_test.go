@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/api/pb"
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func dialGRPC(t *testing.T, s *Server) pb.RelayerClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	gs := s.NewGRPCServer()
+	go gs.Serve(lis)
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewRelayerClient(conn)
+}
+
+func TestGRPCSubmitRFQ(t *testing.T) {
+	requestorKey := cryptoocax.GeneratePrivateKey()
+	addr := requestorKey.PublicKey().Address()
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("WriteRFQTxs", mock.Anything).Return(nil)
+	mockChain.On("GetParticipant", addr).Return(nil, assert.AnError)
+	mockChain.On("CheckParticipant", addr, types.RoleRequestor, big.NewInt(1000), mock.Anything).Return(nil)
+
+	txChan := make(chan *types.Transaction, 1)
+	client := dialGRPC(t, NewServer(ServerConfig{}, mockChain, txChan))
+
+	token := &types.Token{Symbol: "ETH", Decimals: 18, Address: common.HexToAddress("0x0d1d4e623D10F9FBA5Db95830F7d3839406C6AF2")}
+	data := &types.SignableData{
+		RequestorId:     addr.Hex(),
+		BaseTokenAmount: big.NewInt(1000),
+		BaseToken:       token,
+		QuoteToken:      token,
+		RFQDurationMs:   10_000,
+	}
+	sig, err := hex.DecodeString(signRFQRequest(t, requestorKey, data))
+	require.NoError(t, err)
+
+	req := &pb.SubmitRFQRequest{From: addr.Bytes(), Data: rfqToPB(data), Signature: sig}
+	res, err := client.SubmitRFQ(context.Background(), req)
+	require.NoError(t, err)
+	tx := <-txChan
+	assert.Equal(t, tx.Hash().Bytes(), res.TxHash)
+
+	// a request signed by another key is rejected by the shared checks
+	req.From = cryptoocax.GeneratePrivateKey().PublicKey().Address().Bytes()
+	_, err = client.SubmitRFQ(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	req.From = []byte{0x01}
+	_, err = client.SubmitRFQ(context.Background(), req)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCGetOpenRFQsAuthenticatesCaller(t *testing.T) {
+	dealerKey := cryptoocax.GeneratePrivateKey()
+	dealer := dealerKey.PublicKey().Address()
+	publicHash := common.HexToHash("0x01")
+	privateHash := common.HexToHash("0x02")
+	rfqs := []*types.OpenRFQ{
+		{Data: &types.RFQData{RFQTxHash: publicHash, RFQRequest: &types.SignableData{}}},
+		{Data: &types.RFQData{
+			RFQTxHash:  privateHash,
+			RFQRequest: &types.SignableData{Recipients: []common.Address{dealer}},
+			Recipients: []common.Address{dealer},
+		}},
+	}
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetOpenRFQRequests").Return(rfqs, nil)
+	mockChain.On("GetParticipant", dealer).Return(nil, assert.AnError)
+	client := dialGRPC(t, NewServer(ServerConfig{}, mockChain, nil))
+
+	hashes := func(res *pb.GetOpenRFQsResponse) []common.Hash {
+		var out []common.Hash
+		for _, rfq := range res.Rfqs {
+			out = append(out, common.BytesToHash(rfq.RfqTxHash))
+		}
+		return out
+	}
+
+	req := &pb.GetOpenRFQsRequest{}
+	res, err := client.GetOpenRFQs(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{publicHash}, hashes(res))
+
+	ctx, err := SignGRPCRequest(context.Background(), pb.Relayer_GetOpenRFQs_FullMethodName, req, dealerKey)
+	require.NoError(t, err)
+	res, err = client.GetOpenRFQs(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{publicHash, privateHash}, hashes(res))
+
+	// the signature can't be replayed
+	_, err = client.GetOpenRFQs(ctx, req)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestGRPCSubscribeRFQs(t *testing.T) {
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, &chainmocks.ChainInterface{}, nil)
+	client := dialGRPC(t, s)
+
+	mkr := common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	s.BroadcastTx(newOpenRFQTx(t, common.HexToHash("0x01"), mkr), types.OpenRFQTxType)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	since := uint64(0)
+	stream, err := client.SubscribeRFQs(ctx, &pb.SubscribeRequest{BaseToken: mkr.Bytes(), Since: &since})
+	require.NoError(t, err)
+
+	// the replayed event arrives first
+	ev, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), ev.Seq)
+	assert.Equal(t, common.HexToHash("0x01").Bytes(), ev.Rfq.RfqTxHash)
+
+	// live events follow, filtered to the pair
+	s.BroadcastTx(newOpenRFQTx(t, common.HexToHash("0x02"), weth), types.OpenRFQTxType)
+	s.BroadcastTx(newOpenRFQTx(t, common.HexToHash("0x03"), mkr), types.OpenRFQTxType)
+	ev, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), ev.Seq)
+	assert.Equal(t, mkr.Bytes(), ev.Rfq.Request.BaseToken.Address)
+}
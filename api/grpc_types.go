@@ -0,0 +1,281 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/OCAX-labs/rfqrelayer/api/pb"
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+)
+
+var (
+	errInvalidHash = errors.New("hashes must be 32 bytes")
+	errMissingRFQ  = errors.New("missing RFQ data")
+)
+
+// Conversions between the core types and their protobuf messages.
+
+func addressFromPB(b []byte) (common.Address, error) {
+	if len(b) != common.AddressLength {
+		return common.Address{}, types.ErrInvalidAddress
+	}
+	return common.BytesToAddress(b), nil
+}
+
+func optionalAddressFromPB(b []byte) (*common.Address, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	addr, err := addressFromPB(b)
+	if err != nil {
+		return nil, err
+	}
+	return &addr, nil
+}
+
+func hashFromPB(b []byte) (common.Hash, error) {
+	if len(b) != common.HashLength {
+		return common.Hash{}, errInvalidHash
+	}
+	return common.BytesToHash(b), nil
+}
+
+func addressesToPB(addrs []common.Address) [][]byte {
+	if len(addrs) == 0 {
+		return nil
+	}
+	out := make([][]byte, len(addrs))
+	for i, addr := range addrs {
+		out[i] = addr.Bytes()
+	}
+	return out
+}
+
+func bigToPB(n *big.Int) string {
+	if n == nil {
+		return ""
+	}
+	return n.String()
+}
+
+func bigFromPB(s string) (*big.Int, error) {
+	if s == "" {
+		return nil, nil
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, types.ErrInvalidAmount
+	}
+	return n, nil
+}
+
+func tokenToPB(t *types.Token) *pb.Token {
+	if t == nil {
+		return nil
+	}
+	return &pb.Token{Address: t.Address.Bytes(), Symbol: t.Symbol, Decimals: t.Decimals}
+}
+
+func tokenFromPB(t *pb.Token) (*types.Token, error) {
+	if t == nil {
+		return nil, nil
+	}
+	addr, err := addressFromPB(t.Address)
+	if err != nil {
+		return nil, err
+	}
+	return &types.Token{Address: addr, Symbol: t.Symbol, Decimals: t.Decimals}, nil
+}
+
+func rfqToPB(d *types.SignableData) *pb.RFQ {
+	if d == nil {
+		return nil
+	}
+	return &pb.RFQ{
+		RequestorId:     d.RequestorId,
+		BaseTokenAmount: bigToPB(d.BaseTokenAmount),
+		BaseToken:       tokenToPB(d.BaseToken),
+		QuoteToken:      tokenToPB(d.QuoteToken),
+		RfqDurationMs:   d.RFQDurationMs,
+		Recipients:      addressesToPB(d.Recipients),
+		DealerGroup:     d.DealerGroup,
+	}
+}
+
+func rfqFromPB(r *pb.RFQ) (*types.SignableData, error) {
+	if r == nil {
+		return nil, errMissingRFQ
+	}
+	d := &types.SignableData{
+		RequestorId:   r.RequestorId,
+		RFQDurationMs: r.RfqDurationMs,
+		DealerGroup:   r.DealerGroup,
+	}
+	var err error
+	if d.BaseTokenAmount, err = bigFromPB(r.BaseTokenAmount); err != nil {
+		return nil, err
+	}
+	if d.BaseToken, err = tokenFromPB(r.BaseToken); err != nil {
+		return nil, err
+	}
+	if d.QuoteToken, err = tokenFromPB(r.QuoteToken); err != nil {
+		return nil, err
+	}
+	for _, b := range r.Recipients {
+		addr, err := addressFromPB(b)
+		if err != nil {
+			return nil, err
+		}
+		d.Recipients = append(d.Recipients, addr)
+	}
+	return d, nil
+}
+
+func quoteDataToPB(d *types.QuoteData) *pb.QuoteData {
+	if d == nil {
+		return nil
+	}
+	q := &pb.QuoteData{
+		QuoterId:        d.QuoterId,
+		RfqTxHash:       d.RFQTxHash.Bytes(),
+		QuoteExpiryTime: d.QuoteExpiryTime,
+		BaseToken:       tokenToPB(d.BaseToken),
+		QuoteToken:      tokenToPB(d.QuoteToken),
+		BaseTokenAmount: bigToPB(d.BaseTokenAmount),
+		BidPrice:        bigToPB(d.BidPrice),
+		AskPrice:        bigToPB(d.AskPrice),
+		EncryptedQuote:  d.EncryptedQuote,
+	}
+	for _, key := range d.EncryptionPublicKeys {
+		if key != nil {
+			q.EncryptionPublicKeys = append(q.EncryptionPublicKeys, *key)
+		}
+	}
+	return q
+}
+
+func quoteDataFromPB(q *pb.QuoteData) (*types.QuoteData, error) {
+	if q == nil {
+		return nil, errMissingData
+	}
+	rfqTxHash, err := hashFromPB(q.RfqTxHash)
+	if err != nil {
+		return nil, err
+	}
+	d := &types.QuoteData{
+		QuoterId:        q.QuoterId,
+		RFQTxHash:       rfqTxHash,
+		QuoteExpiryTime: q.QuoteExpiryTime,
+		EncryptedQuote:  q.EncryptedQuote,
+	}
+	if d.BaseToken, err = tokenFromPB(q.BaseToken); err != nil {
+		return nil, err
+	}
+	if d.QuoteToken, err = tokenFromPB(q.QuoteToken); err != nil {
+		return nil, err
+	}
+	if d.BaseTokenAmount, err = bigFromPB(q.BaseTokenAmount); err != nil {
+		return nil, err
+	}
+	if d.BidPrice, err = bigFromPB(q.BidPrice); err != nil {
+		return nil, err
+	}
+	if d.AskPrice, err = bigFromPB(q.AskPrice); err != nil {
+		return nil, err
+	}
+	for _, b := range q.EncryptionPublicKeys {
+		key := cryptoocax.PublicKey(b)
+		d.EncryptionPublicKeys = append(d.EncryptionPublicKeys, &key)
+	}
+	return d, nil
+}
+
+func quotesToPB(quotes []*types.Quote) []*pb.Quote {
+	out := make([]*pb.Quote, len(quotes))
+	for i, quote := range quotes {
+		out[i] = &pb.Quote{From: quote.From.Bytes(), Data: quoteDataToPB(quote.Data)}
+	}
+	return out
+}
+
+// openRFQToPB converts an open or closed RFQ, validator is the zero address
+// when it is not known.
+func openRFQToPB(validator common.Address, d *types.RFQData) *pb.OpenRFQ {
+	rfq := &pb.OpenRFQ{
+		RfqTxHash:          d.RFQTxHash.Bytes(),
+		Request:            rfqToPB(d.RFQRequest),
+		StartTime:          d.RFQStartTime,
+		EndTime:            d.RFQEndTime,
+		Quotes:             quotesToPB(d.Quotes),
+		SettlementContract: d.SettlementContract.Bytes(),
+		MatchingContract:   d.MatchingContract.Bytes(),
+		Status:             string(d.Status),
+		QuotesRoot:         d.QuotesRoot.Bytes(),
+		Recipients:         addressesToPB(d.Recipients),
+	}
+	if validator != (common.Address{}) {
+		rfq.Validator = validator.Bytes()
+	}
+	return rfq
+}
+
+func matchedQuoteToPB(q *types.MatchedQuote) *pb.MatchedQuote {
+	if q == nil {
+		return nil
+	}
+	return &pb.MatchedQuote{QuoteHash: q.QuoteHash.Bytes(), Quoter: q.Quoter.Bytes(), Price: bigToPB(q.Price)}
+}
+
+func matchResultToPB(m *types.MatchResult) *pb.MatchResult {
+	return &pb.MatchResult{
+		RfqTxHash:  m.RFQTxHash.Bytes(),
+		QuotesRoot: m.QuotesRoot.Bytes(),
+		Engine:     m.Engine,
+		BestBid:    matchedQuoteToPB(m.BestBid),
+		BestAsk:    matchedQuoteToPB(m.BestAsk),
+		Validator:  m.Validator.Bytes(),
+		Signature:  m.Signature,
+	}
+}
+
+func blockToPB(b *types.Block) *pb.Block {
+	block := &pb.Block{
+		Hash:       b.Hash().Bytes(),
+		Version:    b.Version(),
+		TxHash:     b.TxHash().Bytes(),
+		ParentHash: b.ParentHash().Bytes(),
+		Height:     b.HeightU64(),
+		Timestamp:  b.Timestamp(),
+		Validator:  b.Validator.Address().Bytes(),
+	}
+	for _, tx := range b.Transactions() {
+		block.TxHashes = append(block.TxHashes, tx.Hash().Bytes())
+	}
+	return block
+}
+
+// rfqEventToPB converts a streamed rfq.opened event, resultEventToPB the
+// rfq.closed and auction.matched events.
+func rfqEventToPB(ev *event) (*pb.RFQEvent, error) {
+	data, ok := ev.payload.(*types.RFQData)
+	if !ok {
+		return nil, fmt.Errorf("unexpected payload %T of %s event", ev.payload, ev.Kind)
+	}
+	return &pb.RFQEvent{Seq: ev.Seq, Rfq: openRFQToPB(common.Address{}, data)}, nil
+}
+
+func resultEventToPB(ev *event) (*pb.ResultEvent, error) {
+	res := &pb.ResultEvent{Seq: ev.Seq}
+	switch payload := ev.payload.(type) {
+	case *types.RFQData:
+		res.Result = &pb.ResultEvent_ClosedRfq{ClosedRfq: openRFQToPB(common.Address{}, payload)}
+	case *types.MatchResult:
+		res.Result = &pb.ResultEvent_MatchResult{MatchResult: matchResultToPB(payload)}
+	default:
+		return nil, fmt.Errorf("unexpected payload %T of %s event", ev.payload, ev.Kind)
+	}
+	return res, nil
+}
@@ -0,0 +1,204 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/labstack/echo/v4"
+)
+
+// Headers of idempotent submissions. A client sets IdempotencyKeyHeader on a
+// POST and sends the same key when it retries, a response answered from the
+// stored result carries IdempotentReplayedHeader.
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	defaultIdempotencyWindow = 24 * time.Hour
+	maxIdempotencyKeyLength  = 255
+	// maxIdempotencyKeys bounds the stored responses, requests are served
+	// without being stored while the store is full
+	maxIdempotencyKeys = 100000
+)
+
+var (
+	errIdempotencyKey      = errors.New("Idempotency-Key must be between 1 and 255 characters")
+	errIdempotencyMismatch = errors.New("Idempotency-Key was already used with a different request body")
+	errIdempotencyInFlight = errors.New("a request with this Idempotency-Key is still being processed")
+)
+
+// storedResponse is the result of a request made with an idempotency key.
+// Until the request completes done is false and the response is empty.
+type storedResponse struct {
+	bodyHash common.Hash
+	done     bool
+	status   int
+	header   http.Header
+	body     []byte
+	expires  time.Time
+}
+
+// idempotencyStore keeps the responses to requests made with an idempotency
+// key for the configured window.
+type idempotencyStore struct {
+	mu        sync.Mutex
+	window    time.Duration
+	responses map[string]*storedResponse
+}
+
+func newIdempotencyStore(window time.Duration) *idempotencyStore {
+	return &idempotencyStore{window: window, responses: make(map[string]*storedResponse)}
+}
+
+// begin looks up key. It returns the stored response of a completed request,
+// or nil after reserving the key for a new request the caller must complete
+// or release. ok is false when the key can't be reserved because the store
+// is full.
+func (st *idempotencyStore) begin(key string, bodyHash common.Hash, now time.Time) (stored *storedResponse, ok bool, err error) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if stored, found := st.responses[key]; found && now.Before(stored.expires) {
+		switch {
+		case stored.bodyHash != bodyHash:
+			return nil, false, errIdempotencyMismatch
+		case !stored.done:
+			return nil, false, errIdempotencyInFlight
+		}
+		return stored, true, nil
+	}
+
+	if len(st.responses) >= maxIdempotencyKeys {
+		st.prune(now)
+		if len(st.responses) >= maxIdempotencyKeys {
+			return nil, false, nil
+		}
+	}
+	st.responses[key] = &storedResponse{bodyHash: bodyHash, expires: now.Add(st.window)}
+	return nil, true, nil
+}
+
+// complete stores the response to the request that reserved key.
+func (st *idempotencyStore) complete(key string, status int, header http.Header, body []byte) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	if stored, ok := st.responses[key]; ok {
+		stored.done = true
+		stored.status = status
+		stored.header = header
+		stored.body = body
+	}
+}
+
+// release frees a key whose request failed so it can be retried.
+func (st *idempotencyStore) release(key string) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+
+	delete(st.responses, key)
+}
+
+// prune drops the expired responses.
+func (st *idempotencyStore) prune(now time.Time) {
+	for key, stored := range st.responses {
+		if !now.Before(stored.expires) {
+			delete(st.responses, key)
+		}
+	}
+}
+
+// responseRecorder copies what a handler writes so it can be stored.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// retryable reports whether a response must not be stored because the same
+// request may succeed when it is retried.
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// idempotent is the echo middleware of the POST endpoints answering a retry
+// carrying the Idempotency-Key of an earlier request with that request's
+// response. Keys are scoped to the endpoint and to the caller, or the client
+// IP of unsigned requests, and reusing one with another body is a conflict.
+// Rate limited and failed requests are not stored so they can be retried.
+func (s *Server) idempotent(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		if _, set := req.Header[IdempotencyKeyHeader]; !set {
+			return next(c)
+		}
+		key := req.Header.Get(IdempotencyKeyHeader)
+		if len(key) == 0 || len(key) > maxIdempotencyKeyLength {
+			return writeError(c, http.StatusBadRequest, errIdempotencyKey)
+		}
+
+		var body []byte
+		if req.Body != nil {
+			var err error
+			if body, err = io.ReadAll(req.Body); err != nil {
+				return writeError(c, http.StatusBadRequest, err)
+			}
+			req.Body = io.NopCloser(bytes.NewReader(body))
+		}
+
+		scope := c.RealIP()
+		if caller := callerFrom(c); caller != nil {
+			scope = caller.Address.Hex()
+		}
+		key = endpointKey(c) + " " + scope + " " + key
+
+		stored, ok, err := s.idempotency.begin(key, common.BytesToHash(cryptoocax.Keccak256Hash(body)), time.Now())
+		if err != nil {
+			return writeError(c, http.StatusConflict, err)
+		}
+		if !ok {
+			return next(c)
+		}
+		if stored != nil {
+			header := c.Response().Header()
+			for name, values := range stored.header {
+				header[name] = values
+			}
+			header.Set(IdempotentReplayedHeader, "true")
+			c.Response().WriteHeader(stored.status)
+			_, err := c.Response().Write(stored.body)
+			return err
+		}
+
+		res := c.Response()
+		rec := &responseRecorder{ResponseWriter: res.Writer, status: http.StatusOK}
+		res.Writer = rec
+		err = next(c)
+		res.Writer = rec.ResponseWriter
+
+		if err != nil || !res.Committed || retryable(rec.status) {
+			s.idempotency.release(key)
+			return err
+		}
+		s.idempotency.complete(key, rec.status, res.Header().Clone(), rec.body.Bytes())
+		return nil
+	}
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotentPost(t *testing.T) {
+	s := NewServer(ServerConfig{}, &chainmocks.ChainInterface{}, nil)
+
+	// the handler fails the first time it sees "retry" and echoes the body
+	calls := 0
+	failed := false
+	e := echo.New()
+	e.POST("/quotes", func(c echo.Context) error {
+		calls++
+		body, _ := io.ReadAll(c.Request().Body)
+		if string(body) == "retry" && !failed {
+			failed = true
+			return c.JSON(http.StatusServiceUnavailable, APIError{Error: "busy"})
+		}
+		return c.String(http.StatusAccepted, string(body))
+	}, s.idempotent)
+
+	post := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/quotes", strings.NewReader(body))
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post("k1", "quote")
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Empty(t, rec.Header().Get(IdempotentReplayedHeader))
+
+	// a retry gets the stored response without running the handler again
+	rec = post("k1", "quote")
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.Equal(t, "quote", rec.Body.String())
+	assert.Equal(t, "true", rec.Header().Get(IdempotentReplayedHeader))
+	assert.Equal(t, 1, calls)
+
+	// the key can't be reused for another request
+	assert.Equal(t, http.StatusConflict, post("k1", "other quote").Code)
+	assert.Equal(t, 1, calls)
+
+	// requests without a key or with a new key are always handled
+	post("", "quote")
+	post("k2", "quote")
+	assert.Equal(t, 3, calls)
+
+	// failures are not stored
+	assert.Equal(t, http.StatusServiceUnavailable, post("k3", "retry").Code)
+	assert.Equal(t, http.StatusAccepted, post("k3", "retry").Code)
+	assert.Equal(t, 5, calls)
+
+	req := httptest.NewRequest(http.MethodPost, "/quotes", nil)
+	req.Header.Set(IdempotencyKeyHeader, "")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestIdempotencyStore(t *testing.T) {
+	st := newIdempotencyStore(time.Minute)
+	now := time.Now()
+	body := common.HexToHash("0x01")
+
+	stored, ok, err := st.begin("k", body, now)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, stored)
+
+	// a retry while the first request is still being handled is a conflict
+	_, _, err = st.begin("k", body, now)
+	assert.ErrorIs(t, err, errIdempotencyInFlight)
+
+	st.complete("k", http.StatusAccepted, http.Header{}, []byte("ok"))
+	stored, _, err = st.begin("k", body, now)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("ok"), stored.body)
+
+	// responses are forgotten once the window has passed
+	stored, ok, err = st.begin("k", common.HexToHash("0x02"), now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, stored)
+}
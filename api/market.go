@@ -0,0 +1,212 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultStatsInterval = time.Hour
+	// maxStatsIntervals bounds the intervals of a history request
+	maxStatsIntervals = 1000
+)
+
+var (
+	errInvalidInterval = errors.New("interval must be a whole number of hours, e.g. 1h or 24h")
+	errStatsRange      = fmt.Errorf("a history covers at most %d intervals", maxStatsIntervals)
+	errNoMarketData    = errors.New("market data is not kept on this node")
+)
+
+// MarketData reads the market data aggregated from closed auctions.
+type MarketData interface {
+	GetMarketStats() ([]*core.PairStats, error)
+	GetPairStats(base, quote common.Address) (*core.PairStats, error)
+	GetPairStatsHistory(base, quote common.Address, from, to, interval uint64) ([]*core.PairStats, error)
+}
+
+// PairStats is the market data of a token pair over the life of the node, or
+// over one interval of its history. Only public RFQs are counted.
+type PairStats struct {
+	BaseToken  common.Address `json:"baseToken"`
+	QuoteToken common.Address `json:"quoteToken"`
+	// Time is the start of the interval in unix milliseconds, it is omitted
+	// from the totals
+	Time uint64 `json:"time,omitempty"`
+
+	RFQs            uint64  `json:"rfqs"`
+	QuotedRFQs      uint64  `json:"quotedRfqs"`
+	Quotes          uint64  `json:"quotes"`
+	AvgQuotesPerRFQ float64 `json:"avgQuotesPerRfq"`
+	// RequestedVolume is the base token amount of the closed RFQs and
+	// QuotedVolume that of the RFQs that received quotes
+	RequestedVolume *big.Int `json:"requestedVolume"`
+	QuotedVolume    *big.Int `json:"quotedVolume"`
+
+	// SpreadBps is the distribution of the spread between the best bid and
+	// ask of matched auctions, in basis points of the mid price
+	SpreadBps       []HistogramBucket `json:"spreadBps"`
+	ResponseLatency LatencyStats      `json:"responseLatencyMs"`
+}
+
+// HistogramBucket counts the values below its bound and at or above the
+// bound of the previous bucket. The last bucket has no bound.
+type HistogramBucket struct {
+	Below *int64 `json:"below,omitempty"`
+	Count uint64 `json:"count"`
+}
+
+// LatencyStats describes how long after an RFQ opened its quotes arrived,
+// in milliseconds.
+type LatencyStats struct {
+	Count   uint64            `json:"count"`
+	Avg     float64           `json:"avg"`
+	Min     uint64            `json:"min"`
+	Max     uint64            `json:"max"`
+	Buckets []HistogramBucket `json:"buckets"`
+}
+
+func histogram(bounds []int64, counts []uint64) []HistogramBucket {
+	buckets := make([]HistogramBucket, len(counts))
+	for i, count := range counts {
+		buckets[i].Count = count
+		if i < len(bounds) {
+			below := bounds[i]
+			buckets[i].Below = &below
+		}
+	}
+	return buckets
+}
+
+func newPairStats(stats *core.PairStats) *PairStats {
+	res := &PairStats{
+		BaseToken:       stats.BaseToken,
+		QuoteToken:      stats.QuoteToken,
+		Time:            stats.Time,
+		RFQs:            stats.RFQs,
+		QuotedRFQs:      stats.QuotedRFQs,
+		Quotes:          stats.Quotes,
+		RequestedVolume: stats.RequestedVolume,
+		QuotedVolume:    stats.QuotedVolume,
+		SpreadBps:       histogram(core.SpreadBoundsBps, stats.Spreads),
+	}
+	if stats.RFQs > 0 {
+		res.AvgQuotesPerRFQ = float64(stats.Quotes) / float64(stats.RFQs)
+	}
+
+	latencyBounds := make([]int64, len(core.LatencyBoundsMs))
+	for i, bound := range core.LatencyBoundsMs {
+		latencyBounds[i] = int64(bound)
+	}
+	res.ResponseLatency = LatencyStats{
+		Count:   stats.QuoteLatencies(),
+		Min:     stats.LatencyMinMs,
+		Max:     stats.LatencyMaxMs,
+		Buckets: histogram(latencyBounds, stats.Latencies),
+	}
+	if res.ResponseLatency.Count > 0 {
+		res.ResponseLatency.Avg = float64(stats.LatencySumMs) / float64(res.ResponseLatency.Count)
+	}
+	return res
+}
+
+// pairParams reads the token pair of a market data endpoint.
+func pairParams(c echo.Context) (common.Address, common.Address, error) {
+	base, quote := c.Param("baseToken"), c.Param("quoteToken")
+	if !common.IsHexAddress(base) || !common.IsHexAddress(quote) {
+		return common.Address{}, common.Address{}, types.ErrInvalidAddress
+	}
+	return common.HexToAddress(base), common.HexToAddress(quote), nil
+}
+
+func (s *Server) handleGetMarketStats(c echo.Context) error {
+	if s.MarketData == nil {
+		return writeError(c, http.StatusNotImplemented, errNoMarketData)
+	}
+	pairs, err := s.MarketData.GetMarketStats()
+	if err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	res := make([]*PairStats, 0, len(pairs))
+	for _, stats := range pairs {
+		res = append(res, newPairStats(stats))
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (s *Server) handleGetPairStats(c echo.Context) error {
+	if s.MarketData == nil {
+		return writeError(c, http.StatusNotImplemented, errNoMarketData)
+	}
+	base, quote, err := pairParams(c)
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	stats, err := s.MarketData.GetPairStats(base, quote)
+	if err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, newPairStats(stats))
+}
+
+// handleGetPairStatsHistory returns the market data of a pair per interval,
+// 1h unless given, over the time range from to to. The range defaults to the
+// last 24 intervals.
+func (s *Server) handleGetPairStatsHistory(c echo.Context) error {
+	if s.MarketData == nil {
+		return writeError(c, http.StatusNotImplemented, errNoMarketData)
+	}
+	base, quote, err := pairParams(c)
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+
+	interval := defaultStatsInterval
+	if value := c.QueryParam("interval"); value != "" {
+		interval, err = time.ParseDuration(value)
+		if err != nil || interval <= 0 || interval%time.Hour != 0 {
+			return writeError(c, http.StatusBadRequest, errInvalidInterval)
+		}
+	}
+	intervalMs := uint64(interval / time.Millisecond)
+
+	to := nowMs()
+	if value := c.QueryParam("to"); value != "" {
+		if to, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return writeError(c, http.StatusBadRequest, errInvalidTimeRange)
+		}
+	}
+	var from uint64
+	if to > 24*intervalMs {
+		from = to - 24*intervalMs
+	}
+	if value := c.QueryParam("from"); value != "" {
+		if from, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return writeError(c, http.StatusBadRequest, errInvalidTimeRange)
+		}
+	}
+	if from > to {
+		return writeError(c, http.StatusBadRequest, errInvalidTimeRange)
+	}
+	if (to-from)/intervalMs >= maxStatsIntervals {
+		return writeError(c, http.StatusBadRequest, errStatsRange)
+	}
+
+	history, err := s.MarketData.GetPairStatsHistory(base, quote, from, to, intervalMs)
+	if err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	res := make([]*PairStats, 0, len(history))
+	for _, stats := range history {
+		res = append(res, newPairStats(stats))
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeMarketData serves fixed stats and records the history requested.
+type fakeMarketData struct {
+	stats    *core.PairStats
+	from, to uint64
+	interval uint64
+}
+
+func (f *fakeMarketData) GetMarketStats() ([]*core.PairStats, error) {
+	return []*core.PairStats{f.stats}, nil
+}
+
+func (f *fakeMarketData) GetPairStats(base, quote common.Address) (*core.PairStats, error) {
+	return f.stats, nil
+}
+
+func (f *fakeMarketData) GetPairStatsHistory(base, quote common.Address, from, to, interval uint64) ([]*core.PairStats, error) {
+	f.from, f.to, f.interval = from, to, interval
+	return []*core.PairStats{f.stats}, nil
+}
+
+func TestMarketStatsEndpoints(t *testing.T) {
+	mkr := common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	stats := &core.PairStats{
+		BaseToken:       mkr,
+		QuoteToken:      usdc,
+		RFQs:            4,
+		QuotedRFQs:      3,
+		Quotes:          6,
+		RequestedVolume: big.NewInt(400),
+		QuotedVolume:    big.NewInt(300),
+		Spreads:         make([]uint64, len(core.SpreadBoundsBps)+1),
+		Latencies:       make([]uint64, len(core.LatencyBoundsMs)+1),
+		LatencySumMs:    900,
+		LatencyMinMs:    100,
+		LatencyMaxMs:    500,
+	}
+	stats.Spreads[len(core.SpreadBoundsBps)] = 2
+	stats.Latencies[1], stats.Latencies[3] = 1, 2
+	data := &fakeMarketData{stats: stats}
+
+	serve := func(s *Server, path string) *httptest.ResponseRecorder {
+		e := echo.New()
+		e.GET("/stats/pairs", s.handleGetMarketStats)
+		e.GET("/stats/pairs/:baseToken/:quoteToken", s.handleGetPairStats)
+		e.GET("/stats/pairs/:baseToken/:quoteToken/history", s.handleGetPairStatsHistory)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+	pairPath := "/stats/pairs/" + mkr.Hex() + "/" + usdc.Hex()
+
+	// nodes without market data say so
+	rec := serve(NewServer(ServerConfig{}, &chainmocks.ChainInterface{}, nil), "/stats/pairs")
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+
+	s := NewServer(ServerConfig{MarketData: data}, &chainmocks.ChainInterface{}, nil)
+	rec = serve(s, pairPath)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var res PairStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, 1.5, res.AvgQuotesPerRFQ)
+	assert.Equal(t, "300", res.QuotedVolume.String())
+	assert.Equal(t, uint64(3), res.ResponseLatency.Count)
+	assert.Equal(t, 300.0, res.ResponseLatency.Avg)
+	require.Len(t, res.SpreadBps, len(core.SpreadBoundsBps)+1)
+	assert.Equal(t, int64(0), *res.SpreadBps[0].Below)
+	last := res.SpreadBps[len(core.SpreadBoundsBps)]
+	assert.Nil(t, last.Below)
+	assert.Equal(t, uint64(2), last.Count)
+
+	rec = serve(s, "/stats/pairs/0x01/"+usdc.Hex())
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(s, pairPath+"/history?interval=24h&from=0&to=864000000")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, uint64(24*60*60*1000), data.interval)
+	assert.Equal(t, uint64(864000000), data.to)
+
+	for _, query := range []string{"interval=30m", "interval=day", "from=10&to=5", "interval=1h&from=0&to=3600000000"} {
+		rec = serve(s, pairPath+"/history?"+query)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
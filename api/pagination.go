@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPageSize = 100
+	maxPageSize     = 1000
+
+	// NextCursorHeader holds the cursor of the next page of a listing, it is
+	// not set on the last page
+	NextCursorHeader = "X-Next-Cursor"
+)
+
+// listParams are the query parameters selecting a page of RFQs.
+var listParams = []string{"limit", "cursor", "order", "status", "requestor", "baseToken", "quoteToken", "from", "to"}
+
+var (
+	errInvalidLimit     = errors.New("limit must be between 1 and 1000")
+	errInvalidCursor    = errors.New("invalid cursor")
+	errInvalidOrder     = errors.New("order must be asc or desc")
+	errInvalidStatus    = errors.New("status must be requested, open or closed")
+	errInvalidTimeRange = errors.New("from and to must be unix times in milliseconds, from before to")
+	errNoRFQIndex       = errors.New("RFQ listings can't be paged or filtered on this node")
+)
+
+// RFQIndex pages through the RFQ tables using their secondary indexes.
+type RFQIndex interface {
+	QueryRFQRequests(q core.RFQQuery, keep func(*types.RFQRequest) bool) ([]*types.RFQRequest, []byte, error)
+	QueryOpenRFQs(q core.RFQQuery, keep func(*types.OpenRFQ) bool) ([]*types.OpenRFQ, []byte, error)
+}
+
+// rfqQuery reads the listing parameters of an RFQ endpoint. The status
+// parameter is only read when status is empty, the open and closed RFQ
+// endpoints list a single status.
+func rfqQuery(params url.Values, status types.RFQStatus) (core.RFQQuery, error) {
+	q := core.RFQQuery{Status: status, Limit: defaultPageSize}
+
+	if value := params.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return q, errInvalidLimit
+		}
+		q.Limit = limit
+	}
+	if value := params.Get("cursor"); value != "" {
+		cursor, err := hexutil.Decode(value)
+		if err != nil {
+			return q, errInvalidCursor
+		}
+		q.Cursor = cursor
+	}
+	switch params.Get("order") {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return q, errInvalidOrder
+	}
+	if value := params.Get("status"); value != "" && status == "" {
+		switch s := types.RFQStatus(strings.ToUpper(value)); s {
+		case types.RFQStatusRequested, types.RFQStatusOpen, types.RFQStatusClosed:
+			q.Status = s
+		default:
+			return q, errInvalidStatus
+		}
+	}
+	for param, addr := range map[string]**common.Address{"requestor": &q.Requestor, "baseToken": &q.BaseToken, "quoteToken": &q.QuoteToken} {
+		if value := params.Get(param); value != "" {
+			if !common.IsHexAddress(value) {
+				return q, types.ErrInvalidAddress
+			}
+			a := common.HexToAddress(value)
+			*addr = &a
+		}
+	}
+	for param, t := range map[string]*uint64{"from": &q.From, "to": &q.To} {
+		if value := params.Get(param); value != "" {
+			ms, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return q, errInvalidTimeRange
+			}
+			*t = ms
+		}
+	}
+	if q.To != 0 && q.From > q.To {
+		return q, errInvalidTimeRange
+	}
+	return q, nil
+}
+
+// paged reports whether a request uses any of the listing parameters.
+func paged(params url.Values) bool {
+	for _, param := range listParams {
+		if params.Has(param) {
+			return true
+		}
+	}
+	return false
+}
+
+// queryError writes the response to a failed listing.
+func queryError(c echo.Context, err error) error {
+	if errors.Is(err, core.ErrInvalidCursor) {
+		err = errInvalidCursor
+	}
+	return writeError(c, http.StatusBadRequest, err)
+}
+
+func setNextCursor(c echo.Context, next []byte) {
+	if next != nil {
+		c.Response().Header().Set(NextCursorHeader, hexutil.Encode(next))
+	}
+}
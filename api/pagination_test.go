@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRFQIndex serves a fixed page of open RFQs and records the query.
+type fakeRFQIndex struct {
+	query core.RFQQuery
+	rfqs  []*types.OpenRFQ
+	next  []byte
+}
+
+func (f *fakeRFQIndex) QueryRFQRequests(q core.RFQQuery, keep func(*types.RFQRequest) bool) ([]*types.RFQRequest, []byte, error) {
+	f.query = q
+	return nil, nil, nil
+}
+
+func (f *fakeRFQIndex) QueryOpenRFQs(q core.RFQQuery, keep func(*types.OpenRFQ) bool) ([]*types.OpenRFQ, []byte, error) {
+	f.query = q
+	if q.Cursor != nil && len(q.Cursor) != 41 {
+		return nil, nil, core.ErrInvalidCursor
+	}
+	var kept []*types.OpenRFQ
+	for _, rfq := range f.rfqs {
+		if keep(rfq) {
+			kept = append(kept, rfq)
+		}
+	}
+	return kept, f.next, nil
+}
+
+func TestGetOpenRFQsPaged(t *testing.T) {
+	dealer := common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	index := &fakeRFQIndex{
+		rfqs: []*types.OpenRFQ{
+			{Data: &types.RFQData{RFQTxHash: common.HexToHash("0x01"), RFQRequest: &types.SignableData{}}},
+			{Data: &types.RFQData{
+				RFQTxHash:  common.HexToHash("0x02"),
+				RFQRequest: &types.SignableData{Recipients: []common.Address{dealer}},
+				Recipients: []common.Address{dealer},
+			}},
+		},
+		next: []byte{0x01, 0x02},
+	}
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetRFQRequestByHash", common.HexToHash("0x02")).Return(nil, assert.AnError)
+	s := NewServer(ServerConfig{RFQIndex: index}, mockChain, nil)
+	e := echo.New()
+	e.GET("/openRFQs", s.handleGetOpenRFQRequests)
+	e.GET("/closedRFQs", s.handleGetClosedRFQRequests)
+
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	weth := "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"
+	rec := get("/openRFQs?limit=2&order=desc&baseToken=" + weth + "&from=1000&to=2000&status=closed")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "0x0102", rec.Header().Get(NextCursorHeader))
+
+	base := common.HexToAddress(weth)
+	assert.Equal(t, core.RFQQuery{
+		Status:    types.RFQStatusOpen,
+		BaseToken: &base,
+		From:      1000,
+		To:        2000,
+		Desc:      true,
+		Limit:     2,
+	}, index.query)
+
+	// the private RFQ is left out for an unauthenticated caller
+	var rfqs []types.OpenRFQ
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rfqs))
+	require.Len(t, rfqs, 1)
+	assert.Equal(t, common.HexToHash("0x01"), rfqs[0].Data.RFQTxHash)
+
+	rec = get("/closedRFQs")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, core.RFQQuery{Status: types.RFQStatusClosed, Limit: defaultPageSize}, index.query)
+
+	for _, target := range []string{
+		"/openRFQs?limit=0",
+		"/openRFQs?limit=1001",
+		"/openRFQs?order=newest",
+		"/openRFQs?requestor=0x01",
+		"/openRFQs?from=2000&to=1000",
+		"/openRFQs?cursor=zz",
+		"/openRFQs?cursor=0x01",
+	} {
+		assert.Equal(t, http.StatusBadRequest, get(target).Code, target)
+	}
+}
+
+func TestListingWithoutRFQIndex(t *testing.T) {
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetOpenRFQRequests").Return([]*types.OpenRFQ{}, nil)
+	s := NewServer(ServerConfig{}, mockChain, nil)
+	e := echo.New()
+	e.GET("/openRFQs", s.handleGetOpenRFQRequests)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openRFQs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openRFQs?limit=10", nil))
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
@@ -0,0 +1,390 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.4
+// source: relayer/v1/relayer.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	Relayer_SubmitRFQ_FullMethodName        = "/relayer.v1.Relayer/SubmitRFQ"
+	Relayer_SubmitQuote_FullMethodName      = "/relayer.v1.Relayer/SubmitQuote"
+	Relayer_GetOpenRFQs_FullMethodName      = "/relayer.v1.Relayer/GetOpenRFQs"
+	Relayer_GetQuotes_FullMethodName        = "/relayer.v1.Relayer/GetQuotes"
+	Relayer_GetBlock_FullMethodName         = "/relayer.v1.Relayer/GetBlock"
+	Relayer_SubscribeRFQs_FullMethodName    = "/relayer.v1.Relayer/SubscribeRFQs"
+	Relayer_SubscribeResults_FullMethodName = "/relayer.v1.Relayer/SubscribeResults"
+)
+
+// RelayerClient is the client API for Relayer service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type RelayerClient interface {
+	SubmitRFQ(ctx context.Context, in *SubmitRFQRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	SubmitQuote(ctx context.Context, in *SubmitQuoteRequest, opts ...grpc.CallOption) (*SubmitResponse, error)
+	GetOpenRFQs(ctx context.Context, in *GetOpenRFQsRequest, opts ...grpc.CallOption) (*GetOpenRFQsResponse, error)
+	GetQuotes(ctx context.Context, in *GetQuotesRequest, opts ...grpc.CallOption) (*GetQuotesResponse, error)
+	GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error)
+	// SubscribeRFQs streams newly opened RFQs.
+	SubscribeRFQs(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Relayer_SubscribeRFQsClient, error)
+	// SubscribeResults streams closed RFQs and the results of their auctions.
+	SubscribeResults(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Relayer_SubscribeResultsClient, error)
+}
+
+type relayerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewRelayerClient(cc grpc.ClientConnInterface) RelayerClient {
+	return &relayerClient{cc}
+}
+
+func (c *relayerClient) SubmitRFQ(ctx context.Context, in *SubmitRFQRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
+	out := new(SubmitResponse)
+	err := c.cc.Invoke(ctx, Relayer_SubmitRFQ_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayerClient) SubmitQuote(ctx context.Context, in *SubmitQuoteRequest, opts ...grpc.CallOption) (*SubmitResponse, error) {
+	out := new(SubmitResponse)
+	err := c.cc.Invoke(ctx, Relayer_SubmitQuote_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayerClient) GetOpenRFQs(ctx context.Context, in *GetOpenRFQsRequest, opts ...grpc.CallOption) (*GetOpenRFQsResponse, error) {
+	out := new(GetOpenRFQsResponse)
+	err := c.cc.Invoke(ctx, Relayer_GetOpenRFQs_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayerClient) GetQuotes(ctx context.Context, in *GetQuotesRequest, opts ...grpc.CallOption) (*GetQuotesResponse, error) {
+	out := new(GetQuotesResponse)
+	err := c.cc.Invoke(ctx, Relayer_GetQuotes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayerClient) GetBlock(ctx context.Context, in *GetBlockRequest, opts ...grpc.CallOption) (*Block, error) {
+	out := new(Block)
+	err := c.cc.Invoke(ctx, Relayer_GetBlock_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *relayerClient) SubscribeRFQs(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Relayer_SubscribeRFQsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Relayer_ServiceDesc.Streams[0], Relayer_SubscribeRFQs_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &relayerSubscribeRFQsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Relayer_SubscribeRFQsClient interface {
+	Recv() (*RFQEvent, error)
+	grpc.ClientStream
+}
+
+type relayerSubscribeRFQsClient struct {
+	grpc.ClientStream
+}
+
+func (x *relayerSubscribeRFQsClient) Recv() (*RFQEvent, error) {
+	m := new(RFQEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *relayerClient) SubscribeResults(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Relayer_SubscribeResultsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Relayer_ServiceDesc.Streams[1], Relayer_SubscribeResults_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &relayerSubscribeResultsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Relayer_SubscribeResultsClient interface {
+	Recv() (*ResultEvent, error)
+	grpc.ClientStream
+}
+
+type relayerSubscribeResultsClient struct {
+	grpc.ClientStream
+}
+
+func (x *relayerSubscribeResultsClient) Recv() (*ResultEvent, error) {
+	m := new(ResultEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// RelayerServer is the server API for Relayer service.
+// All implementations must embed UnimplementedRelayerServer
+// for forward compatibility
+type RelayerServer interface {
+	SubmitRFQ(context.Context, *SubmitRFQRequest) (*SubmitResponse, error)
+	SubmitQuote(context.Context, *SubmitQuoteRequest) (*SubmitResponse, error)
+	GetOpenRFQs(context.Context, *GetOpenRFQsRequest) (*GetOpenRFQsResponse, error)
+	GetQuotes(context.Context, *GetQuotesRequest) (*GetQuotesResponse, error)
+	GetBlock(context.Context, *GetBlockRequest) (*Block, error)
+	// SubscribeRFQs streams newly opened RFQs.
+	SubscribeRFQs(*SubscribeRequest, Relayer_SubscribeRFQsServer) error
+	// SubscribeResults streams closed RFQs and the results of their auctions.
+	SubscribeResults(*SubscribeRequest, Relayer_SubscribeResultsServer) error
+	mustEmbedUnimplementedRelayerServer()
+}
+
+// UnimplementedRelayerServer must be embedded to have forward compatible implementations.
+type UnimplementedRelayerServer struct {
+}
+
+func (UnimplementedRelayerServer) SubmitRFQ(context.Context, *SubmitRFQRequest) (*SubmitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitRFQ not implemented")
+}
+func (UnimplementedRelayerServer) SubmitQuote(context.Context, *SubmitQuoteRequest) (*SubmitResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitQuote not implemented")
+}
+func (UnimplementedRelayerServer) GetOpenRFQs(context.Context, *GetOpenRFQsRequest) (*GetOpenRFQsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetOpenRFQs not implemented")
+}
+func (UnimplementedRelayerServer) GetQuotes(context.Context, *GetQuotesRequest) (*GetQuotesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetQuotes not implemented")
+}
+func (UnimplementedRelayerServer) GetBlock(context.Context, *GetBlockRequest) (*Block, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBlock not implemented")
+}
+func (UnimplementedRelayerServer) SubscribeRFQs(*SubscribeRequest, Relayer_SubscribeRFQsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeRFQs not implemented")
+}
+func (UnimplementedRelayerServer) SubscribeResults(*SubscribeRequest, Relayer_SubscribeResultsServer) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeResults not implemented")
+}
+func (UnimplementedRelayerServer) mustEmbedUnimplementedRelayerServer() {}
+
+// UnsafeRelayerServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to RelayerServer will
+// result in compilation errors.
+type UnsafeRelayerServer interface {
+	mustEmbedUnimplementedRelayerServer()
+}
+
+func RegisterRelayerServer(s grpc.ServiceRegistrar, srv RelayerServer) {
+	s.RegisterService(&Relayer_ServiceDesc, srv)
+}
+
+func _Relayer_SubmitRFQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitRFQRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayerServer).SubmitRFQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Relayer_SubmitRFQ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayerServer).SubmitRFQ(ctx, req.(*SubmitRFQRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Relayer_SubmitQuote_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitQuoteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayerServer).SubmitQuote(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Relayer_SubmitQuote_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayerServer).SubmitQuote(ctx, req.(*SubmitQuoteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Relayer_GetOpenRFQs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOpenRFQsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayerServer).GetOpenRFQs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Relayer_GetOpenRFQs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayerServer).GetOpenRFQs(ctx, req.(*GetOpenRFQsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Relayer_GetQuotes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetQuotesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayerServer).GetQuotes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Relayer_GetQuotes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayerServer).GetQuotes(ctx, req.(*GetQuotesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Relayer_GetBlock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RelayerServer).GetBlock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Relayer_GetBlock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RelayerServer).GetBlock(ctx, req.(*GetBlockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Relayer_SubscribeRFQs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RelayerServer).SubscribeRFQs(m, &relayerSubscribeRFQsServer{stream})
+}
+
+type Relayer_SubscribeRFQsServer interface {
+	Send(*RFQEvent) error
+	grpc.ServerStream
+}
+
+type relayerSubscribeRFQsServer struct {
+	grpc.ServerStream
+}
+
+func (x *relayerSubscribeRFQsServer) Send(m *RFQEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Relayer_SubscribeResults_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RelayerServer).SubscribeResults(m, &relayerSubscribeResultsServer{stream})
+}
+
+type Relayer_SubscribeResultsServer interface {
+	Send(*ResultEvent) error
+	grpc.ServerStream
+}
+
+type relayerSubscribeResultsServer struct {
+	grpc.ServerStream
+}
+
+func (x *relayerSubscribeResultsServer) Send(m *ResultEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Relayer_ServiceDesc is the grpc.ServiceDesc for Relayer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Relayer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "relayer.v1.Relayer",
+	HandlerType: (*RelayerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitRFQ",
+			Handler:    _Relayer_SubmitRFQ_Handler,
+		},
+		{
+			MethodName: "SubmitQuote",
+			Handler:    _Relayer_SubmitQuote_Handler,
+		},
+		{
+			MethodName: "GetOpenRFQs",
+			Handler:    _Relayer_GetOpenRFQs_Handler,
+		},
+		{
+			MethodName: "GetQuotes",
+			Handler:    _Relayer_GetQuotes_Handler,
+		},
+		{
+			MethodName: "GetBlock",
+			Handler:    _Relayer_GetBlock_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeRFQs",
+			Handler:       _Relayer_SubscribeRFQs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeResults",
+			Handler:       _Relayer_SubscribeResults_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "relayer/v1/relayer.proto",
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"sync"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/labstack/echo/v4"
+)
+
+// maxQuoteBatch is the most quotes submitted in one batch.
+const maxQuoteBatch = 100
+
+var (
+	errQuoteBatchSize = fmt.Errorf("a batch must hold 1 to %d quotes", maxQuoteBatch)
+	errDuplicateQuote = errors.New("quote appears earlier in the batch")
+)
+
+// QuoteBatchResult is the outcome of one quote of a batch. Status is the
+// status POST /quotes would have answered with.
+type QuoteBatchResult struct {
+	Index     int          `json:"index"`
+	Status    int          `json:"status"`
+	TxHash    *common.Hash `json:"txHash,omitempty"`
+	RFQTxHash *common.Hash `json:"rfqTxHash,omitempty"`
+	Error     *APIError    `json:"error,omitempty"`
+	// RetryAfterMs is how long to wait before resubmitting a rate limited
+	// quote
+	RetryAfterMs int64 `json:"retryAfterMs,omitempty"`
+}
+
+// QuoteBatchResponse lists the results of a batch in the order the quotes
+// were sent.
+type QuoteBatchResponse struct {
+	Accepted int                 `json:"accepted"`
+	Rejected int                 `json:"rejected"`
+	Results  []*QuoteBatchResult `json:"results"`
+}
+
+// handlePostQuoteBatch submits many independently signed quotes. Each quote
+// is checked as by POST /quotes and counts against its limits, and a
+// rejected quote doesn't affect the others. Quotes are validated and their
+// signatures recovered concurrently, then accepted in the order sent.
+func (s *Server) handlePostQuoteBatch(c echo.Context) error {
+	var quoteBodies []*QuoteBody
+	if err := json.NewDecoder(c.Request().Body).Decode(&quoteBodies); err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	if len(quoteBodies) == 0 || len(quoteBodies) > maxQuoteBatch {
+		return writeError(c, http.StatusBadRequest, errQuoteBatchSize)
+	}
+
+	// the batch shares the allowance of single submissions
+	const endpoint = http.MethodPost + " /quotes"
+	results := make([]*QuoteBatchResult, len(quoteBodies))
+	prepared := make([]*types.Transaction, len(quoteBodies))
+	openRFQs := make([]*types.OpenRFQ, len(quoteBodies))
+	for i, quoteBody := range quoteBodies {
+		results[i] = &QuoteBatchResult{Index: i}
+		if quoteBody == nil {
+			results[i].reject(rejectSubmission(http.StatusBadRequest, errMissingData))
+			continue
+		}
+		if serr := s.limitSubmissionIP(endpoint, c.RealIP()); serr != nil {
+			results[i].reject(serr)
+		}
+	}
+
+	var wg sync.WaitGroup
+	work := make(chan int)
+	for w := 0; w < runtime.GOMAXPROCS(0) && w < len(quoteBodies); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				signedTx, openRFQ, serr := s.prepareQuote(quoteBodies[i])
+				if serr != nil {
+					results[i].reject(serr)
+					continue
+				}
+				prepared[i], openRFQs[i] = signedTx, openRFQ
+			}
+		}()
+	}
+	for i := range quoteBodies {
+		if results[i].Status == 0 {
+			work <- i
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	res := &QuoteBatchResponse{Results: results}
+	seen := make(map[common.Hash]bool, len(quoteBodies))
+	for i, signedTx := range prepared {
+		if signedTx == nil {
+			continue
+		}
+		hash := signedTx.Hash()
+		if seen[hash] {
+			results[i].reject(rejectSubmission(http.StatusConflict, errDuplicateQuote))
+			continue
+		}
+		seen[hash] = true
+		if serr := s.acceptQuote(endpoint, signedTx, openRFQs[i]); serr != nil {
+			results[i].reject(serr)
+			continue
+		}
+		rfqTxHash := openRFQs[i].Data.RFQTxHash
+		results[i].Status = http.StatusCreated
+		results[i].TxHash = &hash
+		results[i].RFQTxHash = &rfqTxHash
+	}
+	for _, result := range results {
+		if result.Status == http.StatusCreated {
+			res.Accepted++
+		} else {
+			res.Rejected++
+		}
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+func (r *QuoteBatchResult) reject(serr *submitError) {
+	r.Status = serr.status
+	r.Error = &serr.res
+	r.RetryAfterMs = serr.retryAfter.Milliseconds()
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func signQuote(t *testing.T, key cryptoocax.PrivateKey, data *types.QuoteData) string {
+	t.Helper()
+	signedTx, err := types.NewTx(types.NewQuote(key.PublicKey().Address(), data)).Sign(key)
+	require.NoError(t, err)
+	v, r, s := signedTx.RawSignatureValues()
+	sig := cryptoocax.Signature{V: v, R: r, S: s}
+	return sig.String()
+}
+
+func TestPostQuoteBatch(t *testing.T) {
+	rfqTxHash := common.HexToHash("0x01")
+	unknownRFQ := common.HexToHash("0x02")
+	base := &types.Token{Symbol: "MKR", Decimals: 18, Address: common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")}
+	quote := &types.Token{Symbol: "USDC", Decimals: 6, Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")}
+	openRFQ := &types.OpenRFQ{Data: &types.RFQData{
+		RFQTxHash:    rfqTxHash,
+		RFQRequest:   &types.SignableData{BaseToken: base, QuoteToken: quote, BaseTokenAmount: big.NewInt(1000)},
+		RFQEndTime:   time.Now().Add(time.Minute).UnixMilli(),
+		Status:       types.RFQStatusOpen,
+		Recipients:   nil,
+		QuotesRoot:   common.Hash{},
+		RFQStartTime: time.Now().UnixMilli(),
+	}}
+
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetOpenRFQByHash", rfqTxHash).Return(openRFQ, nil)
+	mockChain.On("GetOpenRFQByHash", unknownRFQ).Return(nil, fmt.Errorf("openRFQ with hash [%x]: %w", unknownRFQ, core.ErrRFQNotFound))
+	mockChain.On("CheckParticipant", mock.Anything, types.RoleMarketMaker, mock.Anything, mock.Anything).Return(nil)
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, assert.AnError)
+	mockChain.On("WriteRFQTxs", mock.Anything).Return(nil)
+	mockChain.On("UpdateActiveRFQ", rfqTxHash, mock.Anything).Return(nil)
+	txChan := make(chan *types.Transaction, maxQuoteBatch)
+	s := NewServer(ServerConfig{}, mockChain, txChan)
+
+	e := echo.New()
+	e.POST("/quotes/batch", s.handlePostQuoteBatch)
+	post := func(body interface{}) *httptest.ResponseRecorder {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/quotes/batch", bytes.NewReader(data)))
+		return rec
+	}
+
+	quoterA := cryptoocax.GeneratePrivateKey()
+	quoterB := cryptoocax.GeneratePrivateKey()
+	newQuote := func(key cryptoocax.PrivateKey, rfq common.Hash, amount int64, signer cryptoocax.PrivateKey) *QuoteBody {
+		data := &types.QuoteData{
+			RFQTxHash:       rfq,
+			BaseToken:       base,
+			QuoteToken:      quote,
+			BaseTokenAmount: big.NewInt(amount),
+			BidPrice:        big.NewInt(99),
+			AskPrice:        big.NewInt(101),
+		}
+		return &QuoteBody{From: key.PublicKey().Address().Hex(), Data: data, SignatureString: signQuote(t, signer, data)}
+	}
+	first := newQuote(quoterA, rfqTxHash, 500, quoterA)
+	batch := []*QuoteBody{
+		first,
+		newQuote(quoterB, rfqTxHash, 1000, quoterB),
+		newQuote(quoterA, rfqTxHash, 2000, quoterA), // more than requested
+		newQuote(quoterA, unknownRFQ, 500, quoterA), // no such RFQ
+		newQuote(quoterA, rfqTxHash, 400, quoterB),  // signed by someone else
+		first, // sent twice
+		nil,
+	}
+
+	rec := post(batch)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var res QuoteBatchResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, 2, res.Accepted)
+	assert.Equal(t, 5, res.Rejected)
+	require.Len(t, res.Results, len(batch))
+
+	statuses := make([]int, len(res.Results))
+	for i, result := range res.Results {
+		assert.Equal(t, i, result.Index)
+		statuses[i] = result.Status
+	}
+	assert.Equal(t, []int{
+		http.StatusCreated, http.StatusCreated, http.StatusBadRequest, http.StatusNotFound,
+		http.StatusBadRequest, http.StatusConflict, http.StatusBadRequest,
+	}, statuses)
+	assert.Equal(t, CodeQuoteAmount, res.Results[2].Error.Code)
+	assert.Equal(t, CodeRFQNotFound, res.Results[3].Error.Code)
+	assert.Equal(t, CodeDuplicateQuote, res.Results[5].Error.Code)
+
+	// accepted quotes went through the pipeline in the order sent
+	require.Len(t, txChan, 2)
+	assert.Equal(t, *res.Results[0].TxHash, (<-txChan).Hash())
+	assert.Equal(t, *res.Results[1].TxHash, (<-txChan).Hash())
+	assert.Equal(t, rfqTxHash, *res.Results[1].RFQTxHash)
+
+	assert.Equal(t, http.StatusBadRequest, post([]*QuoteBody{}).Code)
+	assert.Equal(t, http.StatusBadRequest, post(make([]*QuoteBody, maxQuoteBatch+1)).Code)
+}
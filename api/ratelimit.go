@@ -0,0 +1,259 @@
+package api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/labstack/echo/v4"
+)
+
+// maxBuckets bounds the number of idle buckets kept per limiter before they
+// are pruned.
+const maxBuckets = 10000
+
+var errRateLimited = errors.New("rate limit exceeded")
+
+// RateLimit is a token bucket refilled at Rate tokens per second up to Burst.
+type RateLimit struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+// RateLimits configures the limits of the submission endpoints. Limits are
+// keyed by endpoint ("POST /quotes") and endpoints without a limit are not
+// limited.
+type RateLimits struct {
+	// IP limits every client address, applied before the request is parsed
+	IP map[string]RateLimit
+	// Address limits every signer address once its signature is verified
+	Address map[string]RateLimit
+	// Roles override the Address limits for onboarded participants
+	Roles map[types.ParticipantRole]map[string]RateLimit
+}
+
+// DefaultRateLimits are the limits used when the server is not configured
+// with any.
+func DefaultRateLimits() *RateLimits {
+	return &RateLimits{
+		IP: map[string]RateLimit{
+			"POST /rfqs":         {Rate: 1, Burst: 10},
+			"POST /quotes":       {Rate: 10, Burst: 100},
+			"POST /participants": {Rate: 1, Burst: 10},
+		},
+		Address: map[string]RateLimit{
+			"POST /rfqs":   {Rate: 0.2, Burst: 5},
+			"POST /quotes": {Rate: 1, Burst: 10},
+		},
+		Roles: map[types.ParticipantRole]map[string]RateLimit{
+			types.RoleMarketMaker: {
+				"POST /quotes": {Rate: 5, Burst: 50},
+			},
+		},
+	}
+}
+
+// RateLimitError is returned with 429 responses, it is the APIError of other
+// error responses with the time to wait.
+type RateLimitError struct {
+	APIError
+	// RetryAfterMs is how long to wait before the request will be accepted
+	RetryAfterMs int64 `json:"retryAfterMs"`
+}
+
+// RateLimitCounters count the requests to an endpoint that passed the IP
+// limit and those rejected by the IP and the address limits.
+type RateLimitCounters struct {
+	Allowed     uint64 `json:"allowed"`
+	LimitedIP   uint64 `json:"limitedIP"`
+	LimitedAddr uint64 `json:"limitedAddress"`
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter holds the token buckets of one kind of key (IP or address).
+type rateLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{buckets: make(map[string]*bucket)}
+}
+
+// allow takes a token from the bucket of key, returning how long until one is
+// available when the bucket is empty.
+func (l *rateLimiter) allow(key string, limit RateLimit, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[key]
+	if !ok {
+		if len(l.buckets) >= maxBuckets {
+			l.prune(now)
+		}
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*limit.Rate)
+	b.last = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	if limit.Rate <= 0 {
+		return false, time.Hour
+	}
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return false, wait
+}
+
+// prune drops buckets that have been idle for a minute, by which time
+// they have usually refilled.
+func (l *rateLimiter) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if now.Sub(b.last) > time.Minute {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+type rateLimitStats struct {
+	allowed, limitedIP, limitedAddr atomic.Uint64
+}
+
+// submissionLimiter applies RateLimits and counts their outcome per endpoint.
+type submissionLimiter struct {
+	limits *RateLimits
+	ip     *rateLimiter
+	addr   *rateLimiter
+
+	mu    sync.Mutex
+	stats map[string]*rateLimitStats
+}
+
+func newSubmissionLimiter(limits *RateLimits) *submissionLimiter {
+	return &submissionLimiter{
+		limits: limits,
+		ip:     newRateLimiter(),
+		addr:   newRateLimiter(),
+		stats:  make(map[string]*rateLimitStats),
+	}
+}
+
+func (l *submissionLimiter) endpointStats(endpoint string) *rateLimitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	st, ok := l.stats[endpoint]
+	if !ok {
+		st = new(rateLimitStats)
+		l.stats[endpoint] = st
+	}
+	return st
+}
+
+func (l *submissionLimiter) counters() map[string]RateLimitCounters {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	counters := make(map[string]RateLimitCounters, len(l.stats))
+	for endpoint, st := range l.stats {
+		counters[endpoint] = RateLimitCounters{
+			Allowed:     st.allowed.Load(),
+			LimitedIP:   st.limitedIP.Load(),
+			LimitedAddr: st.limitedAddr.Load(),
+		}
+	}
+	return counters
+}
+
+func endpointKey(c echo.Context) string {
+	return c.Request().Method + " " + c.Path()
+}
+
+func rateLimited(c echo.Context, wait time.Duration) error {
+	c.Response().Header().Set("Retry-After", strconv.FormatInt(int64(math.Ceil(wait.Seconds())), 10))
+	return c.JSON(http.StatusTooManyRequests, RateLimitError{
+		APIError:     APIError{Code: CodeRateLimited, Error: errRateLimited.Error()},
+		RetryAfterMs: wait.Milliseconds(),
+	})
+}
+
+// limitIP is the echo middleware limiting submissions per client IP.
+func (s *Server) limitIP(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		endpoint := endpointKey(c)
+		limit, ok := s.limiter.limits.IP[endpoint]
+		if !ok {
+			return next(c)
+		}
+		if allowed, wait := s.allowIP(endpoint, limit, c.RealIP()); !allowed {
+			return rateLimited(c, wait)
+		}
+		return next(c)
+	}
+}
+
+// limitSubmissionIP applies the IP limit of a REST endpoint to a submission
+// made over another interface, so a client has one allowance whichever it
+// uses.
+func (s *Server) limitSubmissionIP(endpoint, ip string) *submitError {
+	limit, ok := s.limiter.limits.IP[endpoint]
+	if !ok {
+		return nil
+	}
+	if allowed, wait := s.allowIP(endpoint, limit, ip); !allowed {
+		return rateLimitSubmission(wait)
+	}
+	return nil
+}
+
+// allowIP counts a submission to endpoint from ip against limit and returns
+// how long to wait when it is over it.
+func (s *Server) allowIP(endpoint string, limit RateLimit, ip string) (bool, time.Duration) {
+	stats := s.limiter.endpointStats(endpoint)
+	allowed, wait := s.limiter.ip.allow(endpoint+" "+ip, limit, time.Now())
+	if !allowed {
+		stats.limitedIP.Add(1)
+		return false, wait
+	}
+	stats.allowed.Add(1)
+	return true, 0
+}
+
+// allowAddress counts a submission to endpoint signed by signer against the
+// limit of the signer's role, or the endpoint's address limit, and returns
+// how long to wait when it is over it. Submissions are only counted once
+// their signature is verified, so a client can't use up someone else's
+// allowance.
+func (s *Server) allowAddress(endpoint string, signer common.Address) (bool, time.Duration) {
+	limit, ok := s.limiter.limits.Address[endpoint]
+	if participant, err := s.bc.GetParticipant(signer); err == nil {
+		if roleLimit, found := s.limiter.limits.Roles[participant.Role][endpoint]; found {
+			limit, ok = roleLimit, true
+		}
+	}
+	if !ok {
+		return true, 0
+	}
+	if allowed, wait := s.limiter.addr.allow(endpoint+" "+signer.Hex(), limit, time.Now()); !allowed {
+		s.limiter.endpointStats(endpoint).limitedAddr.Add(1)
+		return false, wait
+	}
+	return true, 0
+}
+
+func (s *Server) handleGetRateLimits(c echo.Context) error {
+	return c.JSON(http.StatusOK, s.limiter.counters())
+}
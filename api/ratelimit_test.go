@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := newRateLimiter()
+	limit := RateLimit{Rate: 2, Burst: 2}
+	now := time.Now()
+
+	ok, _ := l.allow("a", limit, now)
+	assert.True(t, ok)
+	ok, _ = l.allow("a", limit, now)
+	assert.True(t, ok)
+	ok, wait := l.allow("a", limit, now)
+	assert.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+
+	// other keys have their own bucket
+	ok, _ = l.allow("b", limit, now)
+	assert.True(t, ok)
+
+	ok, _ = l.allow("a", limit, now.Add(wait))
+	assert.True(t, ok)
+}
+
+func TestLimitIP(t *testing.T) {
+	s := NewServer(ServerConfig{RateLimits: &RateLimits{
+		IP: map[string]RateLimit{"POST /rfqs": {Rate: 0.5, Burst: 1}},
+	}}, &chainmocks.ChainInterface{}, nil)
+
+	e := echo.New()
+	e.POST("/rfqs", func(c echo.Context) error { return c.NoContent(http.StatusAccepted) }, s.limitIP)
+	post := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rfqs", nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusAccepted, post().Code)
+	rec := post()
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	// the body is the error model of other errors with the time to wait
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, string(CodeRateLimited), body["code"])
+	assert.Equal(t, errRateLimited.Error(), body["Error"])
+	assert.InDelta(t, 2000, body["retryAfterMs"], 50)
+	var apiErr APIError
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &apiErr))
+	assert.Equal(t, CodeRateLimited, apiErr.Code)
+
+	counters := s.limiter.counters()["POST /rfqs"]
+	assert.Equal(t, uint64(1), counters.Allowed)
+	assert.Equal(t, uint64(1), counters.LimitedIP)
+}
+
+func TestLimitAddressByRole(t *testing.T) {
+	maker := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	other := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetParticipant", maker).Return(&types.Participant{Address: maker, Role: types.RoleMarketMaker}, nil)
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, assert.AnError)
+	s := NewServer(ServerConfig{RateLimits: &RateLimits{
+		Address: map[string]RateLimit{"POST /quotes": {Rate: 0, Burst: 1}},
+		Roles: map[types.ParticipantRole]map[string]RateLimit{
+			types.RoleMarketMaker: {"POST /quotes": {Rate: 0, Burst: 3}},
+		},
+	}}, mockChain, nil)
+
+	submit := func(signer common.Address) bool {
+		ok, _ := s.allowAddress("POST /quotes", signer)
+		return ok
+	}
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, submit(maker))
+	}
+	assert.False(t, submit(maker))
+
+	assert.True(t, submit(other))
+	assert.False(t, submit(other))
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/labstack/echo/v4"
+)
+
+// handleGetRiskUtilisation reports a requestor's risk limits and how much of
+// them its open and recent RFQs use. Callers must be signed and may only
+// query their own address unless they are auditors.
+func (s *Server) handleGetRiskUtilisation(c echo.Context) error {
+	if s.RiskChecker == nil {
+		return writeError(c, http.StatusNotFound, errRiskNotEnabled)
+	}
+	address := c.Param("address")
+	if !common.IsHexAddress(address) {
+		return writeError(c, http.StatusBadRequest, types.ErrInvalidAddress)
+	}
+	requestor := common.HexToAddress(address)
+	caller := callerFrom(c)
+	if caller == nil {
+		return writeError(c, http.StatusUnauthorized, errAuthRequired)
+	}
+	if caller.Address != requestor && !caller.HasRole(types.RoleAuditor) {
+		return writeError(c, http.StatusForbidden, errRiskNotVisible)
+	}
+	return c.JSON(http.StatusOK, s.RiskChecker.Utilisation(requestor, int64(nowMs())))
+}
@@ -0,0 +1,453 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const jsonrpcVersion = "2.0"
+
+// rpcMaxBatch is the largest batch of calls served in one request.
+const rpcMaxBatch = 100
+
+// JSON-RPC error codes, the standard ones and the server errors of EIP-1474.
+const (
+	RPCParseError     = -32700
+	RPCInvalidRequest = -32600
+	RPCMethodNotFound = -32601
+	RPCInvalidParams  = -32602
+	RPCInternalError  = -32603
+	RPCInvalidInput   = -32000
+	RPCNotFound       = -32001
+	RPCRejected       = -32003
+	RPCLimitExceeded  = -32005
+)
+
+// rpcSubscriptionMethod is the method of the notifications carrying the
+// events of rfq_subscribe subscriptions.
+const rpcSubscriptionMethod = "rfq_subscription"
+
+// RPCRequest is a JSON-RPC 2.0 call, calls without an ID are notifications
+// and get no response.
+type RPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// RPCResponse is the response to an RPCRequest.
+type RPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+// RPCError is the error of a failed call.
+type RPCError struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+// RPCNotification delivers an event to an rfq_subscribe subscription.
+type RPCNotification struct {
+	JSONRPC string                `json:"jsonrpc"`
+	Method  string                `json:"method"`
+	Params  RPCSubscriptionResult `json:"params"`
+}
+
+// RPCSubscriptionResult is an event and the subscription it was sent to.
+type RPCSubscriptionResult struct {
+	Subscription string          `json:"subscription"`
+	Result       json.RawMessage `json:"result"`
+}
+
+// RPCSubscriptionFilter narrows an rfq_subscribe subscription.
+type RPCSubscriptionFilter struct {
+	RFQTxHash  *common.Hash    `json:"rfqTxHash,omitempty"`
+	BaseToken  *common.Address `json:"baseToken,omitempty"`
+	QuoteToken *common.Address `json:"quoteToken,omitempty"`
+}
+
+var errNotificationsUnsupported = errors.New("notifications not supported, subscribe over a websocket")
+
+func rpcError(code int, err error) *RPCError {
+	return &RPCError{Code: code, Message: err.Error()}
+}
+
+// rpcSubmitError reports a rejected submission with the code matching its
+// HTTP status, the data holds the body of the REST error response.
+func rpcSubmitError(err *submitError) *RPCError {
+	code := RPCInvalidInput
+	var data interface{} = err.res
+	switch err.status {
+	case http.StatusTooManyRequests:
+		code = RPCLimitExceeded
+		data = RateLimitError{APIError: APIError{Code: CodeRateLimited, Error: err.Error()}, RetryAfterMs: err.retryAfter.Milliseconds()}
+	case http.StatusNotFound:
+		code = RPCNotFound
+	case http.StatusForbidden, http.StatusConflict:
+		code = RPCRejected
+	}
+	return &RPCError{Code: code, Message: err.Error(), Data: data}
+}
+
+// rpcParams decodes the positional params into args, the first required of
+// which must be given.
+func rpcParams(raw json.RawMessage, required int, args ...interface{}) *RPCError {
+	var params []json.RawMessage
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return rpcError(RPCInvalidParams, errors.New("params must be an array"))
+		}
+	}
+	if len(params) < required || len(params) > len(args) {
+		return &RPCError{Code: RPCInvalidParams, Message: "expected " + strconv.Itoa(required) + " to " + strconv.Itoa(len(args)) + " params"}
+	}
+	for i, param := range params {
+		if err := json.Unmarshal(param, args[i]); err != nil {
+			return &RPCError{Code: RPCInvalidParams, Message: "invalid param " + strconv.Itoa(i) + ": " + err.Error()}
+		}
+	}
+	return nil
+}
+
+// rpcBlockNumber is a block height given as a hex quantity or a number.
+type rpcBlockNumber big.Int
+
+func (n *rpcBlockNumber) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var q hexutil.Big
+		if err := q.UnmarshalJSON(data); err != nil {
+			return err
+		}
+		*n = rpcBlockNumber(q)
+		return nil
+	}
+	var height uint64
+	if err := json.Unmarshal(data, &height); err != nil {
+		return err
+	}
+	(*big.Int)(n).SetUint64(height)
+	return nil
+}
+
+// rpcConn is the connection a call arrived on.
+type rpcConn struct {
+	caller *Caller
+	ip     string
+	// sub receives the events of the connection's subscriptions, nil over
+	// HTTP
+	sub *subscriber
+}
+
+type rpcMethod func(s *Server, conn *rpcConn, params json.RawMessage) (interface{}, *RPCError)
+
+var rpcMethods = map[string]rpcMethod{
+	"rfq_submitRequest":      (*Server).rpcSubmitRequest,
+	"rfq_submitQuote":        (*Server).rpcSubmitQuote,
+	"rfq_getOpenRFQs":        (*Server).rpcGetOpenRFQs,
+	"rfq_getOpenRFQ":         (*Server).rpcGetOpenRFQ,
+	"rfq_getClosedRFQs":      (*Server).rpcGetClosedRFQs,
+	"rfq_getQuotes":          (*Server).rpcGetQuotes,
+	"rfq_subscribe":          (*Server).rpcSubscribe,
+	"rfq_unsubscribe":        (*Server).rpcUnsubscribe,
+	"chain_getBlockByNumber": (*Server).rpcGetBlockByNumber,
+}
+
+// serveRPC serves a single call or a batch and returns the encoded response,
+// nil when there is nothing to respond with.
+func (s *Server) serveRPC(conn *rpcConn, body []byte) []byte {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '[' {
+		res := s.callRPC(conn, body)
+		if res == nil {
+			return nil
+		}
+		data, _ := json.Marshal(res)
+		return data
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(body, &batch); err != nil {
+		data, _ := json.Marshal(&RPCResponse{JSONRPC: jsonrpcVersion, Error: rpcError(RPCParseError, err)})
+		return data
+	}
+	if len(batch) == 0 || len(batch) > rpcMaxBatch {
+		data, _ := json.Marshal(&RPCResponse{
+			JSONRPC: jsonrpcVersion,
+			Error:   &RPCError{Code: RPCInvalidRequest, Message: "batch must hold 1 to " + strconv.Itoa(rpcMaxBatch) + " calls"},
+		})
+		return data
+	}
+	responses := make([]*RPCResponse, 0, len(batch))
+	for _, call := range batch {
+		if res := s.callRPC(conn, call); res != nil {
+			responses = append(responses, res)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	data, _ := json.Marshal(responses)
+	return data
+}
+
+// callRPC serves one call, returning nil for notifications.
+func (s *Server) callRPC(conn *rpcConn, call []byte) *RPCResponse {
+	var req RPCRequest
+	if err := json.Unmarshal(call, &req); err != nil {
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) || len(call) == 0 {
+			return &RPCResponse{JSONRPC: jsonrpcVersion, Error: rpcError(RPCParseError, err)}
+		}
+		return &RPCResponse{JSONRPC: jsonrpcVersion, Error: rpcError(RPCInvalidRequest, err)}
+	}
+	res := &RPCResponse{JSONRPC: jsonrpcVersion, ID: req.ID}
+	if req.JSONRPC != jsonrpcVersion || req.Method == "" {
+		res.Error = &RPCError{Code: RPCInvalidRequest, Message: `jsonrpc must be "2.0" and method is required`}
+		return res
+	}
+
+	method, ok := rpcMethods[req.Method]
+	if !ok {
+		res.Error = &RPCError{Code: RPCMethodNotFound, Message: "the method " + req.Method + " does not exist"}
+	} else {
+		res.Result, res.Error = method(s, conn, req.Params)
+	}
+	if len(req.ID) == 0 {
+		return nil
+	}
+	return res
+}
+
+// handleRPC serves JSON-RPC calls posted over HTTP.
+func (s *Server) handleRPC(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	res := s.serveRPC(&rpcConn{caller: callerFrom(c), ip: c.RealIP()}, body)
+	if res == nil {
+		return c.NoContent(http.StatusNoContent)
+	}
+	return c.JSONBlob(http.StatusOK, res)
+}
+
+// handleRPCWebsocket serves JSON-RPC calls over a websocket, on which
+// rfq_subscribe subscriptions deliver their events as rfq_subscription
+// notifications.
+func (s *Server) handleRPCWebsocket(c echo.Context) error {
+	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return err
+	}
+	sub := s.subscribe(callerFrom(c), nil, nil, false, s.closeWebsocket(ws))
+	defer s.hub.unregister(sub)
+	defer sub.close()
+
+	go rpcWriteLoop(ws, sub)
+
+	conn := &rpcConn{caller: sub.caller, ip: c.RealIP(), sub: sub}
+	for {
+		_, msg, err := ws.ReadMessage()
+		if err != nil {
+			return nil
+		}
+		if res := s.serveRPC(conn, msg); res != nil {
+			if sub.queue(outbound{data: res}) != nil {
+				return nil
+			}
+		}
+	}
+}
+
+// rpcWriteLoop writes the responses queued for sub and a notification per
+// subscription each queued event matches.
+func rpcWriteLoop(conn *websocket.Conn, sub *subscriber) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+	defer sub.close()
+
+	write := func(data []byte) bool {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		return conn.WriteMessage(websocket.TextMessage, data) == nil
+	}
+	for {
+		select {
+		case msg := <-sub.send:
+			if msg.ev == nil {
+				if !write(msg.data) {
+					return
+				}
+				continue
+			}
+			for _, id := range sub.matching(msg.ev) {
+				data, err := json.Marshal(&RPCNotification{
+					JSONRPC: jsonrpcVersion,
+					Method:  rpcSubscriptionMethod,
+					Params:  RPCSubscriptionResult{Subscription: id, Result: msg.data},
+				})
+				if err != nil || !write(data) {
+					return
+				}
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+func (s *Server) rpcSubmitRequest(conn *rpcConn, params json.RawMessage) (interface{}, *RPCError) {
+	body := new(RFQRequestBody)
+	if err := rpcParams(params, 1, body); err != nil {
+		return nil, err
+	}
+	const endpoint = http.MethodPost + " /rfqs"
+	if err := s.limitSubmissionIP(endpoint, conn.ip); err != nil {
+		return nil, rpcSubmitError(err)
+	}
+	tx, err := s.submitRFQRequest(endpoint, body)
+	if err != nil {
+		return nil, rpcSubmitError(err)
+	}
+	return tx, nil
+}
+
+func (s *Server) rpcSubmitQuote(conn *rpcConn, params json.RawMessage) (interface{}, *RPCError) {
+	body := new(QuoteBody)
+	if err := rpcParams(params, 1, body); err != nil {
+		return nil, err
+	}
+	const endpoint = http.MethodPost + " /quotes"
+	if err := s.limitSubmissionIP(endpoint, conn.ip); err != nil {
+		return nil, rpcSubmitError(err)
+	}
+	tx, _, err := s.submitQuote(endpoint, body)
+	if err != nil {
+		return nil, rpcSubmitError(err)
+	}
+	return tx, nil
+}
+
+func (s *Server) rpcGetOpenRFQs(conn *rpcConn, params json.RawMessage) (interface{}, *RPCError) {
+	if err := rpcParams(params, 0); err != nil {
+		return nil, err
+	}
+	rfqs, err := s.bc.GetOpenRFQRequests()
+	if err != nil {
+		return nil, rpcError(RPCInternalError, err)
+	}
+	return intoJSONOpenRFQS(s.visibleRFQs(conn.caller, rfqs)), nil
+}
+
+func (s *Server) rpcGetOpenRFQ(conn *rpcConn, params json.RawMessage) (interface{}, *RPCError) {
+	var hash common.Hash
+	if err := rpcParams(params, 1, &hash); err != nil {
+		return nil, err
+	}
+	rfq, err := s.bc.GetOpenRFQByHash(hash)
+	// private RFQs are reported as missing rather than revealing they exist
+	if err != nil || !s.rfqVisible(conn.caller, rfq.Data) {
+		return nil, &RPCError{Code: RPCNotFound, Message: "RFQ not found"}
+	}
+	return intoJSONOpenRFQ(s.filterRFQ(conn.caller, rfq)), nil
+}
+
+func (s *Server) rpcGetClosedRFQs(conn *rpcConn, params json.RawMessage) (interface{}, *RPCError) {
+	if err := rpcParams(params, 0); err != nil {
+		return nil, err
+	}
+	rfqs, err := s.bc.GetClosedRFQRequests()
+	if err != nil {
+		return nil, rpcError(RPCInternalError, err)
+	}
+	return intoJSONOpenRFQS(s.visibleRFQs(conn.caller, rfqs)), nil
+}
+
+func (s *Server) rpcGetQuotes(conn *rpcConn, params json.RawMessage) (interface{}, *RPCError) {
+	var rfqTxHash common.Hash
+	if err := rpcParams(params, 1, &rfqTxHash); err != nil {
+		return nil, err
+	}
+	// private RFQs are reported as missing rather than revealing they exist
+	if !s.auctionVisible(conn.caller, rfqTxHash) {
+		return nil, &RPCError{Code: RPCNotFound, Message: "RFQ not found"}
+	}
+	quotes, err := s.bc.GetAuctionQuotes(rfqTxHash)
+	if err != nil {
+		return nil, rpcError(RPCNotFound, err)
+	}
+	quotes = s.filterQuotes(conn.caller, rfqTxHash, quotes)
+	if quotes == nil {
+		quotes = []*types.Quote{}
+	}
+	return quotes, nil
+}
+
+func (s *Server) rpcGetBlockByNumber(conn *rpcConn, params json.RawMessage) (interface{}, *RPCError) {
+	height := new(rpcBlockNumber)
+	if err := rpcParams(params, 1, height); err != nil {
+		return nil, err
+	}
+	block, err := s.bc.GetBlock((*big.Int)(height))
+	if err != nil {
+		return nil, rpcError(RPCNotFound, err)
+	}
+	return intoJSONBlock(block), nil
+}
+
+// rpcSubscribe subscribes the connection to a topic, narrowed by an optional
+// filter, and returns the subscription ID.
+func (s *Server) rpcSubscribe(conn *rpcConn, params json.RawMessage) (interface{}, *RPCError) {
+	if conn.sub == nil {
+		return nil, rpcError(RPCMethodNotFound, errNotificationsUnsupported)
+	}
+	var topic string
+	var filter RPCSubscriptionFilter
+	if err := rpcParams(params, 1, &topic, &filter); err != nil {
+		return nil, err
+	}
+	ack := conn.sub.handle(&WSRequest{
+		Op:         opSubscribe,
+		Topic:      topic,
+		RFQTxHash:  filter.RFQTxHash,
+		BaseToken:  filter.BaseToken,
+		QuoteToken: filter.QuoteToken,
+	})
+	if ack.Type == messageError {
+		return nil, &RPCError{Code: RPCInvalidParams, Message: ack.Error}
+	}
+	return ack.Subscription, nil
+}
+
+func (s *Server) rpcUnsubscribe(conn *rpcConn, params json.RawMessage) (interface{}, *RPCError) {
+	if conn.sub == nil {
+		return nil, rpcError(RPCMethodNotFound, errNotificationsUnsupported)
+	}
+	var id string
+	if err := rpcParams(params, 1, &id); err != nil {
+		return nil, err
+	}
+	ack := conn.sub.handle(&WSRequest{Op: opUnsubscribe, Subscription: id})
+	return ack.Type == messageAck, nil
+}
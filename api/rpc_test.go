@@ -0,0 +1,170 @@
+package api
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/go-kit/log"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func postRPC(t *testing.T, s *Server, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	require.NoError(t, s.handleRPC(echo.New().NewContext(req, rec)))
+	return rec
+}
+
+func TestRPCBatch(t *testing.T) {
+	mockChain := &chainmocks.ChainInterface{}
+	openRFQ := types.NewOpenRFQ(common.Address{}, &types.RFQData{RFQTxHash: common.HexToHash("0x01"), Status: types.RFQStatusOpen})
+	mockChain.On("GetOpenRFQRequests").Return([]*types.OpenRFQ{openRFQ}, nil)
+	mockChain.On("GetBlock", big.NewInt(16)).Return(nil, assert.AnError)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, mockChain, nil)
+
+	rec := postRPC(t, s, `[
+		{"jsonrpc": "2.0", "id": 1, "method": "rfq_getOpenRFQs"},
+		{"jsonrpc": "2.0", "id": 2, "method": "rfq_unknown"},
+		{"jsonrpc": "2.0", "method": "rfq_getOpenRFQs"},
+		{"jsonrpc": "1.0", "id": 3, "method": "rfq_getOpenRFQs"},
+		{"jsonrpc": "2.0", "id": 4, "method": "chain_getBlockByNumber", "params": ["0x10"]},
+		{"jsonrpc": "2.0", "id": 5, "method": "chain_getBlockByNumber", "params": []},
+		{"jsonrpc": "2.0", "id": 6, "method": "rfq_subscribe", "params": ["rfqs"]},
+		1
+	]`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var responses []struct {
+		ID     json.RawMessage
+		Result json.RawMessage
+		Error  *RPCError
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responses))
+	// the notification gets no response
+	require.Len(t, responses, 7)
+
+	var rfqs []types.OpenRFQ
+	require.Nil(t, responses[0].Error)
+	require.NoError(t, json.Unmarshal(responses[0].Result, &rfqs))
+	require.Len(t, rfqs, 1)
+	assert.Equal(t, common.HexToHash("0x01"), rfqs[0].Data.RFQTxHash)
+
+	for i, code := range []int{RPCMethodNotFound, RPCInvalidRequest, RPCNotFound, RPCInvalidParams, RPCMethodNotFound, RPCInvalidRequest} {
+		require.NotNil(t, responses[i+1].Error, "response %d", i+1)
+		assert.Equal(t, code, responses[i+1].Error.Code, "response %d", i+1)
+	}
+	assert.Equal(t, "3", string(responses[2].ID))
+	assert.Equal(t, "null", string(responses[6].ID))
+
+	rec = postRPC(t, s, `{"jsonrpc": "2.0", "id": 1, "method"`)
+	var res RPCResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, RPCParseError, res.Error.Code)
+
+	rec = postRPC(t, s, `{"jsonrpc": "2.0", "method": "rfq_getOpenRFQs"}`)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestRPCSubmitRequest(t *testing.T) {
+	requestorKey := cryptoocax.GeneratePrivateKey()
+	addr := requestorKey.PublicKey().Address()
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("WriteRFQTxs", mock.Anything).Return(nil)
+	mockChain.On("GetParticipant", addr).Return(nil, assert.AnError)
+	mockChain.On("CheckParticipant", addr, types.RoleRequestor, mock.Anything, mock.Anything).Return(nil)
+
+	txChan := make(chan *types.Transaction, 1)
+	s := NewServer(ServerConfig{}, mockChain, txChan)
+
+	token := &types.Token{Symbol: "ETH", Decimals: 18, Address: common.HexToAddress("0x0d1d4e623D10F9FBA5Db95830F7d3839406C6AF2")}
+	signableData := &types.SignableData{
+		RequestorId:     addr.Hex(),
+		BaseTokenAmount: big.NewInt(1000),
+		BaseToken:       token,
+		QuoteToken:      token,
+		RFQDurationMs:   10_000,
+	}
+	params, err := json.Marshal([]interface{}{RFQRequestBody{
+		From:            addr.Hex(),
+		Data:            signableData,
+		SignatureString: signRFQRequest(t, requestorKey, signableData),
+	}})
+	require.NoError(t, err)
+
+	rec := postRPC(t, s, `{"jsonrpc": "2.0", "id": "a", "method": "rfq_submitRequest", "params": `+string(params)+`}`)
+	var res struct {
+		ID     string
+		Result *types.Transaction
+		Error  *RPCError
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.Nil(t, res.Error)
+	assert.Equal(t, "a", res.ID)
+	tx := <-txChan
+	assert.Equal(t, tx.Hash(), res.Result.Hash())
+
+	// the submission checks are shared with the REST endpoint
+	rec = postRPC(t, s, `{"jsonrpc": "2.0", "id": "b", "method": "rfq_submitRequest", "params": [{"from": "`+addr.Hex()+`"}]}`)
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.NotNil(t, res.Error)
+	assert.Equal(t, RPCInvalidInput, res.Error.Code)
+	assert.Equal(t, errMissingData.Error(), res.Error.Message)
+}
+
+func TestRPCSubscribe(t *testing.T) {
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, &chainmocks.ChainInterface{}, nil)
+	e := echo.New()
+	e.GET("/rpc", s.handleRPCWebsocket)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/rpc", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	mkr := common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	require.NoError(t, conn.WriteJSON(RPCRequest{
+		JSONRPC: jsonrpcVersion,
+		ID:      json.RawMessage("1"),
+		Method:  "rfq_subscribe",
+		Params:  json.RawMessage(`["rfqs", {"baseToken": "` + mkr.Hex() + `"}]`),
+	}))
+	var res struct {
+		Result string
+		Error  *RPCError
+	}
+	require.NoError(t, conn.ReadJSON(&res))
+	require.Nil(t, res.Error)
+	require.NotEmpty(t, res.Result)
+
+	rfqTxHash := common.HexToHash("0x02")
+	s.BroadcastTx(newOpenRFQTx(t, rfqTxHash, mkr), types.OpenRFQTxType)
+
+	var note struct {
+		Method string
+		Params struct {
+			Subscription string
+			Result       Event
+		}
+	}
+	require.NoError(t, conn.ReadJSON(&note))
+	assert.Equal(t, rpcSubscriptionMethod, note.Method)
+	assert.Equal(t, res.Result, note.Params.Subscription)
+	assert.Equal(t, EventRFQOpened, note.Params.Result.Kind)
+	assert.Equal(t, rfqTxHash, note.Params.Result.RFQTxHash)
+}
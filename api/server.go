@@ -7,49 +7,41 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/big"
-	"sync"
 
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/OCAX-labs/rfqrelayer/common"
 	"github.com/OCAX-labs/rfqrelayer/core"
 	"github.com/OCAX-labs/rfqrelayer/core/types"
 	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/OCAX-labs/rfqrelayer/risk"
+	"github.com/OCAX-labs/rfqrelayer/tokens"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/go-kit/log"
-	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
 )
 
-var (
-	errInvalidAddress     = errors.New("invalid Ethereum address")
-	errInvalidChecksum    = errors.New("invalid Ethereum address checksum")
-	errInvalidSymbol      = errors.New("invalid token symbol")
-	errInvalidDecimals    = errors.New("invalid token decimals")
-	errInvalidAmount      = errors.New("invalid token amount")
-	errInvalidDuration    = errors.New("invalid RFQ duration")
-	errInvalidRequestorId = errors.New("invalid requestor ID")
-	errInvalidTimestamp   = errors.New("invalid timestamp")
-
-	// Secure this for production in terms of allowed origins
-	upgrader = websocket.Upgrader{
-		CheckOrigin: func(r *http.Request) bool {
-			return true
-		},
-	}
-
-	clients = make(map[*websocket.Conn]bool) // connected clients
-	mutex   = &sync.Mutex{}
-)
+var errMissingData = types.NewFieldError("data", types.ErrMissingField, "missing data")
 
 type TxResponse struct {
 	TxCount uint
 	Hashes  []string
 }
 
-type APIError struct {
-	Error string
+// IncludedTx is a transaction and the block it was included in.
+type IncludedTx struct {
+	Transaction *types.Transaction `json:"transaction"`
+	BlockHash   string             `json:"blockHash"`
+	BlockHeight uint64             `json:"blockHeight"`
+	// Index is the position of the transaction in the block
+	Index     uint64    `json:"index"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 type Block struct {
@@ -65,6 +57,44 @@ type Block struct {
 	TxResponse TxResponse
 }
 
+// submitError is a rejected submission and the response it is reported
+// with.
+type submitError struct {
+	status int
+	err    error
+	res    APIError
+	// retryAfter is how long a rate limited signer must wait
+	retryAfter time.Duration
+}
+
+// rejectSubmission rejects a submission with err, status is used when err is
+// not in the error catalogue.
+func rejectSubmission(status int, err error) *submitError {
+	status, res := apiError(status, err)
+	return &submitError{status: status, err: err, res: res}
+}
+
+func rateLimitSubmission(wait time.Duration) *submitError {
+	return &submitError{
+		status:     http.StatusTooManyRequests,
+		err:        errRateLimited,
+		res:        APIError{Code: CodeRateLimited, Error: errRateLimited.Error()},
+		retryAfter: wait,
+	}
+}
+
+func (e *submitError) Error() string { return e.err.Error() }
+
+func (e *submitError) Unwrap() error { return e.err }
+
+// write writes the error response for the rejected submission.
+func (e *submitError) write(c echo.Context) error {
+	if e.status == http.StatusTooManyRequests {
+		return rateLimited(c, e.retryAfter)
+	}
+	return c.JSON(e.status, e.res)
+}
+
 type RFQRequest struct {
 	From string
 	Data string
@@ -74,8 +104,9 @@ type RFQRequest struct {
 }
 
 type RFQRequestBody struct {
-	From string              `json:"from"`
-	Data *types.SignableData `json:"data"`
+	From            string              `json:"from"`
+	Data            *types.SignableData `json:"data"`
+	SignatureString string              `json:"signature"`
 }
 
 type QuoteBody struct {
@@ -84,6 +115,24 @@ type QuoteBody struct {
 	SignatureString string           `json:"signature"`
 }
 
+type QuoteProof struct {
+	types.QuoteProof
+	ClosedRFQTxHash common.Hash    `json:"closedRFQTxHash"`
+	Validator       common.Address `json:"validator"`
+	V               *big.Int       `json:"v"`
+	R               *big.Int       `json:"r"`
+	S               *big.Int       `json:"s"`
+	// BlockHash and BlockHeight identify the validator signed block holding
+	// the closed RFQ transaction
+	BlockHash   common.Hash `json:"blockHash"`
+	BlockHeight uint64      `json:"blockHeight"`
+}
+
+type EncryptionKey struct {
+	PublicKey string `json:"publicKey"`
+	Threshold int    `json:"threshold"`
+}
+
 type Header struct {
 	Version        uint64      `json:"version" gencodec:"required"`
 	TxHash         common.Hash `json:"txRoot" gencodec:"required"`
@@ -98,6 +147,71 @@ type ServerConfig struct {
 	Logger     log.Logger
 	ListenAddr string
 	PrivateKey *cryptoocax.PrivateKey
+	// GRPCListenAddr is where the gRPC API is served, it is not served when
+	// empty
+	GRPCListenAddr string
+
+	// ThresholdKey is the validators' group key quotes must be sealed to.
+	// Quotes are accepted in the clear when it is nil.
+	ThresholdKey        *threshold.PublicKey
+	DecryptionThreshold int
+
+	// ParticipantCh receives participant records accepted by the API so they
+	// can be gossiped to the other nodes
+	ParticipantCh chan<- *types.Participant
+
+	// TokenRegistry lists the tokens RFQs and quotes may use, any token is
+	// accepted when it is nil
+	TokenRegistry *tokens.Registry
+
+	// DealerGroups are the named sets of market makers private RFQs may be
+	// sent to
+	DealerGroups types.DealerGroups
+
+	// RiskChecker applies requestors' pre-trade limits to new RFQs, no
+	// limits are checked when it is nil
+	RiskChecker *risk.Checker
+
+	// RateLimits limits submissions per client IP and signer address,
+	// defaults to DefaultRateLimits
+	RateLimits *RateLimits
+
+	// EventStore persists the log of lifecycle events clients resume from,
+	// the log is only kept in memory when it is nil
+	EventStore EventStore
+	// EventLogSize is how many events are kept, defaults to 10000
+	EventLogSize int
+
+	// RFQIndex pages, filters and sorts the RFQ listings. Without it the
+	// listings are returned whole and reject the listing parameters
+	RFQIndex RFQIndex
+
+	// MarketData serves the market data aggregated from closed auctions, the
+	// market data endpoints answer 501 without it
+	MarketData MarketData
+
+	// RequireAuth rejects requests that are not signed by the caller,
+	// otherwise unsigned requests are served without filtering
+	RequireAuth bool
+	// AuthWindow is how far a signed request's timestamp may be from the
+	// node's clock, defaults to 5 minutes
+	AuthWindow time.Duration
+
+	// IdempotencyWindow is how long the responses to requests made with an
+	// Idempotency-Key are kept for retries, defaults to 24 hours
+	IdempotencyWindow time.Duration
+
+	// WebhookStore persists webhook registrations and their dead-letter
+	// queues, they are only kept in memory when it is nil
+	WebhookStore WebhookStore
+	// WebhookPolicy configures webhook retries, defaults to
+	// DefaultWebhookPolicy
+	WebhookPolicy *WebhookPolicy
+
+	// NodeAdmin serves the admin endpoints to requests signed by
+	// AdminAddress, they answer 501 without it
+	NodeAdmin    NodeAdmin
+	AdminAddress common.Address
 }
 
 type Server struct {
@@ -105,100 +219,223 @@ type Server struct {
 	// newRFQChan chan *types.RFQRequest // <- add this line
 
 	ServerConfig
-	bc core.ChainInterface
+	bc      core.ChainInterface
+	replays *replayGuard
+	limiter *submissionLimiter
+	// idempotency holds the responses of POST requests with idempotency keys
+	idempotency *idempotencyStore
+	hub         *eventHub
+	events      *eventLog
+	webhooks    *webhookDispatcher
+
+	echo *echo.Echo
+	// closing is closed when Shutdown starts
+	closing   chan struct{}
+	closeOnce sync.Once
+	// closeDeadline is the deadline of the shutdown, if it has one, set
+	// before closing is closed
+	closeDeadline time.Time
+
+	mu   sync.Mutex
+	grpc *grpc.Server
 }
 
 func NewServer(cfg ServerConfig, bc core.ChainInterface, txChan chan *types.Transaction) *Server {
-	return &Server{
+	if cfg.AuthWindow == 0 {
+		cfg.AuthWindow = defaultAuthWindow
+	}
+	if cfg.RateLimits == nil {
+		cfg.RateLimits = DefaultRateLimits()
+	}
+	if cfg.EventLogSize == 0 {
+		cfg.EventLogSize = defaultEventLogSize
+	}
+	if cfg.IdempotencyWindow == 0 {
+		cfg.IdempotencyWindow = defaultIdempotencyWindow
+	}
+	if cfg.WebhookPolicy == nil {
+		cfg.WebhookPolicy = DefaultWebhookPolicy()
+	}
+	s := &Server{
 		ServerConfig: cfg,
 		bc:           bc,
 		txChan:       txChan,
-	}
+		replays:      newReplayGuard(cfg.AuthWindow),
+		limiter:      newSubmissionLimiter(cfg.RateLimits),
+		idempotency:  newIdempotencyStore(cfg.IdempotencyWindow),
+		hub:          newEventHub(),
+		events:       newEventLog(cfg.EventStore, cfg.EventLogSize),
+		webhooks:     newWebhookDispatcher(cfg.WebhookPolicy, cfg.WebhookStore, cfg.Logger),
+		closing:      make(chan struct{}),
+	}
+	s.echo = s.routes()
+	if err := s.loadEvents(); err != nil && s.Logger != nil {
+		s.Logger.Log("level", "error", "msg", "failed to load event log", "err", err)
+	}
+	if err := s.webhooks.load(); err != nil && s.Logger != nil {
+		s.Logger.Log("level", "error", "msg", "failed to load webhooks", "err", err)
+	}
+	return s
 }
 
-func (s *Server) Start() error {
+// routes returns the echo instance serving the API.
+func (s *Server) routes() *echo.Echo {
 	e := echo.New()
+	e.HideBanner = true
 	// go func() {
 	// 	for newRFQRequest := range s.newRFQChan {
 	// 		s.broadcastNewRFQRequest(newRFQRequest)
 	// 	}
 	// }()
+	// client IPs are rate limited so they must not be taken from spoofable headers
+	e.IPExtractor = echo.ExtractIPDirect()
+	e.Use(s.authenticate)
 
 	e.GET("/block/:hashorid", s.handleGetBlock)
 	e.GET("/headers/:height", s.handleGetHeaders)
 	e.GET("/tx/:hash", s.handleGetTx)
-	e.POST("/tx", s.handlePostTx)
+	e.POST("/tx", s.handlePostTx, s.idempotent, s.limitIP)
 	e.GET("/rfqs", s.handleGetRFQRequests)
-	e.POST("/rfqs", s.handlePostRFQRequest)
+	e.POST("/rfqs", s.handlePostRFQRequest, s.idempotent, s.limitIP)
 	e.GET("/openRFQs", s.handleGetOpenRFQRequests)
 	e.GET("/openRFQs/:txHash", s.handleGetOpenRFQRequest)
 	e.GET("/closedRFQs", s.handleGetClosedRFQRequests)
 	e.GET("/quotes/:rfqTxHash", s.handleGetAuctionQuotes)
-	e.POST("/quotes", s.handlePostQuote)
-
-	// websockets for broadcast of RFQRequest
+	e.GET("/quotes/:rfqTxHash/proof/:quoteHash", s.handleGetQuoteProof)
+	e.POST("/quotes", s.handlePostQuote, s.idempotent, s.limitIP)
+	e.POST("/quotes/batch", s.handlePostQuoteBatch, s.idempotent)
+	e.GET("/encryptionKey", s.handleGetEncryptionKey)
+	e.GET("/participants", s.handleGetParticipants)
+	e.GET("/participants/:address", s.handleGetParticipant)
+	e.POST("/participants", s.handlePostParticipant, s.idempotent, s.limitIP)
+	e.GET("/stats/rateLimits", s.handleGetRateLimits)
+	e.GET("/stats/pairs", s.handleGetMarketStats)
+	e.GET("/stats/pairs/:baseToken/:quoteToken", s.handleGetPairStats)
+	e.GET("/stats/pairs/:baseToken/:quoteToken/history", s.handleGetPairStatsHistory)
+	e.GET("/risk/:address", s.handleGetRiskUtilisation)
+
+	// websocket and Server-Sent Events feeds of RFQ lifecycle events
 	e.GET("/ws", s.handleWsConnections)
+	e.GET("/events", s.handleGetEvents)
+
+	// webhooks the signed caller has events posted to
+	e.POST("/webhooks", s.handlePostWebhook, s.idempotent)
+	e.GET("/webhooks", s.handleGetWebhooks)
+	e.DELETE("/webhooks/:id", s.handleDeleteWebhook)
+	e.GET("/webhooks/:id/deliveries", s.handleGetWebhookDeliveries)
+	e.GET("/webhooks/:id/deadLetters", s.handleGetDeadLetters)
+	e.POST("/webhooks/:id/deadLetters/:deliveryId", s.handleRedeliverDeadLetter)
+
+	// node administration for the operator
+	admin := e.Group("/admin", s.requireAdmin)
+	admin.GET("/status", s.handleGetNodeStatus)
+	admin.GET("/peers", s.handleGetPeers)
+	admin.DELETE("/peers/:addr", s.handleDisconnectPeer)
+	admin.GET("/mempool", s.handleGetMempool)
+	admin.POST("/blockProduction/pause", s.handlePauseBlockProduction)
+	admin.POST("/blockProduction/resume", s.handleResumeBlockProduction)
+
+	// JSON-RPC 2.0 over HTTP and websockets
+	e.POST("/rpc", s.handleRPC, s.idempotent)
+	e.GET("/rpc", s.handleRPCWebsocket)
+	return e
+}
+
+// Start serves the API on ListenAddr, and the gRPC API on GRPCListenAddr
+// when it is set, until Shutdown.
+func (s *Server) Start() error {
+	if s.GRPCListenAddr != "" {
+		go func() {
+			if err := s.StartGRPC(); err != nil && s.Logger != nil {
+				s.Logger.Log("level", "error", "msg", "gRPC API stopped", "err", err)
+			}
+		}()
+	}
 
-	return e.Start(s.ListenAddr)
+	if err := s.echo.Start(s.ListenAddr); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
 }
 
 func (s *Server) handlePostTx(c echo.Context) error {
+	// RFQ requests sent as transactions share the allowance of POST /rfqs
+	const endpoint = http.MethodPost + " /rfqs"
+	if err := s.limitSubmissionIP(endpoint, c.RealIP()); err != nil {
+		return err.write(c)
+	}
 	txRequest := new(types.Transaction)
 	if err := c.Bind(txRequest); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
 	}
 
 	if err := txRequest.Validate(); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
 	}
-	// so we are not constrained by block time - we save this in a custom table
-	// to allow fast access to the db
-	s.bc.WriteRFQTxs(txRequest)
-
-	// Broadcast to the blockchain
-	s.txChan <- txRequest
-
-	return c.JSON(http.StatusAccepted, txRequest)
+	// clients only send RFQ requests, the other transactions are created by
+	// validators
+	if txRequest.Type() != types.RFQRequestTxType {
+		return writeError(c, http.StatusBadRequest, types.ErrInvalidTxType)
+	}
+	if err := s.validateRFQRequest(txRequest.EmbeddedData().(*types.SignableData)); err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	signedTx, serr := s.admitRFQRequest(endpoint, txRequest)
+	if serr != nil {
+		return serr.write(c)
+	}
+	return c.JSON(http.StatusAccepted, signedTx)
 }
 
 func (s *Server) handleGetTx(c echo.Context) error {
-	hash := c.Param("hash")
+	hash := strings.TrimPrefix(c.Param("hash"), "0x")
 
 	b, err := hex.DecodeString(hash)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
 	}
 	hashFromBytes := common.HashFromBytes(b)
-	tx, err := s.bc.GetTxByHash(hashFromBytes)
+	tx, inclusion, err := s.bc.GetIncludedTx(hashFromBytes)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	// private transactions are reported as missing rather than revealing
+	// they exist
+	if !s.txVisible(callerFrom(c), tx) {
+		return writeError(c, http.StatusNotFound, core.ErrTxNotFound)
 	}
 
-	return c.JSON(http.StatusOK, tx)
+	return c.JSON(http.StatusOK, IncludedTx{
+		Transaction: tx,
+		BlockHash:   inclusion.BlockHash.String(),
+		BlockHeight: inclusion.BlockHeight,
+		Index:       inclusion.Index,
+		Timestamp:   time.Unix(0, int64(inclusion.Timestamp)),
+	})
 }
 
 func (s *Server) handleGetBlock(c echo.Context) error {
 	hashOrID := c.Param("hashorid")
 
+	// a hash can be all digits, so only shorter values are read as heights
 	height, err := strconv.Atoi(hashOrID)
-	if err == nil {
-		fmt.Println("height", height)
+	if err == nil && len(hashOrID) < 2*common.HashLength {
 		block, err := s.bc.GetBlock(big.NewInt(int64(height)))
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+			return writeError(c, http.StatusBadRequest, err)
 		}
 
 		return c.JSON(http.StatusOK, intoJSONBlock(block))
 	}
 
-	b, err := hex.DecodeString(hashOrID)
-	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+	b, err := hex.DecodeString(strings.TrimPrefix(hashOrID, "0x"))
+	if err != nil || len(b) != common.HashLength {
+		return writeError(c, http.StatusBadRequest, errInvalidHash)
 	}
 
 	block, err := s.bc.GetBlockByHash(common.HashFromBytes(b))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusInternalServerError, err)
 	}
 
 	return c.JSON(http.StatusOK, intoJSONBlock(block))
@@ -208,47 +445,104 @@ func (s *Server) handleGetHeaders(c echo.Context) error {
 	height := c.Param("height")
 	h, err := strconv.Atoi(height)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
 	}
 	hHeight := big.NewInt(int64(h))
 	header, err := s.bc.GetBlockHeader(hHeight)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
 	}
 	return c.JSON(http.StatusOK, intoJSONHeader(header))
 }
 
 func (s *Server) handleGetRFQRequests(c echo.Context) error {
+	caller := callerFrom(c)
+	if s.RFQIndex != nil {
+		q, err := rfqQuery(c.QueryParams(), "")
+		if err != nil {
+			return queryError(c, err)
+		}
+		rfqRequests, next, err := s.RFQIndex.QueryRFQRequests(q, func(rfqRequest *types.RFQRequest) bool {
+			return s.rfqRequestVisible(caller, rfqRequest)
+		})
+		if err != nil {
+			return queryError(c, err)
+		}
+		setNextCursor(c, next)
+		return c.JSON(http.StatusOK, intoJSONRFQ(rfqRequests))
+	}
+	if paged(c.QueryParams()) {
+		return writeError(c, http.StatusNotImplemented, errNoRFQIndex)
+	}
+
 	rfqRequests, err := s.bc.GetRFQRequests()
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	visible := make([]*types.RFQRequest, 0, len(rfqRequests))
+	for _, rfqRequest := range rfqRequests {
+		if s.rfqRequestVisible(caller, rfqRequest) {
+			visible = append(visible, rfqRequest)
+		}
 	}
 
-	return c.JSON(http.StatusOK, intoJSONRFQ(rfqRequests))
+	return c.JSON(http.StatusOK, intoJSONRFQ(visible))
 }
 
 func (s *Server) handleGetOpenRFQRequests(c echo.Context) error {
+	if s.RFQIndex != nil {
+		return s.queryOpenRFQs(c, types.RFQStatusOpen)
+	}
+	if paged(c.QueryParams()) {
+		return writeError(c, http.StatusNotImplemented, errNoRFQIndex)
+	}
 	rfqRequests, err := s.bc.GetOpenRFQRequests()
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusInternalServerError, err)
 	}
-	return c.JSON(http.StatusOK, intoJSONOpenRFQS(rfqRequests))
+	return c.JSON(http.StatusOK, intoJSONOpenRFQS(s.visibleRFQs(callerFrom(c), rfqRequests)))
+}
+
+// queryOpenRFQs responds with a page of the visible RFQs with the given
+// status.
+func (s *Server) queryOpenRFQs(c echo.Context, status types.RFQStatus) error {
+	q, err := rfqQuery(c.QueryParams(), status)
+	if err != nil {
+		return queryError(c, err)
+	}
+	caller := callerFrom(c)
+	rfqs, next, err := s.RFQIndex.QueryOpenRFQs(q, func(rfq *types.OpenRFQ) bool {
+		return s.rfqVisible(caller, rfq.Data)
+	})
+	if err != nil {
+		return queryError(c, err)
+	}
+	for i, rfq := range rfqs {
+		rfqs[i] = s.filterRFQ(caller, rfq)
+	}
+	setNextCursor(c, next)
+	return c.JSON(http.StatusOK, intoJSONOpenRFQS(rfqs))
 }
 
 func (s *Server) handleGetOpenRFQRequest(c echo.Context) error {
 	hash := c.Param("txHash")
 	b, err := hex.DecodeString(hash)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
 	}
 	hashFromBytes := common.HashFromBytes(b)
 
 	rfqRequest, err := s.bc.GetOpenRFQByHash(hashFromBytes)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	caller := callerFrom(c)
+	// private RFQs are reported as missing rather than revealing they exist
+	if !s.rfqVisible(caller, rfqRequest.Data) {
+		return writeError(c, http.StatusNotFound, core.ErrRFQNotFound)
 	}
 
-	return c.JSON(http.StatusOK, intoJSONOpenRFQ(rfqRequest))
+	return c.JSON(http.StatusOK, intoJSONOpenRFQ(s.filterRFQ(caller, rfqRequest)))
 }
 
 func (s *Server) handlePostRFQRequest(c echo.Context) error {
@@ -257,25 +551,86 @@ func (s *Server) handlePostRFQRequest(c echo.Context) error {
 	// Start by reading the request body into a byte slice
 	body, err := ioutil.ReadAll(c.Request().Body)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
 	}
 
 	// Then use json.Unmarshal to unmarshal the byte slice into signableData
 	err = json.Unmarshal(body, requestBody)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
+	}
+
+	signedTx, serr := s.submitRFQRequest(endpointKey(c), requestBody)
+	if serr != nil {
+		return serr.write(c)
 	}
+	return c.JSON(http.StatusAccepted, signedTx)
+
+}
 
+// submitRFQRequest checks a signed RFQ request and sends it to the chain.
+// endpoint selects the rate limit the signer is held to.
+func (s *Server) submitRFQRequest(endpoint string, requestBody *RFQRequestBody) (*types.Transaction, *submitError) {
 	signableData := requestBody.Data
+	if signableData == nil {
+		return nil, rejectSubmission(http.StatusBadRequest, errMissingData)
+	}
 	if err := signableData.Validate(); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return nil, rejectSubmission(http.StatusBadRequest, err)
+	}
+	if err := s.validateRFQRequest(signableData); err != nil {
+		return nil, rejectSubmission(http.StatusBadRequest, err)
+	}
+	if !common.IsHexAddress(requestBody.From) {
+		return nil, rejectSubmission(http.StatusBadRequest, types.ErrInvalidAddress)
 	}
 
-	rfqRequest := types.NewRFQRequest(s.PrivateKey.PublicKey().Address(), signableData)
-	txRFQRequest := types.NewTx(rfqRequest)
-	signedTx, err := txRFQRequest.Sign(*s.PrivateKey)
+	signature, err := cryptoocax.DeserializeSigFromHexString(requestBody.SignatureString)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return nil, rejectSubmission(http.StatusBadRequest, err)
+	}
+
+	// the request is signed by the requestor, the relayer only signs the OpenRFQ derived from it
+	rfqRequest := types.NewRFQRequest(common.HexToAddress(requestBody.From), signableData)
+	signedTx, err := types.NewTx(rfqRequest).WithSignature(types.NewSigner(), signature.ToBytes())
+	if err != nil {
+		return nil, rejectSubmission(http.StatusBadRequest, err)
+	}
+	return s.admitRFQRequest(endpoint, signedTx)
+}
+
+// validateRFQRequest checks the tokens and recipients of an RFQ request.
+func (s *Server) validateRFQRequest(signableData *types.SignableData) error {
+	if s.TokenRegistry != nil {
+		if err := s.TokenRegistry.ValidateRFQ(signableData); err != nil {
+			return err
+		}
+	}
+	_, err := s.DealerGroups.Recipients(signableData)
+	return err
+}
+
+// admitRFQRequest checks the signature of an RFQ request transaction and that
+// its sender may request it, then sends it to the chain.
+func (s *Server) admitRFQRequest(endpoint string, signedTx *types.Transaction) (*types.Transaction, *submitError) {
+	signableData := signedTx.EmbeddedData().(*types.SignableData)
+	if err := signedTx.Verify(); err != nil {
+		return nil, rejectSubmission(http.StatusBadRequest, err)
+	}
+	if allowed, wait := s.allowAddress(endpoint, *signedTx.From()); !allowed {
+		return nil, rateLimitSubmission(wait)
+	}
+	if err := s.bc.CheckParticipant(*signedTx.From(), types.RoleRequestor, signableData.BaseTokenAmount, nowMs()); err != nil {
+		return nil, rejectSubmission(http.StatusForbidden, err)
+	}
+	// the RFQ is counted against the requestor's limits as it is accepted, so
+	// concurrent submissions can't overrun them; the validator opening it
+	// finds it already counted
+	if s.RiskChecker != nil {
+		now := int64(nowMs())
+		if err := s.RiskChecker.Open(*signedTx.From(), signedTx.Hash(), signableData, now, now+int64(signableData.RFQDurationMs)); err != nil {
+			return nil, rejectSubmission(http.StatusForbidden, err)
+		}
 	}
 
 	s.bc.WriteRFQTxs(signedTx)
@@ -283,31 +638,84 @@ func (s *Server) handlePostRFQRequest(c echo.Context) error {
 	// Broadcast to the blockchain
 	s.txChan <- signedTx
 
-	return c.JSON(http.StatusAccepted, signedTx)
-
+	return signedTx, nil
 }
 
 func (s *Server) handleGetClosedRFQRequests(c echo.Context) error {
+	if s.RFQIndex != nil {
+		return s.queryOpenRFQs(c, types.RFQStatusClosed)
+	}
+	if paged(c.QueryParams()) {
+		return writeError(c, http.StatusNotImplemented, errNoRFQIndex)
+	}
 	rfqRequests, err := s.bc.GetClosedRFQRequests()
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusInternalServerError, err)
 	}
-
-	return c.JSON(http.StatusOK, intoJSONOpenRFQS(rfqRequests))
+	return c.JSON(http.StatusOK, intoJSONOpenRFQS(s.visibleRFQs(callerFrom(c), rfqRequests)))
 }
 
 func (s *Server) handleGetAuctionQuotes(c echo.Context) error {
 	rfqTxHash := c.Param("rfqTxHash")
 	b, err := hex.DecodeString(rfqTxHash)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
 	}
 	hashFromBytes := common.HashFromBytes(b)
+	caller := callerFrom(c)
+	// the quotes of private RFQs are reported as missing rather than
+	// revealing the RFQ exists
+	if !s.auctionVisible(caller, hashFromBytes) {
+		return writeError(c, http.StatusNotFound, core.ErrRFQNotFound)
+	}
 	auctionQuotes, err := s.bc.GetAuctionQuotes(hashFromBytes)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, s.filterQuotes(caller, hashFromBytes, auctionQuotes))
+}
+
+func (s *Server) handleGetQuoteProof(c echo.Context) error {
+	rfqTxHash, err := hex.DecodeString(c.Param("rfqTxHash"))
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	quoteHash, err := hex.DecodeString(c.Param("quoteHash"))
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+
+	closedRFQ, err := s.bc.GetClosedRFQByHash(common.HashFromBytes(rfqTxHash))
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
 	}
-	return c.JSON(http.StatusOK, auctionQuotes)
+	proof, err := closedRFQ.Data.QuoteProof(common.HashFromBytes(quoteHash))
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	if quote := closedRFQ.Data.Quotes[proof.Index]; !quoteVisible(callerFrom(c), s.rfqRequestor(closedRFQ.Data.RFQTxHash), quote) {
+		return writeError(c, http.StatusForbidden, errQuoteNotVisible)
+	}
+
+	// the closed RFQ transaction is signed by the validator and included in a block,
+	// tying the quotes root to the chain; until it is the proof has nothing
+	// to be checked against
+	closedRFQTxHash := types.NewTx(closedRFQ).Hash()
+	_, inclusion, err := s.bc.GetIncludedTx(closedRFQTxHash)
+	if err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+
+	return c.JSON(http.StatusOK, QuoteProof{
+		QuoteProof:      *proof,
+		ClosedRFQTxHash: closedRFQTxHash,
+		Validator:       closedRFQ.From,
+		V:               closedRFQ.V,
+		R:               closedRFQ.R,
+		S:               closedRFQ.S,
+		BlockHash:       inclusion.BlockHash,
+		BlockHeight:     inclusion.BlockHeight,
+	})
 }
 
 func (s *Server) handlePostQuote(c echo.Context) error {
@@ -315,24 +723,70 @@ func (s *Server) handlePostQuote(c echo.Context) error {
 	var quoteBody QuoteBody
 	err := decoder.Decode(&quoteBody)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	_, openRFQ, serr := s.submitQuote(endpointKey(c), &quoteBody)
+	if serr != nil {
+		return serr.write(c)
+	}
+	return c.JSON(http.StatusCreated, openRFQ)
+}
+
+// submitQuote checks a signed quote against its open RFQ and sends it to the
+// chain. It returns the quote transaction and the RFQ it was added to.
+func (s *Server) submitQuote(endpoint string, quoteBody *QuoteBody) (*types.Transaction, *types.OpenRFQ, *submitError) {
+	signedTx, openRFQ, serr := s.prepareQuote(quoteBody)
+	if serr != nil {
+		return nil, nil, serr
+	}
+	if serr := s.acceptQuote(endpoint, signedTx, openRFQ); serr != nil {
+		return nil, nil, serr
+	}
+	return signedTx, openRFQ, nil
+}
+
+// prepareQuote validates a quote against its open RFQ and recovers its
+// signer. It doesn't change any state so quotes can be prepared concurrently.
+func (s *Server) prepareQuote(quoteBody *QuoteBody) (*types.Transaction, *types.OpenRFQ, *submitError) {
+	if quoteBody.Data == nil {
+		return nil, nil, rejectSubmission(http.StatusBadRequest, errMissingData)
 	}
 	rfqTxHash := quoteBody.Data.RFQTxHash
 	openRFQ, err := s.bc.GetOpenRFQByHash(rfqTxHash)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "RFQ does not exist or has already expired"})
+		return nil, nil, rejectSubmission(http.StatusNotFound, err)
 	}
-	if time.Now().Unix() > openRFQ.Data.RFQEndTime {
-		return c.JSON(http.StatusBadRequest, APIError{Error: "RFQ is no longer open"})
+	if int64(nowMs()) > openRFQ.Data.RFQEndTime {
+		return nil, nil, rejectSubmission(http.StatusConflict, errRFQClosed)
 	}
 	quoteData := quoteBody.Data
 	if err := quoteData.Validate(); err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return nil, nil, rejectSubmission(http.StatusBadRequest, err)
+	}
+	if s.TokenRegistry != nil {
+		if err := s.TokenRegistry.ValidateQuote(quoteData); err != nil {
+			return nil, nil, rejectSubmission(http.StatusBadRequest, err)
+		}
+	}
+	if err := quoteData.CheckRFQ(openRFQ.Data.RFQRequest); err != nil {
+		return nil, nil, rejectSubmission(http.StatusBadRequest, err)
+	}
+	if err := s.bc.CheckParticipant(common.HexToAddress(quoteBody.From), types.RoleMarketMaker, quoteData.BaseTokenAmount, nowMs()); err != nil {
+		return nil, nil, rejectSubmission(http.StatusForbidden, err)
+	}
+	if s.ThresholdKey != nil {
+		// prices must stay hidden from every node until the auction closes
+		if !quoteData.IsSealed() {
+			return nil, nil, rejectSubmission(http.StatusBadRequest, errPricesNotSealed)
+		}
+		if _, err := quoteData.Ciphertext(); err != nil {
+			return nil, nil, rejectSubmission(http.StatusBadRequest, err)
+		}
 	}
 
 	signature, err := cryptoocax.DeserializeSigFromHexString(quoteBody.SignatureString)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return nil, nil, rejectSubmission(http.StatusBadRequest, err)
 	}
 
 	quote := types.NewQuote(common.HexToAddress(quoteBody.From), quoteBody.Data)
@@ -341,22 +795,91 @@ func (s *Server) handlePostQuote(c echo.Context) error {
 
 	signedTx, err := quoteTx.WithSignature(signer, signature.ToBytes())
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return nil, nil, rejectSubmission(http.StatusBadRequest, err)
 	}
 
 	fmt.Printf("HANDLER signedTx: %+v\n", signedTx)
 
 	err = signedTx.Verify()
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, APIError{Error: err.Error()})
+		return nil, nil, rejectSubmission(http.StatusBadRequest, err)
+	}
+	return signedTx, openRFQ, nil
+}
+
+// acceptQuote applies the signer's rate limit and the RFQ's recipients to a
+// prepared quote and sends it to the chain.
+func (s *Server) acceptQuote(endpoint string, signedTx *types.Transaction, openRFQ *types.OpenRFQ) *submitError {
+	if allowed, wait := s.allowAddress(endpoint, *signedTx.From()); !allowed {
+		return rateLimitSubmission(wait)
+	}
+	if err := openRFQ.Data.CheckQuoter(*signedTx.From()); err != nil {
+		return rejectSubmission(http.StatusForbidden, err)
 	}
 	// update the openRFQ in memory
 	s.bc.WriteRFQTxs(signedTx)
 	s.txChan <- signedTx
 
-	s.bc.UpdateActiveRFQ(rfqTxHash, quote)
+	quoteData := signedTx.EmbeddedData().(*types.QuoteData)
+	s.bc.UpdateActiveRFQ(quoteData.RFQTxHash, types.NewQuote(*signedTx.From(), quoteData))
+	return nil
+}
 
-	return c.JSON(http.StatusCreated, openRFQ)
+func (s *Server) handleGetEncryptionKey(c echo.Context) error {
+	if s.ThresholdKey == nil {
+		return writeError(c, http.StatusNotFound, errEncryptionDisabled)
+	}
+	return c.JSON(http.StatusOK, EncryptionKey{
+		PublicKey: hexutil.Encode(s.ThresholdKey.Bytes()),
+		Threshold: s.DecryptionThreshold,
+	})
+}
+
+func (s *Server) handleGetParticipants(c echo.Context) error {
+	participants, err := s.bc.GetParticipants()
+	if err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, participants)
+}
+
+func (s *Server) handleGetParticipant(c echo.Context) error {
+	address := c.Param("address")
+	if !common.IsHexAddress(address) {
+		return writeError(c, http.StatusBadRequest, types.ErrInvalidAddress)
+	}
+	participant, err := s.bc.GetParticipant(common.HexToAddress(address))
+	if err != nil {
+		if errors.Is(err, core.ErrParticipantNotFound) {
+			err = fmt.Errorf("%w: %s", errUnknownParticipant, address)
+		}
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, participant)
+}
+
+// handlePostParticipant adds or updates a participant. The record must be
+// signed by the registry admin, the node itself holds no admin key.
+func (s *Server) handlePostParticipant(c echo.Context) error {
+	participant := new(types.Participant)
+	if err := json.NewDecoder(c.Request().Body).Decode(participant); err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+
+	if err := s.bc.WriteParticipant(participant); err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+
+	if s.ParticipantCh != nil {
+		s.ParticipantCh <- participant
+	}
+
+	return c.JSON(http.StatusCreated, participant)
+}
+
+// nowMs returns the current unix time in milliseconds.
+func nowMs() uint64 {
+	return uint64(time.Now().UnixNano() / int64(time.Millisecond))
 }
 
 // Check that the RFQ is still open and not completed
@@ -443,82 +966,3 @@ func intoJSONHeader(header *types.Header) Header {
 		Hash:           header.Hash().Hex(),
 	}
 }
-
-func (s *Server) handleWsConnections(c echo.Context) error {
-	fmt.Println("WEBSOCKETS: ws connection")
-	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
-	if err != nil {
-		return err
-	}
-	defer ws.Close()
-
-	mutex.Lock()
-	clients[ws] = true
-	mutex.Unlock()
-
-	// Start a separate goroutine that waits for messages from the client.
-	go func() {
-		defer func() {
-			mutex.Lock()
-			delete(clients, ws)
-			mutex.Unlock()
-		}()
-
-		for {
-			var msg string
-			err := ws.ReadJSON(&msg)
-			if err != nil {
-				s.Logger.Log("level", "error", "msg", err)
-				break
-			}
-			// For now, just print out the received messages.
-			fmt.Printf("received: %v\n", msg)
-		}
-	}()
-
-	// Wait for the connection to close.
-	// refactor: hack to keep the connection open
-	for {
-		if _, ok := clients[ws]; !ok {
-			break
-		}
-		time.Sleep(1 * time.Second)
-	}
-
-	return nil
-}
-
-func (s *Server) BroadcastTx(tx *types.Transaction, txType byte) {
-	fmt.Println("WEBSOCKETS: broadcasting  tx")
-	var data []byte
-	var err error
-	switch txType {
-	case types.OpenRFQTxType:
-		data, err = json.Marshal(tx.EmbeddedData().(*types.RFQData)) // Replace with your RFQRequest marshalling code
-		if err != nil {
-			s.Logger.Log("level", "error", "broadcast error", err)
-			return
-		}
-	case types.QuoteTxType:
-		data, err = json.Marshal(tx.EmbeddedData().(*types.QuoteData))
-	case types.ClosedRFQTxType:
-		data, err = json.Marshal(tx.EmbeddedData().(types.OpenRFQ))
-	default:
-		s.Logger.Log("level", "error", "broadcast error", "invalid tx type")
-	}
-	if err != nil {
-		s.Logger.Log("level", "error", "broadcast error", err)
-		return
-	}
-	s.broadcastToWS(data)
-}
-
-func (s *Server) broadcastToWS(data []byte) {
-	for client := range clients {
-		if err := client.WriteMessage(websocket.TextMessage, data); err != nil {
-			s.Logger.Log("level", "error", "error", err)
-			client.Close()
-			delete(clients, client)
-		}
-	}
-}
@@ -7,11 +7,16 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
 	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/risk"
+	"github.com/go-kit/log"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
 	"github.com/OCAX-labs/rfqrelayer/core/types"
@@ -23,7 +28,8 @@ func TestHandlePostRFQRequest(t *testing.T) {
 	e := echo.New()
 	// Initialize an instance of your Server type (replace with your actual initialization code)
 	privateKey := cryptoocax.GeneratePrivateKey()
-	addr := privateKey.PublicKey().Address()
+	requestorKey := cryptoocax.GeneratePrivateKey()
+	addr := requestorKey.PublicKey().Address()
 
 	mockChain := &chainmocks.ChainInterface{}
 	mockChain.On("WriteRFQTxs", mock.Anything).Run(func(args mock.Arguments) {
@@ -33,6 +39,8 @@ func TestHandlePostRFQRequest(t *testing.T) {
 		assert.Equal(t, signedTx.EmbeddedData().(*types.SignableData).RequestorId, "0x0d1d4e623D10F9FBA5Db95830F7d3839406C6AF2")
 		assert.Equal(t, signedTx.EmbeddedData().(*types.SignableData).BaseTokenAmount, big.NewInt(1000000000000000000))
 	}).Return(nil)
+	mockChain.On("GetParticipant", addr).Return(nil, assert.AnError)
+	mockChain.On("CheckParticipant", addr, types.RoleRequestor, big.NewInt(1000000000000000000), mock.Anything).Return(nil)
 
 	txChan := make(chan *types.Transaction)
 	defer close(txChan)
@@ -71,10 +79,11 @@ func TestHandlePostRFQRequest(t *testing.T) {
 		RFQDurationMs:   10_000,
 	}
 
-	// Prepare the request body
+	// Prepare the request body, signed by the requestor
 	body, _ := json.Marshal(RFQRequestBody{
-		From: addr.String(),
-		Data: &signableData,
+		From:            addr.String(),
+		Data:            &signableData,
+		SignatureString: signRFQRequest(t, requestorKey, &signableData),
 	})
 	req := httptest.NewRequest(http.MethodPost, "/rfqs", bytes.NewReader(body))
 	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
@@ -100,3 +109,303 @@ func TestHandlePostRFQRequest(t *testing.T) {
 	}
 
 }
+
+func TestHandlePostRFQRequestSignerMismatch(t *testing.T) {
+	e := echo.New()
+	privateKey := cryptoocax.GeneratePrivateKey()
+	requestorKey := cryptoocax.GeneratePrivateKey()
+
+	mockChain := &chainmocks.ChainInterface{}
+	s := NewServer(ServerConfig{PrivateKey: &privateKey}, mockChain, make(chan *types.Transaction))
+
+	token := types.Token{
+		Symbol:   "ETH",
+		Decimals: 18,
+		Address:  common.HexToAddress("0x0d1d4e623D10F9FBA5Db95830F7d3839406C6AF2"),
+	}
+	signableData := types.SignableData{
+		RequestorId:     "0x0d1d4e623D10F9FBA5Db95830F7d3839406C6AF2",
+		BaseTokenAmount: big.NewInt(1000000000000000000),
+		BaseToken:       &token,
+		QuoteToken:      &token,
+		RFQDurationMs:   10_000,
+	}
+
+	// signed by one key but claiming to be from another address
+	body, _ := json.Marshal(RFQRequestBody{
+		From:            privateKey.PublicKey().Address().String(),
+		Data:            &signableData,
+		SignatureString: signRFQRequest(t, requestorKey, &signableData),
+	})
+	req := httptest.NewRequest(http.MethodPost, "/rfqs", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	assert.NoError(t, s.handlePostRFQRequest(e.NewContext(req, rec)))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	mockChain.AssertNotCalled(t, "WriteRFQTxs", mock.Anything)
+}
+
+// signRFQRequest signs the request as cmd/rfq does and returns the hex encoded signature.
+func signRFQRequest(t *testing.T, key cryptoocax.PrivateKey, data *types.SignableData) string {
+	signedTx, err := types.NewTx(types.NewRFQRequest(key.PublicKey().Address(), data)).Sign(key)
+	if err != nil {
+		t.Fatalf("failed to sign rfq request: %s", err.Error())
+	}
+	v, r, s := signedTx.RawSignatureValues()
+	sig := cryptoocax.Signature{V: v, R: r, S: s}
+	return sig.String()
+}
+
+func TestHandleGetTx(t *testing.T) {
+	key := cryptoocax.GeneratePrivateKey()
+	tx, err := types.NewTx(types.NewRFQRequest(key.PublicKey().Address(), &types.SignableData{
+		RequestorId:     "1",
+		BaseTokenAmount: big.NewInt(1000),
+		BaseToken:       &types.BaseToken{Symbol: "MKR", Decimals: 18},
+		QuoteToken:      &types.QuoteToken{Symbol: "WETH", Decimals: 18},
+		RFQDurationMs:   5000,
+	})).Sign(key)
+	assert.NoError(t, err)
+	missing := common.HexToHash("0x01")
+
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetIncludedTx", tx.Hash()).Return(tx, &types.TxInclusion{
+		BlockHash:   common.HexToHash("0x02"),
+		BlockHeight: 7,
+		Index:       3,
+	}, nil)
+	mockChain.On("GetIncludedTx", missing).Return(nil, nil, core.ErrTxNotFound)
+	s := NewServer(ServerConfig{}, mockChain, nil)
+	e := echo.New()
+	e.GET("/tx/:hash", s.handleGetTx)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tx/"+tx.Hash().Hex(), nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var res struct {
+		Transaction map[string]interface{} `json:"transaction"`
+		BlockHash   string                 `json:"blockHash"`
+		BlockHeight uint64                 `json:"blockHeight"`
+		Index       uint64                 `json:"index"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.NotEmpty(t, res.Transaction)
+	assert.Equal(t, common.HexToHash("0x02").Hex(), res.BlockHash)
+	assert.Equal(t, uint64(7), res.BlockHeight)
+	assert.Equal(t, uint64(3), res.Index)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tx/"+missing.Hex()[2:], nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandleGetBlockByHash(t *testing.T) {
+	missing := common.HexToHash("0x01")
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetBlockByHash", missing).Return(nil, core.ErrBlockNotFound)
+	s := NewServer(ServerConfig{}, mockChain, nil)
+	e := echo.New()
+	e.GET("/block/:hashorid", s.handleGetBlock)
+
+	for target, code := range map[string]int{
+		"/block/" + missing.Hex():     http.StatusNotFound,
+		"/block/" + missing.Hex()[2:]: http.StatusNotFound,
+		"/block/0x0102":               http.StatusBadRequest,
+		"/block/zz":                   http.StatusBadRequest,
+	} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		assert.Equal(t, code, rec.Code, target)
+	}
+}
+
+func TestPostRFQRequestReservesRiskLimits(t *testing.T) {
+	requestorKey := cryptoocax.GeneratePrivateKey()
+	addr := requestorKey.PublicKey().Address()
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("WriteRFQTxs", mock.Anything).Return(nil)
+	mockChain.On("CheckParticipant", addr, types.RoleRequestor, mock.Anything, mock.Anything).Return(nil)
+	mockChain.On("GetParticipant", addr).Return(nil, assert.AnError)
+	checker, err := risk.NewChecker(func(common.Address) *types.ParticipantLimits {
+		return &types.ParticipantLimits{MaxOpenAmount: big.NewInt(100)}
+	}, nil)
+	require.NoError(t, err)
+
+	txChan := make(chan *types.Transaction, 2)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger(), RiskChecker: checker}, mockChain, txChan)
+	e := echo.New()
+	e.POST("/rfqs", s.handlePostRFQRequest)
+	post := func(requestorID string) *httptest.ResponseRecorder {
+		data := &types.SignableData{
+			RequestorId:     requestorID,
+			BaseTokenAmount: big.NewInt(60),
+			BaseToken:       &types.BaseToken{Symbol: "MKR", Decimals: 18, Address: common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")},
+			QuoteToken:      &types.QuoteToken{Symbol: "USDC", Decimals: 6, Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")},
+			RFQDurationMs:   10_000,
+		}
+		body, _ := json.Marshal(RFQRequestBody{From: addr.String(), Data: data, SignatureString: signRFQRequest(t, requestorKey, data)})
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/rfqs", bytes.NewReader(body)))
+		return rec
+	}
+
+	// the first RFQ holds its amount before any validator opens it, so the
+	// second is rejected in the response
+	require.Equal(t, http.StatusAccepted, post("1").Code)
+	rec := post("2")
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	var res APIError
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	assert.Equal(t, CodeRiskLimit, res.Code)
+	assert.Len(t, txChan, 1)
+	assert.Equal(t, 1, checker.Utilisation(addr, int64(nowMs())).OpenRFQs)
+}
+
+func TestPostTxChecksParticipant(t *testing.T) {
+	requestorKey := cryptoocax.GeneratePrivateKey()
+	outsiderKey := cryptoocax.GeneratePrivateKey()
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("WriteRFQTxs", mock.Anything).Return(nil)
+	mockChain.On("CheckParticipant", requestorKey.PublicKey().Address(), types.RoleRequestor, mock.Anything, mock.Anything).Return(nil)
+	mockChain.On("CheckParticipant", outsiderKey.PublicKey().Address(), types.RoleRequestor, mock.Anything, mock.Anything).Return(core.ErrParticipantNotFound)
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, core.ErrParticipantNotFound)
+
+	txChan := make(chan *types.Transaction, 2)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, mockChain, txChan)
+	e := echo.New()
+	e.POST("/tx", s.handlePostTx)
+
+	// requests from addresses that aren't whitelisted never reach the chain
+	assert.Equal(t, http.StatusForbidden, postTx(t, e, rfqRequestTx(t, outsiderKey, "1")))
+	assert.Empty(t, txChan)
+	assert.Equal(t, http.StatusAccepted, postTx(t, e, rfqRequestTx(t, requestorKey, "1")))
+	assert.Len(t, txChan, 1)
+	mockChain.AssertNumberOfCalls(t, "WriteRFQTxs", 1)
+}
+
+func TestPostTxRateLimited(t *testing.T) {
+	requestorKey := cryptoocax.GeneratePrivateKey()
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("WriteRFQTxs", mock.Anything).Return(nil)
+	mockChain.On("CheckParticipant", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, core.ErrParticipantNotFound)
+
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger(), RateLimits: &RateLimits{
+		IP:      map[string]RateLimit{"POST /rfqs": {Rate: 0, Burst: 2}},
+		Address: map[string]RateLimit{"POST /rfqs": {Rate: 0, Burst: 1}},
+	}}, mockChain, make(chan *types.Transaction, 3))
+	e := echo.New()
+	e.POST("/tx", s.handlePostTx, s.limitIP)
+
+	// RFQ requests sent as transactions count against the limits of POST
+	// /rfqs, per signer and per IP
+	assert.Equal(t, http.StatusAccepted, postTx(t, e, rfqRequestTx(t, requestorKey, "1")))
+	assert.Equal(t, http.StatusTooManyRequests, postTx(t, e, rfqRequestTx(t, requestorKey, "2")))
+	assert.Equal(t, http.StatusTooManyRequests, postTx(t, e, rfqRequestTx(t, cryptoocax.GeneratePrivateKey(), "3")))
+	counters := s.limiter.counters()["POST /rfqs"]
+	assert.Equal(t, uint64(1), counters.LimitedAddr)
+	assert.Equal(t, uint64(1), counters.LimitedIP)
+}
+
+// rfqRequestTx returns an RFQ request transaction signed by key.
+func rfqRequestTx(t *testing.T, key cryptoocax.PrivateKey, requestorID string) *types.Transaction {
+	t.Helper()
+	tx, err := types.NewTx(types.NewRFQRequest(key.PublicKey().Address(), &types.SignableData{
+		RequestorId:     requestorID,
+		BaseTokenAmount: big.NewInt(60),
+		BaseToken:       &types.BaseToken{Symbol: "MKR", Decimals: 18, Address: common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")},
+		QuoteToken:      &types.QuoteToken{Symbol: "USDC", Decimals: 6, Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")},
+		RFQDurationMs:   10_000,
+	})).Sign(key)
+	require.NoError(t, err)
+	return tx
+}
+
+// postTx posts tx to /tx and returns the response status.
+func postTx(t *testing.T, e *echo.Echo, tx *types.Transaction) int {
+	t.Helper()
+	body, err := tx.MarshalJSON()
+	require.NoError(t, err)
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/tx", bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	e.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestGetQuoteProofIncludesBlock(t *testing.T) {
+	validatorKey := cryptoocax.GeneratePrivateKey()
+	quoterKey := cryptoocax.GeneratePrivateKey()
+	rfqTxHash := common.HexToHash("0x01")
+	data := &types.RFQData{
+		RFQTxHash:  rfqTxHash,
+		RFQRequest: &types.SignableData{RequestorId: "1", BaseTokenAmount: big.NewInt(1000), BaseToken: &types.BaseToken{}, QuoteToken: &types.QuoteToken{}},
+		Status:     types.RFQStatusOpen,
+		Quotes: []*types.Quote{{
+			From: quoterKey.PublicKey().Address(),
+			Data: &types.QuoteData{RFQTxHash: rfqTxHash, BaseToken: &types.BaseToken{}, QuoteToken: &types.QuoteToken{}, BaseTokenAmount: big.NewInt(1000), BidPrice: big.NewInt(100), AskPrice: big.NewInt(200)},
+			V:    big.NewInt(0), R: big.NewInt(1), S: big.NewInt(1),
+		}},
+	}
+	data.Close()
+	closedRFQ := types.NewOpenRFQ(validatorKey.PublicKey().Address(), data)
+	closedTx, err := types.NewTx(closedRFQ).Sign(validatorKey)
+	require.NoError(t, err)
+
+	// the validator seals the closed RFQ in a block it signs
+	block := types.NewBlock(&types.Header{Version: 1, Height: big.NewInt(4), Timestamp: uint64(time.Now().UnixNano())}, types.Transactions{closedTx}, validatorKey.PublicKey())
+	require.NoError(t, block.Sign(validatorKey))
+
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, core.ErrParticipantNotFound)
+	mockChain.On("GetClosedRFQByHash", rfqTxHash).Return(closedRFQ, nil)
+	mockChain.On("GetRFQRequestByHash", rfqTxHash).Return(nil, core.ErrRFQNotFound)
+	mockChain.On("GetIncludedTx", closedTx.Hash()).Return(closedTx, &types.TxInclusion{BlockHash: block.Hash(), BlockHeight: block.Height().Uint64()}, nil).Once()
+	mockChain.On("GetIncludedTx", closedTx.Hash()).Return(nil, nil, core.ErrTxNotFound)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, mockChain, nil)
+	e := echo.New()
+	e.Use(s.authenticate)
+	e.GET("/quotes/:rfqTxHash/proof/:quoteHash", s.handleGetQuoteProof)
+	path := "/quotes/" + rfqTxHash.Hex()[2:] + "/proof/" + data.Quotes[0].Hash().Hex()[2:]
+
+	var proof QuoteProof
+	require.Equal(t, http.StatusOK, serveSigned(t, e, quoterKey, http.MethodGet, path, nil, &proof))
+	assert.True(t, proof.Verify())
+	assert.Equal(t, closedTx.Hash(), proof.ClosedRFQTxHash)
+	assert.Equal(t, block.Hash(), proof.BlockHash)
+	assert.Equal(t, uint64(4), proof.BlockHeight)
+
+	// the block the proof points at is signed by the validator that signed
+	// the closed RFQ, and holds it
+	assert.NoError(t, block.Verify())
+	assert.Equal(t, proof.Validator, block.Validator.Address())
+	assert.Equal(t, proof.ClosedRFQTxHash, block.Transactions()[0].Hash())
+
+	// a closed RFQ not yet in a block has no proof
+	var res APIError
+	assert.Equal(t, http.StatusNotFound, serveSigned(t, e, quoterKey, http.MethodGet, path, nil, &res))
+	assert.Equal(t, CodeTxNotFound, res.Code)
+}
+
+func TestGetRiskUtilisationRequiresCaller(t *testing.T) {
+	requestorKey := cryptoocax.GeneratePrivateKey()
+	addr := requestorKey.PublicKey().Address()
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, core.ErrParticipantNotFound)
+	checker, err := risk.NewChecker(func(common.Address) *types.ParticipantLimits { return nil }, nil)
+	require.NoError(t, err)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger(), RiskChecker: checker}, mockChain, nil)
+	e := echo.New()
+	e.Use(s.authenticate)
+	e.GET("/risk/:address", s.handleGetRiskUtilisation)
+	path := "/risk/" + addr.Hex()
+
+	// unsigned callers can't read anyone's exposure
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	var res APIError
+	assert.Equal(t, http.StatusForbidden, serveSigned(t, e, cryptoocax.GeneratePrivateKey(), http.MethodGet, path, nil, &res))
+	assert.Equal(t, http.StatusOK, serveSigned(t, e, requestorKey, http.MethodGet, path, nil, nil))
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var errShuttingDown = errors.New("server is shutting down")
+
+// Shutdown stops the server gracefully. Event feed clients are disconnected,
+// websockets with a going away close frame, then the requests in flight are
+// left to complete, as are the gRPC calls, before the webhook deliveries
+// still pending are moved to the dead-letter queue. Whatever is still
+// running when ctx is done is cut off.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.closeOnce.Do(func() {
+		s.closeDeadline, _ = ctx.Deadline()
+		close(s.closing)
+	})
+
+	var errs []error
+	if err := s.hub.closeAll(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("error disconnecting event feed clients: %w", err))
+	}
+	if err := s.echo.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("error shutting down http server: %w", err))
+	}
+
+	s.mu.Lock()
+	gs := s.grpc
+	s.mu.Unlock()
+	if gs != nil {
+		stopped := make(chan struct{})
+		go func() {
+			gs.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			gs.Stop()
+			errs = append(errs, fmt.Errorf("error shutting down gRPC server: %w", ctx.Err()))
+		}
+	}
+
+	if err := s.webhooks.stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("error stopping webhook deliveries: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Server) shuttingDown() bool {
+	select {
+	case <-s.closing:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,138 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/go-kit/log"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdown(t *testing.T) {
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, assert.AnError)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger(), ListenAddr: "127.0.0.1:0"}, mockChain, nil)
+
+	started := make(chan error, 1)
+	go func() { started <- s.Start() }()
+	require.Eventually(t, func() bool { return s.echo.ListenerAddr() != nil }, 5*time.Second, 10*time.Millisecond)
+	addr := s.echo.ListenerAddr().String()
+
+	ws, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/ws?topic=rfqs", nil)
+	require.NoError(t, err)
+	defer ws.Close()
+	resp, err := http.Get("http://" + addr + "/events?topic=rfqs")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	// wait for both clients to be attached to the feed
+	require.Eventually(t, func() bool {
+		s.hub.mu.RLock()
+		defer s.hub.mu.RUnlock()
+		return len(s.hub.subscribers) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, s.Shutdown(ctx))
+	select {
+	case err := <-started:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return after Shutdown")
+	}
+
+	// the websocket is told the server is going away and the event stream
+	// ends
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		_, _, err = ws.ReadMessage()
+		if err != nil {
+			break
+		}
+	}
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), err)
+	_, err = bufio.NewReader(resp.Body).ReadString('\n')
+	assert.Error(t, err)
+
+	// new connections are refused
+	_, err = http.Get("http://" + addr + "/events?topic=rfqs")
+	assert.Error(t, err)
+	// shutting down again is harmless
+	assert.NoError(t, s.Shutdown(ctx))
+}
+
+func TestShutdownDeadLettersPendingWebhooks(t *testing.T) {
+	s, e := newWebhookServer(t, &WebhookPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, Timeout: time.Minute})
+	// the receiver holds on to the first delivery so the next one queues
+	// behind it
+	receiver := newWebhookReceiver(http.StatusOK)
+	defer receiver.Close()
+	release := make(chan struct{})
+	defer close(release)
+	handler := receiver.Config.Handler
+	receiver.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		handler.ServeHTTP(w, req)
+		select {
+		case <-req.Context().Done():
+		case <-release:
+		}
+	})
+	key := cryptoocax.GeneratePrivateKey()
+
+	var hook Webhook
+	require.Equal(t, http.StatusCreated, serveSigned(t, e, key, http.MethodPost, "/webhooks", WebhookRequest{URL: receiver.URL}, &hook))
+	s.BroadcastTx(newOpenRFQTx(t, common.HexToHash("0x01"), common.HexToAddress("0x01")), types.OpenRFQTxType)
+	s.BroadcastTx(newOpenRFQTx(t, common.HexToHash("0x02"), common.HexToAddress("0x01")), types.OpenRFQTxType)
+	receiver.next(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, s.Shutdown(ctx))
+
+	// the delivery in flight and the queued one are kept for redelivery, as
+	// are events raised after the shutdown
+	s.BroadcastTx(newOpenRFQTx(t, common.HexToHash("0x03"), common.HexToAddress("0x01")), types.OpenRFQTxType)
+	w, err := s.webhooks.worker(key.PublicKey().Address(), hook.ID)
+	require.NoError(t, err)
+	letters, err := s.webhooks.deadLetters(w)
+	require.NoError(t, err)
+	require.Len(t, letters, 3)
+	for i, letter := range letters {
+		assert.Equal(t, uint64(i+1), letter.Seq)
+		assert.Equal(t, errWebhookShutdown.Error(), letter.LastError)
+	}
+}
+
+func TestCloseAllStalledClients(t *testing.T) {
+	h := newEventHub()
+	stalled := make(chan struct{})
+	defer close(stalled)
+	for i := 0; i < 3; i++ {
+		h.register(newSubscriber(nil, 0, func() { <-stalled }))
+	}
+	healthy := newSubscriber(nil, 0, nil)
+	h.register(healthy)
+
+	// clients that don't take their close frame hold up neither each other
+	// nor the shutdown past its deadline, and events keep being published
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.ErrorIs(t, h.closeAll(ctx), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+	<-healthy.done
+	h.publish(&event{audience: func(*Caller) bool { return true }})
+	h.register(newSubscriber(nil, 0, nil))
+	assert.Empty(t, h.subscribers)
+}
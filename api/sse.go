@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// sseHeartbeat is how often a comment is sent on an idle event stream so
+// proxies don't drop it.
+const sseHeartbeat = 15 * time.Second
+
+var (
+	errNoTopic       = errors.New("at least one topic is required")
+	errInvalidLastID = errors.New("the Last-Event-ID header must be an event sequence number")
+	errNoStreaming   = errors.New("streaming is not supported")
+)
+
+// handleGetEvents serves the lifecycle events as Server-Sent Events. The
+// subscriptions are given in the query string as for the websocket feed and
+// each event is sent with its seq as the id and its kind as the event type,
+// so a reconnecting EventSource resumes from Last-Event-ID on its own.
+func (s *Server) handleGetEvents(c echo.Context) error {
+	reqs, since, err := subscriptionQuery(c.QueryParams())
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	if len(reqs) == 0 {
+		return writeError(c, http.StatusBadRequest, errNoTopic)
+	}
+	if value := c.Request().Header.Get("Last-Event-ID"); value != "" {
+		seq, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return writeError(c, http.StatusBadRequest, errInvalidLastID)
+		}
+		since = &seq
+	}
+	res := c.Response()
+	flusher, ok := res.Writer.(http.Flusher)
+	if !ok {
+		return writeError(c, http.StatusInternalServerError, errNoStreaming)
+	}
+
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// stop nginx from buffering the stream
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	sub := s.subscribe(callerFrom(c), reqs, since, false, nil)
+	defer s.hub.unregister(sub)
+	defer sub.close()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	ctx := c.Request().Context()
+
+	for {
+		select {
+		case msg := <-sub.send:
+			var err error
+			if msg.ev != nil {
+				_, err = fmt.Fprintf(res, "id: %d\nevent: %s\ndata: %s\n\n", msg.ev.Seq, msg.ev.Kind, msg.data)
+			} else {
+				_, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", messageError, msg.data)
+			}
+			if err != nil {
+				return nil
+			}
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case <-sub.done:
+			return nil
+		case <-ctx.Done():
+			return nil
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/go-kit/log"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sseMessage struct {
+	id    string
+	event string
+	data  string
+}
+
+// readSSE reads the next message from an event stream, skipping comments.
+func readSSE(t *testing.T, r *bufio.Reader) sseMessage {
+	t.Helper()
+	var msg sseMessage
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "":
+			if msg.data != "" {
+				return msg
+			}
+		case strings.HasPrefix(line, ":"):
+		case strings.HasPrefix(line, "id: "):
+			msg.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			msg.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			msg.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func TestServerSentEvents(t *testing.T) {
+	mkr := common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, &chainmocks.ChainInterface{}, nil)
+
+	e := echo.New()
+	e.GET("/events", s.handleGetEvents)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	for _, query := range []string{"", "?topic=quotes", "?topic=rfqs&since=x"} {
+		resp, err := http.Get(srv.URL + "/events" + query)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode, query)
+	}
+
+	for i := 1; i <= 2; i++ {
+		s.BroadcastTx(newOpenRFQTx(t, common.BigToHash(big.NewInt(int64(i))), mkr), types.OpenRFQTxType)
+	}
+
+	// Last-Event-ID takes precedence over since
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/events?topic=rfqs&baseToken="+mkr.Hex()+"&since=0", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// the missed event is replayed, then live events in the pair follow
+	s.BroadcastTx(newOpenRFQTx(t, common.BigToHash(big.NewInt(3)), weth), types.OpenRFQTxType)
+	s.BroadcastTx(newOpenRFQTx(t, common.BigToHash(big.NewInt(4)), mkr), types.OpenRFQTxType)
+
+	r := bufio.NewReader(resp.Body)
+	for _, seq := range []uint64{2, 4} {
+		msg := readSSE(t, r)
+		assert.Equal(t, strconv.FormatUint(seq, 10), msg.id)
+		assert.Equal(t, string(EventRFQOpened), msg.event)
+		var ev Event
+		require.NoError(t, json.Unmarshal([]byte(msg.data), &ev))
+		assert.Equal(t, seq, ev.Seq)
+		assert.Equal(t, common.BigToHash(new(big.Int).SetUint64(seq)), ev.RFQTxHash)
+	}
+}
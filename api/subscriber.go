@@ -0,0 +1,421 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+// Topics a client can subscribe to.
+const (
+	// TopicRFQs receives newly opened RFQs
+	TopicRFQs = "rfqs"
+	// TopicQuotes receives the quotes of the RFQ given by rfqTxHash
+	TopicQuotes = "quotes"
+	// TopicPair receives every event in the token pair given by baseToken
+	// and quoteToken
+	TopicPair = "pair"
+	// TopicResults receives closed RFQs, the results of matched auctions and
+	// settled RFQs
+	TopicResults = "results"
+)
+
+// Types of the messages exchanged with subscribers.
+const (
+	opSubscribe   = "subscribe"
+	opUnsubscribe = "unsubscribe"
+	// opResume reports on replaying the events after ?since
+	opResume = "resume"
+
+	messageAck   = "ack"
+	messageError = "error"
+	messageEvent = "event"
+)
+
+// sendBuffer is how many messages may be queued for a subscriber before it
+// is considered too slow and disconnected.
+const sendBuffer = 256
+
+var (
+	errUnknownOp      = errors.New("op must be subscribe or unsubscribe")
+	errUnknownTopic   = errors.New("topic must be rfqs, quotes, pair or results")
+	errTopicRFQ       = errors.New("the quotes topic needs an rfqTxHash")
+	errTopicPair      = errors.New("the pair topic needs a baseToken and a quoteToken")
+	errNoSubscription = errors.New("no such subscription")
+	errSlowClient     = errors.New("client is not reading its events")
+	errClientGone     = errors.New("client disconnected")
+	errInvalidSince   = errors.New("since must be an event sequence number")
+)
+
+// WSRequest is a message sent by a websocket client to subscribe to a topic
+// or cancel a subscription. The rfqTxHash and token filters narrow any topic.
+type WSRequest struct {
+	// ID is echoed in the acknowledgement
+	ID    string `json:"id,omitempty"`
+	Op    string `json:"op"`
+	Topic string `json:"topic,omitempty"`
+
+	RFQTxHash  *common.Hash    `json:"rfqTxHash,omitempty"`
+	BaseToken  *common.Address `json:"baseToken,omitempty"`
+	QuoteToken *common.Address `json:"quoteToken,omitempty"`
+
+	// Subscription is the subscription to cancel
+	Subscription string `json:"subscription,omitempty"`
+}
+
+// WSAck acknowledges a WSRequest, Type is "error" when it was rejected.
+type WSAck struct {
+	Type         string `json:"type"`
+	ID           string `json:"id,omitempty"`
+	Op           string `json:"op,omitempty"`
+	Subscription string `json:"subscription,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+// subscription is a subscription to a topic with its filters.
+type subscription struct {
+	topic      string
+	rfqTxHash  *common.Hash
+	baseToken  *common.Address
+	quoteToken *common.Address
+}
+
+func newSubscription(req *WSRequest) (*subscription, error) {
+	switch req.Topic {
+	case TopicRFQs, TopicResults:
+	case TopicQuotes:
+		if req.RFQTxHash == nil {
+			return nil, errTopicRFQ
+		}
+	case TopicPair:
+		if req.BaseToken == nil || req.QuoteToken == nil {
+			return nil, errTopicPair
+		}
+	default:
+		return nil, errUnknownTopic
+	}
+	return &subscription{
+		topic:      req.Topic,
+		rfqTxHash:  req.RFQTxHash,
+		baseToken:  req.BaseToken,
+		quoteToken: req.QuoteToken,
+	}, nil
+}
+
+func (sub *subscription) matches(ev *event) bool {
+	switch sub.topic {
+	case TopicRFQs:
+		if ev.Kind != EventRFQOpened {
+			return false
+		}
+	case TopicQuotes:
+		if ev.Kind != EventQuoteReceived {
+			return false
+		}
+	case TopicResults:
+		if ev.Kind != EventRFQClosed && ev.Kind != EventAuctionMatched && ev.Kind != EventRFQSettled {
+			return false
+		}
+	}
+	if sub.rfqTxHash != nil && *sub.rfqTxHash != ev.RFQTxHash {
+		return false
+	}
+	if sub.baseToken != nil && *sub.baseToken != ev.pair.base {
+		return false
+	}
+	if sub.quoteToken != nil && *sub.quoteToken != ev.pair.quote {
+		return false
+	}
+	return true
+}
+
+// outbound is a message queued for a subscriber, ev is nil for
+// acknowledgements and notices.
+type outbound struct {
+	ev   *event
+	data []byte
+}
+
+// subscriber is a client of the event feed, over a websocket or SSE, and its
+// subscriptions. Messages are queued on send and written by the client's own
+// goroutine so a slow client can't hold up the others.
+type subscriber struct {
+	// caller is the authenticated caller that connected, nil if anonymous
+	caller *Caller
+	send   chan outbound
+	done   chan struct{}
+	once   sync.Once
+	// onClose releases the client's connection
+	onClose func()
+	// filter narrows an event to what the caller may see before it is
+	// queued, nil events are dropped
+	filter func(*event) *event
+
+	mu            sync.Mutex
+	subscriptions map[string]*subscription
+	nextID        int
+}
+
+// newSubscriber returns a subscriber whose queue has room for backlog
+// replayed events on top of the usual buffer.
+func newSubscriber(caller *Caller, backlog int, onClose func()) *subscriber {
+	return &subscriber{
+		caller:        caller,
+		send:          make(chan outbound, sendBuffer+backlog),
+		done:          make(chan struct{}),
+		onClose:       onClose,
+		subscriptions: make(map[string]*subscription),
+	}
+}
+
+func (c *subscriber) close() {
+	c.once.Do(func() {
+		close(c.done)
+		if c.onClose != nil {
+			c.onClose()
+		}
+	})
+}
+
+// queue queues msg for the client, disconnecting it if its queue is full.
+func (c *subscriber) queue(msg outbound) error {
+	select {
+	case c.send <- msg:
+		return nil
+	case <-c.done:
+		return errClientGone
+	default:
+		c.close()
+		return errSlowClient
+	}
+}
+
+func (c *subscriber) queueEvent(ev *event) error {
+	if c.filter != nil {
+		if ev = c.filter(ev); ev == nil {
+			return nil
+		}
+	}
+	return c.queue(outbound{ev: ev, data: ev.msg})
+}
+
+func (c *subscriber) queueJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.queue(outbound{data: data})
+}
+
+// receives reports whether ev matches any of the client's subscriptions and
+// the client may see it.
+func (c *subscriber) receives(ev *event) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, sub := range c.subscriptions {
+		if sub.matches(ev) {
+			return ev.audience(c.caller)
+		}
+	}
+	return false
+}
+
+// matching returns the IDs of the client's subscriptions ev matches, none if
+// the client may not see it.
+func (c *subscriber) matching(ev *event) []string {
+	if !ev.audience(c.caller) {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var ids []string
+	for id, sub := range c.subscriptions {
+		if sub.matches(ev) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// handle applies a subscribe or unsubscribe request and returns its
+// acknowledgement.
+func (c *subscriber) handle(req *WSRequest) *WSAck {
+	ack := &WSAck{Type: messageAck, ID: req.ID, Op: req.Op}
+	reject := func(err error) *WSAck {
+		ack.Type = messageError
+		ack.Error = err.Error()
+		return ack
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch req.Op {
+	case opSubscribe:
+		sub, err := newSubscription(req)
+		if err != nil {
+			return reject(err)
+		}
+		c.nextID++
+		ack.Subscription = fmt.Sprintf("s%d", c.nextID)
+		c.subscriptions[ack.Subscription] = sub
+	case opUnsubscribe:
+		if _, ok := c.subscriptions[req.Subscription]; !ok {
+			return reject(errNoSubscription)
+		}
+		delete(c.subscriptions, req.Subscription)
+		ack.Subscription = req.Subscription
+	default:
+		return reject(errUnknownOp)
+	}
+	return ack
+}
+
+// eventHub holds the connected subscribers.
+type eventHub struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+	// closed is set when the server shuts down, subscribers connecting
+	// afterwards are closed straight away
+	closed bool
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscribers: make(map[*subscriber]struct{})}
+}
+
+func (h *eventHub) register(c *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		c.close()
+		return
+	}
+	h.subscribers[c] = struct{}{}
+}
+
+func (h *eventHub) unregister(c *subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subscribers, c)
+}
+
+// closeAll disconnects every subscriber and refuses new ones. Subscribers
+// are closed concurrently outside the lock, as closing a connection may wait
+// on the client, and closeAll gives up waiting for them when ctx is done.
+func (h *eventHub) closeAll(ctx context.Context) error {
+	h.mu.Lock()
+	h.closed = true
+	subscribers := make([]*subscriber, 0, len(h.subscribers))
+	for c := range h.subscribers {
+		subscribers = append(subscribers, c)
+		delete(h.subscribers, c)
+	}
+	h.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, c := range subscribers {
+		wg.Add(1)
+		go func(c *subscriber) {
+			defer wg.Done()
+			c.close()
+		}(c)
+	}
+	closed := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// publish sends a numbered event to the subscribers that subscribed to it
+// and are allowed to see it.
+func (h *eventHub) publish(ev *event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for c := range h.subscribers {
+		if c.receives(ev) {
+			c.queueEvent(ev)
+		}
+	}
+}
+
+// subscribe creates a subscriber with the subscriptions in reqs and attaches
+// it to the feed once the events after since have been queued for it. The
+// acknowledgements of reqs are queued first when ack is set. Events are
+// queued as filterEvent narrows them for caller.
+func (s *Server) subscribe(caller *Caller, reqs []*WSRequest, since *uint64, ack bool, onClose func()) *subscriber {
+	backlog := len(reqs)
+	if since != nil {
+		backlog += s.events.backlog(*since)
+	}
+	sub := newSubscriber(caller, backlog, onClose)
+	sub.filter = func(ev *event) *event { return s.filterEvent(caller, ev) }
+	for _, req := range reqs {
+		if res := sub.handle(req); ack {
+			sub.queueJSON(res)
+		}
+	}
+	s.events.attach(sub, since, s.hub)
+	return sub
+}
+
+// subscriptionQuery reads the subscriptions given in a query string: one per
+// topic parameter, each narrowed by the rfqTxHash, baseToken and quoteToken
+// parameters, and the sequence number of the last event the client received
+// from since.
+func subscriptionQuery(query url.Values) ([]*WSRequest, *uint64, error) {
+	var since *uint64
+	if value := query.Get("since"); value != "" {
+		seq, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, nil, errInvalidSince
+		}
+		since = &seq
+	}
+
+	var filter WSRequest
+	if value := query.Get("rfqTxHash"); value != "" {
+		hash := common.HexToHash(value)
+		filter.RFQTxHash = &hash
+	}
+	for param, addr := range map[string]**common.Address{"baseToken": &filter.BaseToken, "quoteToken": &filter.QuoteToken} {
+		if value := query.Get(param); value != "" {
+			if !common.IsHexAddress(value) {
+				return nil, nil, types.ErrInvalidAddress
+			}
+			token := common.HexToAddress(value)
+			*addr = &token
+		}
+	}
+
+	var reqs []*WSRequest
+	for _, topic := range query["topic"] {
+		req := filter
+		req.Op = opSubscribe
+		req.Topic = topic
+		if _, err := newSubscription(&req); err != nil {
+			return nil, nil, err
+		}
+		reqs = append(reqs, &req)
+	}
+	return reqs, since, nil
+}
@@ -0,0 +1,787 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/go-kit/log"
+	"github.com/labstack/echo/v4"
+)
+
+// Headers of webhook deliveries. The signature is WebhookSignature of the
+// timestamp and the body, keyed with the webhook's secret.
+const (
+	WebhookIDHeader        = "X-OCAX-Webhook-Id"
+	WebhookDeliveryHeader  = "X-OCAX-Delivery-Id"
+	WebhookEventHeader     = "X-OCAX-Event"
+	WebhookTimestampHeader = "X-OCAX-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-OCAX-Webhook-Signature"
+)
+
+const (
+	maxWebhooksPerOwner = 10
+	// webhookQueueSize bounds the deliveries waiting for a webhook, events
+	// go straight to the dead-letter queue when it is full
+	webhookQueueSize = 1000
+	// webhookLogSize is how many delivery attempts are kept per webhook
+	webhookLogSize = 100
+)
+
+var (
+	errWebhookURL        = errors.New("webhook url must be an absolute http or https url")
+	errWebhookEvent      = errors.New("events must be rfq.opened, quote.received, rfq.closed, auction.matched or rfq.settled")
+	errWebhookLimit      = fmt.Errorf("at most %d webhooks can be registered per participant", maxWebhooksPerOwner)
+	errWebhookNotFound   = errors.New("webhook not found")
+	errDeadLetterMissing = errors.New("dead letter not found")
+	errWebhookQueueFull  = errors.New("delivery queue is full")
+	errWebhookShutdown   = errors.New("server shut down before the delivery was made")
+)
+
+// webhookEvents are the event kinds webhooks can be registered for.
+var webhookEvents = []EventKind{EventRFQOpened, EventQuoteReceived, EventRFQClosed, EventAuctionMatched, EventRFQSettled}
+
+// WebhookPolicy configures how events are delivered to webhooks.
+type WebhookPolicy struct {
+	// MaxAttempts is how many times an event is posted before it is moved to
+	// the dead-letter queue
+	MaxAttempts int
+	// Backoff is the wait before the first retry, it doubles after every
+	// attempt up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Timeout bounds each POST
+	Timeout time.Duration
+}
+
+// DefaultWebhookPolicy is the policy used when the server is not configured
+// with one.
+func DefaultWebhookPolicy() *WebhookPolicy {
+	return &WebhookPolicy{MaxAttempts: 6, Backoff: time.Second, MaxBackoff: time.Minute, Timeout: 10 * time.Second}
+}
+
+// backoff returns the wait after the given failed attempt.
+func (p *WebhookPolicy) backoff(attempt int) time.Duration {
+	wait := p.Backoff
+	for i := 1; i < attempt && wait < p.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > p.MaxBackoff {
+		wait = p.MaxBackoff
+	}
+	return wait
+}
+
+// WebhookStore persists webhook registrations and the events that could not
+// be delivered to them.
+type WebhookStore interface {
+	WriteWebhook(id string, data []byte) error
+	DeleteWebhook(id string) error
+	GetWebhooks() ([][]byte, error)
+	WriteDeadLetter(webhookID, deliveryID string, data []byte) error
+	DeleteDeadLetter(webhookID, deliveryID string) error
+	GetDeadLetters(webhookID string) ([][]byte, error)
+}
+
+// Webhook is a URL a participant has events posted to.
+type Webhook struct {
+	ID     string         `json:"id"`
+	Owner  common.Address `json:"owner"`
+	URL    string         `json:"url"`
+	Events []EventKind    `json:"events"`
+	// Secret is the key deliveries are signed with, it is only returned
+	// when the webhook is registered
+	Secret    string `json:"secret,omitempty"`
+	CreatedAt int64  `json:"createdAt"`
+}
+
+// WebhookRequest registers a webhook for the given event kinds, every kind
+// when Events is empty.
+type WebhookRequest struct {
+	URL    string      `json:"url"`
+	Events []EventKind `json:"events"`
+}
+
+// WebhookAttempt is an entry of a webhook's delivery log.
+type WebhookAttempt struct {
+	DeliveryID string    `json:"deliveryId"`
+	Seq        uint64    `json:"seq"`
+	Kind       EventKind `json:"kind"`
+	Attempt    int       `json:"attempt"`
+	// Status is the HTTP status of the response, 0 when no response was
+	// received
+	Status    int    `json:"status"`
+	Error     string `json:"error,omitempty"`
+	Delivered bool   `json:"delivered"`
+	Time      int64  `json:"time"`
+}
+
+// WebhookDelivery is an event to be posted to a webhook. Deliveries that
+// fail every attempt are kept in the webhook's dead-letter queue.
+type WebhookDelivery struct {
+	// ID is the same for every attempt and redelivery of the event, so
+	// receivers can drop duplicates
+	ID        string          `json:"id"`
+	WebhookID string          `json:"webhookId"`
+	Seq       uint64          `json:"seq"`
+	Kind      EventKind       `json:"kind"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"lastError,omitempty"`
+	Event     json.RawMessage `json:"event"`
+}
+
+// WebhookSignature returns the signature of a delivery made at timestamp
+// with the given body: the hex encoded HMAC-SHA256, keyed with the webhook's
+// secret, of the timestamp, a dot and the body. Receivers should compare it
+// with hmac.Equal.
+func WebhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// webhookWorker posts the deliveries of one webhook in order.
+type webhookWorker struct {
+	hook  *Webhook
+	queue chan *WebhookDelivery
+	quit  chan struct{}
+
+	mu  sync.Mutex
+	log []WebhookAttempt
+}
+
+func (w *webhookWorker) wants(kind EventKind) bool {
+	if len(w.hook.Events) == 0 {
+		return true
+	}
+	for _, k := range w.hook.Events {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *webhookWorker) record(attempt WebhookAttempt) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.log = append(w.log, attempt)
+	if len(w.log) > webhookLogSize {
+		w.log = w.log[len(w.log)-webhookLogSize:]
+	}
+}
+
+// attempts returns the delivery log, most recent attempt first.
+func (w *webhookWorker) attempts() []WebhookAttempt {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	attempts := make([]WebhookAttempt, len(w.log))
+	for i, attempt := range w.log {
+		attempts[len(w.log)-1-i] = attempt
+	}
+	return attempts
+}
+
+// webhookDispatcher holds the registered webhooks and delivers events to
+// them, retrying failed deliveries with exponential backoff.
+type webhookDispatcher struct {
+	policy *WebhookPolicy
+	store  WebhookStore
+	client *http.Client
+	logger log.Logger
+
+	// ctx is cancelled when the dispatcher stops, aborting the posts in
+	// flight
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	workers map[string]*webhookWorker
+	stopped bool
+}
+
+func newWebhookDispatcher(policy *WebhookPolicy, store WebhookStore, logger log.Logger) *webhookDispatcher {
+	if store == nil {
+		store = newMemoryWebhookStore()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &webhookDispatcher{
+		policy: policy,
+		store:  store,
+		client: &http.Client{
+			Timeout: policy.Timeout,
+			// a webhook must not be able to send deliveries elsewhere
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		logger:  logger,
+		ctx:     ctx,
+		cancel:  cancel,
+		workers: make(map[string]*webhookWorker),
+	}
+}
+
+// load starts the workers of the stored webhooks.
+func (d *webhookDispatcher) load() error {
+	stored, err := d.store.GetWebhooks()
+	if err != nil {
+		return err
+	}
+	for _, data := range stored {
+		hook := new(Webhook)
+		if err := json.Unmarshal(data, hook); err != nil {
+			return fmt.Errorf("error decoding webhook: %w", err)
+		}
+		d.start(hook)
+	}
+	return nil
+}
+
+func (d *webhookDispatcher) start(hook *Webhook) {
+	w := &webhookWorker{
+		hook:  hook,
+		queue: make(chan *WebhookDelivery, webhookQueueSize),
+		quit:  make(chan struct{}),
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.workers[hook.ID] = w
+	// the events of a webhook registered while stopping are dead-lettered
+	// by enqueue
+	if !d.stopped {
+		d.wg.Add(1)
+		go d.run(w)
+	}
+}
+
+// register stores a new webhook and starts delivering events to it.
+func (d *webhookDispatcher) register(owner common.Address, req *WebhookRequest) (*Webhook, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errWebhookURL
+	}
+	for _, kind := range req.Events {
+		if !containsKind(webhookEvents, kind) {
+			return nil, errWebhookEvent
+		}
+	}
+	if len(d.list(owner)) >= maxWebhooksPerOwner {
+		return nil, errWebhookLimit
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return nil, err
+	}
+	hook := &Webhook{
+		ID:        id,
+		Owner:     owner,
+		URL:       u.String(),
+		Events:    req.Events,
+		Secret:    secret,
+		CreatedAt: int64(nowMs()),
+	}
+	data, err := json.Marshal(hook)
+	if err != nil {
+		return nil, err
+	}
+	if err := d.store.WriteWebhook(id, data); err != nil {
+		return nil, err
+	}
+	d.start(hook)
+	return hook, nil
+}
+
+func containsKind(kinds []EventKind, kind EventKind) bool {
+	for _, k := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// worker returns the worker of the owner's webhook with the given id.
+func (d *webhookDispatcher) worker(owner common.Address, id string) (*webhookWorker, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	w, ok := d.workers[id]
+	if !ok || w.hook.Owner != owner {
+		return nil, errWebhookNotFound
+	}
+	return w, nil
+}
+
+// list returns the owner's webhooks, without their secrets, oldest first.
+func (d *webhookDispatcher) list(owner common.Address) []*Webhook {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	hooks := []*Webhook{}
+	for _, w := range d.workers {
+		if w.hook.Owner == owner {
+			hook := *w.hook
+			hook.Secret = ""
+			hooks = append(hooks, &hook)
+		}
+	}
+	sort.Slice(hooks, func(i, j int) bool {
+		if hooks[i].CreatedAt != hooks[j].CreatedAt {
+			return hooks[i].CreatedAt < hooks[j].CreatedAt
+		}
+		return hooks[i].ID < hooks[j].ID
+	})
+	return hooks
+}
+
+// remove stops delivering to the owner's webhook and deletes it with its
+// dead letters.
+func (d *webhookDispatcher) remove(owner common.Address, id string) error {
+	w, err := d.worker(owner, id)
+	if err != nil {
+		return err
+	}
+	if err := d.store.DeleteWebhook(id); err != nil {
+		return err
+	}
+	d.mu.Lock()
+	delete(d.workers, id)
+	d.mu.Unlock()
+	close(w.quit)
+	return nil
+}
+
+// dispatch queues ev for every webhook registered for its kind, encoded by
+// render for the webhook's owner. render returns nil for webhooks that may
+// not receive ev.
+func (d *webhookDispatcher) dispatch(ev *event, render func(*Webhook) []byte) {
+	d.mu.Lock()
+	workers := make([]*webhookWorker, 0, len(d.workers))
+	for _, w := range d.workers {
+		if w.wants(ev.Kind) {
+			workers = append(workers, w)
+		}
+	}
+	d.mu.Unlock()
+
+	for _, w := range workers {
+		msg := render(w.hook)
+		if msg == nil {
+			continue
+		}
+		d.enqueue(w, &WebhookDelivery{
+			ID:        fmt.Sprintf("%s-%d", w.hook.ID, ev.Seq),
+			WebhookID: w.hook.ID,
+			Seq:       ev.Seq,
+			Kind:      ev.Kind,
+			Event:     msg,
+		})
+	}
+}
+
+func (d *webhookDispatcher) enqueue(w *webhookWorker, delivery *WebhookDelivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stopped {
+		delivery.LastError = errWebhookShutdown.Error()
+		d.deadLetter(delivery)
+		return
+	}
+	select {
+	case w.queue <- delivery:
+	default:
+		delivery.LastError = errWebhookQueueFull.Error()
+		d.deadLetter(delivery)
+	}
+}
+
+// run posts the webhook's deliveries until it is removed or the dispatcher
+// stops.
+func (d *webhookDispatcher) run(w *webhookWorker) {
+	defer d.wg.Done()
+	for {
+		select {
+		case <-w.quit:
+			return
+		case <-d.ctx.Done():
+			return
+		case delivery := <-w.queue:
+			if !d.deliver(w, delivery) {
+				return
+			}
+		}
+	}
+}
+
+// deliver posts a delivery until it is accepted or MaxAttempts is reached,
+// when it is moved to the dead-letter queue. It returns false if the webhook
+// was removed meanwhile or the dispatcher stopped, the delivery being
+// dead-lettered in the latter case so it can be redelivered after a restart.
+func (d *webhookDispatcher) deliver(w *webhookWorker, delivery *WebhookDelivery) bool {
+	for attempt := 1; ; attempt++ {
+		delivery.Attempts++
+		status, err := d.post(w.hook, delivery)
+		if err != nil && d.ctx.Err() != nil {
+			delivery.LastError = errWebhookShutdown.Error()
+			d.deadLetter(delivery)
+			return false
+		}
+		entry := WebhookAttempt{
+			DeliveryID: delivery.ID,
+			Seq:        delivery.Seq,
+			Kind:       delivery.Kind,
+			Attempt:    delivery.Attempts,
+			Status:     status,
+			Time:       int64(nowMs()),
+		}
+		if err == nil {
+			entry.Delivered = true
+			w.record(entry)
+			return true
+		}
+		entry.Error = err.Error()
+		w.record(entry)
+		delivery.LastError = err.Error()
+
+		if attempt >= d.policy.MaxAttempts {
+			d.deadLetter(delivery)
+			return true
+		}
+		select {
+		case <-w.quit:
+			return false
+		case <-d.ctx.Done():
+			d.deadLetter(delivery)
+			return false
+		case <-time.After(d.policy.backoff(attempt)):
+		}
+	}
+}
+
+// post sends a delivery and returns the status of the response, any status
+// but 2xx is an error.
+func (d *webhookDispatcher) post(hook *Webhook, delivery *WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Event))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(int64(nowMs()), 10)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(WebhookIDHeader, hook.ID)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID)
+	req.Header.Set(WebhookEventHeader, string(delivery.Kind))
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, WebhookSignature(hook.Secret, timestamp, delivery.Event))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, io.LimitReader(res.Body, 1<<16))
+	res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return res.StatusCode, fmt.Errorf("webhook responded with %s", res.Status)
+	}
+	return res.StatusCode, nil
+}
+
+// stop aborts the deliveries in flight and waits for the workers to return,
+// for as long as ctx allows, then moves the queued deliveries to the
+// dead-letter queue.
+func (d *webhookDispatcher) stop(ctx context.Context) error {
+	d.mu.Lock()
+	d.stopped = true
+	workers := make([]*webhookWorker, 0, len(d.workers))
+	for _, w := range d.workers {
+		workers = append(workers, w)
+	}
+	d.mu.Unlock()
+
+	d.cancel()
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	for _, w := range workers {
+		for drained := false; !drained; {
+			select {
+			case delivery := <-w.queue:
+				delivery.LastError = errWebhookShutdown.Error()
+				d.deadLetter(delivery)
+			default:
+				drained = true
+			}
+		}
+	}
+	return err
+}
+
+func (d *webhookDispatcher) deadLetter(delivery *WebhookDelivery) {
+	data, err := json.Marshal(delivery)
+	if err == nil {
+		err = d.store.WriteDeadLetter(delivery.WebhookID, delivery.ID, data)
+	}
+	if err != nil && d.logger != nil {
+		d.logger.Log("level", "error", "msg", "failed to store webhook dead letter", "delivery", delivery.ID, "err", err)
+	}
+}
+
+// deadLetters returns the events that could not be delivered to a webhook.
+func (d *webhookDispatcher) deadLetters(w *webhookWorker) ([]*WebhookDelivery, error) {
+	stored, err := d.store.GetDeadLetters(w.hook.ID)
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]*WebhookDelivery, 0, len(stored))
+	for _, data := range stored {
+		delivery := new(WebhookDelivery)
+		if err := json.Unmarshal(data, delivery); err != nil {
+			return nil, fmt.Errorf("error decoding dead letter: %w", err)
+		}
+		letters = append(letters, delivery)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i].Seq < letters[j].Seq })
+	return letters, nil
+}
+
+// redeliver moves a dead letter back to the webhook's queue.
+func (d *webhookDispatcher) redeliver(w *webhookWorker, deliveryID string) error {
+	letters, err := d.deadLetters(w)
+	if err != nil {
+		return err
+	}
+	for _, delivery := range letters {
+		if delivery.ID != deliveryID {
+			continue
+		}
+		if err := d.store.DeleteDeadLetter(w.hook.ID, deliveryID); err != nil {
+			return err
+		}
+		delivery.Attempts = 0
+		delivery.LastError = ""
+		d.enqueue(w, delivery)
+		return nil
+	}
+	return errDeadLetterMissing
+}
+
+// memoryWebhookStore keeps webhooks in memory when the server has no
+// WebhookStore.
+type memoryWebhookStore struct {
+	mu       sync.Mutex
+	webhooks map[string][]byte
+	letters  map[string]map[string][]byte
+}
+
+func newMemoryWebhookStore() *memoryWebhookStore {
+	return &memoryWebhookStore{
+		webhooks: make(map[string][]byte),
+		letters:  make(map[string]map[string][]byte),
+	}
+}
+
+func (m *memoryWebhookStore) WriteWebhook(id string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.webhooks[id] = data
+	return nil
+}
+
+func (m *memoryWebhookStore) DeleteWebhook(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.webhooks, id)
+	delete(m.letters, id)
+	return nil
+}
+
+func (m *memoryWebhookStore) GetWebhooks() ([][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	webhooks := make([][]byte, 0, len(m.webhooks))
+	for _, data := range m.webhooks {
+		webhooks = append(webhooks, data)
+	}
+	return webhooks, nil
+}
+
+func (m *memoryWebhookStore) WriteDeadLetter(webhookID, deliveryID string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.letters[webhookID] == nil {
+		m.letters[webhookID] = make(map[string][]byte)
+	}
+	m.letters[webhookID][deliveryID] = data
+	return nil
+}
+
+func (m *memoryWebhookStore) DeleteDeadLetter(webhookID, deliveryID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.letters[webhookID], deliveryID)
+	return nil
+}
+
+func (m *memoryWebhookStore) GetDeadLetters(webhookID string) ([][]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	letters := make([][]byte, 0, len(m.letters[webhookID]))
+	for _, data := range m.letters[webhookID] {
+		letters = append(letters, data)
+	}
+	return letters, nil
+}
+
+// dispatchWebhooks queues ev for the webhooks whose owner may receive it,
+// filtered as filterEvent narrows it for the owner. Quote events only go to
+// the quoter that sent them.
+func (s *Server) dispatchWebhooks(ev *event) {
+	s.webhooks.dispatch(ev, func(hook *Webhook) []byte {
+		if ev.Kind == EventQuoteReceived {
+			if ev.From == nil || *ev.From != hook.Owner {
+				return nil
+			}
+			return ev.msg
+		}
+		caller := &Caller{Address: hook.Owner}
+		if participant, err := s.bc.GetParticipant(hook.Owner); err == nil {
+			caller.Participant = participant
+		}
+		if !ev.audience(caller) {
+			return nil
+		}
+		if filtered := s.filterEvent(caller, ev); filtered != nil {
+			return filtered.msg
+		}
+		return nil
+	})
+}
+
+// webhookOwner returns the signed caller managing its webhooks.
+func webhookOwner(c echo.Context) (common.Address, error) {
+	caller := callerFrom(c)
+	if caller == nil {
+		return common.Address{}, errAuthRequired
+	}
+	return caller.Address, nil
+}
+
+func (s *Server) handlePostWebhook(c echo.Context) error {
+	owner, err := webhookOwner(c)
+	if err != nil {
+		return writeError(c, http.StatusUnauthorized, err)
+	}
+	req := new(WebhookRequest)
+	if err := json.NewDecoder(c.Request().Body).Decode(req); err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	hook, err := s.webhooks.register(owner, req)
+	if err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusCreated, hook)
+}
+
+func (s *Server) handleGetWebhooks(c echo.Context) error {
+	owner, err := webhookOwner(c)
+	if err != nil {
+		return writeError(c, http.StatusUnauthorized, err)
+	}
+	return c.JSON(http.StatusOK, s.webhooks.list(owner))
+}
+
+func (s *Server) handleDeleteWebhook(c echo.Context) error {
+	owner, err := webhookOwner(c)
+	if err != nil {
+		return writeError(c, http.StatusUnauthorized, err)
+	}
+	if err := s.webhooks.remove(owner, c.Param("id")); err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) handleGetWebhookDeliveries(c echo.Context) error {
+	owner, err := webhookOwner(c)
+	if err != nil {
+		return writeError(c, http.StatusUnauthorized, err)
+	}
+	w, err := s.webhooks.worker(owner, c.Param("id"))
+	if err != nil {
+		return writeError(c, http.StatusNotFound, err)
+	}
+	return c.JSON(http.StatusOK, w.attempts())
+}
+
+func (s *Server) handleGetDeadLetters(c echo.Context) error {
+	owner, err := webhookOwner(c)
+	if err != nil {
+		return writeError(c, http.StatusUnauthorized, err)
+	}
+	w, err := s.webhooks.worker(owner, c.Param("id"))
+	if err != nil {
+		return writeError(c, http.StatusNotFound, err)
+	}
+	letters, err := s.webhooks.deadLetters(w)
+	if err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.JSON(http.StatusOK, letters)
+}
+
+// handleRedeliverDeadLetter queues a dead letter for delivery again, it
+// returns to the dead-letter queue if every attempt fails again.
+func (s *Server) handleRedeliverDeadLetter(c echo.Context) error {
+	owner, err := webhookOwner(c)
+	if err != nil {
+		return writeError(c, http.StatusUnauthorized, err)
+	}
+	w, err := s.webhooks.worker(owner, c.Param("id"))
+	if err != nil {
+		return writeError(c, http.StatusNotFound, err)
+	}
+	if err := s.webhooks.redeliver(w, c.Param("deliveryId")); err != nil {
+		return writeError(c, http.StatusInternalServerError, err)
+	}
+	return c.NoContent(http.StatusAccepted)
+}
@@ -0,0 +1,261 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/go-kit/log"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookReceiver is a local endpoint webhooks are registered with, it
+// answers with status and passes on the requests it receives.
+type webhookReceiver struct {
+	*httptest.Server
+	status   atomic.Int32
+	requests chan *receivedWebhook
+}
+
+type receivedWebhook struct {
+	header http.Header
+	body   []byte
+}
+
+func newWebhookReceiver(status int) *webhookReceiver {
+	r := &webhookReceiver{requests: make(chan *receivedWebhook, 16)}
+	r.status.Store(int32(status))
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		status := int(r.status.Load())
+		body, _ := io.ReadAll(req.Body)
+		r.requests <- &receivedWebhook{header: req.Header.Clone(), body: body}
+		w.WriteHeader(status)
+	}))
+	return r
+}
+
+func (r *webhookReceiver) next(t *testing.T) *receivedWebhook {
+	t.Helper()
+	select {
+	case req := <-r.requests:
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatal("no webhook delivery received")
+		return nil
+	}
+}
+
+func newWebhookServer(t *testing.T, policy *WebhookPolicy) (*Server, *echo.Echo) {
+	t.Helper()
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, assert.AnError)
+	mockChain.On("GetOpenRFQByHash", mock.Anything).Return(nil, assert.AnError)
+	mockChain.On("GetRFQRequestByHash", mock.Anything).Return(nil, assert.AnError)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger(), WebhookPolicy: policy}, mockChain, nil)
+
+	e := echo.New()
+	e.Use(s.authenticate)
+	e.POST("/webhooks", s.handlePostWebhook)
+	e.GET("/webhooks", s.handleGetWebhooks)
+	e.DELETE("/webhooks/:id", s.handleDeleteWebhook)
+	e.GET("/webhooks/:id/deliveries", s.handleGetWebhookDeliveries)
+	e.GET("/webhooks/:id/deadLetters", s.handleGetDeadLetters)
+	e.POST("/webhooks/:id/deadLetters/:deliveryId", s.handleRedeliverDeadLetter)
+	return s, e
+}
+
+// serveSigned serves a request signed with key and decodes the response
+// into v. Requests are signed a millisecond apart as the same request signed
+// at the same time is taken for a replay.
+func serveSigned(t *testing.T, e *echo.Echo, key cryptoocax.PrivateKey, method, path string, body interface{}, v interface{}) int {
+	t.Helper()
+	var data []byte
+	if body != nil {
+		var err error
+		data, err = json.Marshal(body)
+		require.NoError(t, err)
+	}
+	time.Sleep(time.Millisecond)
+	req := httptest.NewRequest(method, path, bytes.NewReader(data))
+	require.NoError(t, SignRequest(req, data, key))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	if v != nil {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), v), rec.Body.String())
+	}
+	return rec.Code
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	s, e := newWebhookServer(t, &WebhookPolicy{MaxAttempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, Timeout: time.Second})
+	receiver := newWebhookReceiver(http.StatusInternalServerError)
+	defer receiver.Close()
+	key := cryptoocax.GeneratePrivateKey()
+
+	// webhooks can only be managed by signed callers
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/webhooks", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	var res APIError
+	assert.Equal(t, http.StatusBadRequest, serveSigned(t, e, key, http.MethodPost, "/webhooks", WebhookRequest{URL: "ftp://example.com"}, &res))
+	assert.Equal(t, errWebhookURL.Error(), res.Error)
+	assert.Equal(t, http.StatusBadRequest, serveSigned(t, e, key, http.MethodPost, "/webhooks", WebhookRequest{URL: receiver.URL, Events: []EventKind{"rfq.unknown"}}, &res))
+	assert.Equal(t, errWebhookEvent.Error(), res.Error)
+
+	var hook Webhook
+	require.Equal(t, http.StatusCreated, serveSigned(t, e, key, http.MethodPost, "/webhooks", WebhookRequest{URL: receiver.URL, Events: []EventKind{EventRFQOpened}}, &hook))
+	require.NotEmpty(t, hook.Secret)
+	assert.Equal(t, key.PublicKey().Address(), hook.Owner)
+
+	// the secret is not listed
+	var hooks []*Webhook
+	require.Equal(t, http.StatusOK, serveSigned(t, e, key, http.MethodGet, "/webhooks", nil, &hooks))
+	require.Len(t, hooks, 1)
+	assert.Equal(t, hook.ID, hooks[0].ID)
+	assert.Empty(t, hooks[0].Secret)
+
+	// a failed delivery is retried with the same delivery ID
+	rfqTxHash := common.HexToHash("0x01")
+	s.BroadcastTx(newOpenRFQTx(t, rfqTxHash, common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")), types.OpenRFQTxType)
+	first := receiver.next(t)
+	receiver.status.Store(http.StatusOK)
+	second := receiver.next(t)
+	assert.Equal(t, first.header.Get(WebhookDeliveryHeader), second.header.Get(WebhookDeliveryHeader))
+
+	assert.Equal(t, hook.ID, second.header.Get(WebhookIDHeader))
+	assert.Equal(t, string(EventRFQOpened), second.header.Get(WebhookEventHeader))
+	timestamp := second.header.Get(WebhookTimestampHeader)
+	assert.Equal(t, WebhookSignature(hook.Secret, timestamp, second.body), second.header.Get(WebhookSignatureHeader))
+	var ev Event
+	require.NoError(t, json.Unmarshal(second.body, &ev))
+	assert.Equal(t, EventRFQOpened, ev.Kind)
+	assert.Equal(t, rfqTxHash, ev.RFQTxHash)
+
+	// the log lists the attempts, most recent first
+	var attempts []WebhookAttempt
+	require.Eventually(t, func() bool {
+		serveSigned(t, e, key, http.MethodGet, "/webhooks/"+hook.ID+"/deliveries", nil, &attempts)
+		return len(attempts) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.True(t, attempts[0].Delivered)
+	assert.Equal(t, 2, attempts[0].Attempt)
+	assert.False(t, attempts[1].Delivered)
+	assert.Equal(t, http.StatusInternalServerError, attempts[1].Status)
+
+	// other participants can't see or delete the webhook
+	other := cryptoocax.GeneratePrivateKey()
+	assert.Equal(t, http.StatusNotFound, serveSigned(t, e, other, http.MethodGet, "/webhooks/"+hook.ID+"/deliveries", nil, &res))
+	assert.Equal(t, CodeWebhookNotFound, res.Code)
+	assert.Equal(t, http.StatusNotFound, serveSigned(t, e, other, http.MethodDelete, "/webhooks/"+hook.ID, nil, nil))
+	assert.Equal(t, http.StatusNoContent, serveSigned(t, e, key, http.MethodDelete, "/webhooks/"+hook.ID, nil, nil))
+	require.Equal(t, http.StatusOK, serveSigned(t, e, key, http.MethodGet, "/webhooks", nil, &hooks))
+	assert.Empty(t, hooks)
+}
+
+func TestWebhookDeadLetters(t *testing.T) {
+	s, e := newWebhookServer(t, &WebhookPolicy{MaxAttempts: 2, Backoff: time.Millisecond, MaxBackoff: time.Millisecond, Timeout: time.Second})
+	receiver := newWebhookReceiver(http.StatusServiceUnavailable)
+	defer receiver.Close()
+	key := cryptoocax.GeneratePrivateKey()
+
+	var hook Webhook
+	require.Equal(t, http.StatusCreated, serveSigned(t, e, key, http.MethodPost, "/webhooks", WebhookRequest{URL: receiver.URL}, &hook))
+
+	s.BroadcastTx(newOpenRFQTx(t, common.HexToHash("0x01"), common.HexToAddress("0x01")), types.OpenRFQTxType)
+	receiver.next(t)
+	receiver.next(t)
+
+	// the event is kept once every attempt failed
+	var letters []*WebhookDelivery
+	require.Eventually(t, func() bool {
+		serveSigned(t, e, key, http.MethodGet, "/webhooks/"+hook.ID+"/deadLetters", nil, &letters)
+		return len(letters) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, 2, letters[0].Attempts)
+	assert.Equal(t, EventRFQOpened, letters[0].Kind)
+	assert.Contains(t, letters[0].LastError, "503")
+
+	var res APIError
+	assert.Equal(t, http.StatusNotFound, serveSigned(t, e, key, http.MethodPost, "/webhooks/"+hook.ID+"/deadLetters/unknown", nil, &res))
+	assert.Equal(t, CodeDeadLetterNotFound, res.Code)
+
+	// redelivered dead letters leave the queue
+	receiver.status.Store(http.StatusOK)
+	require.Equal(t, http.StatusAccepted, serveSigned(t, e, key, http.MethodPost, "/webhooks/"+hook.ID+"/deadLetters/"+letters[0].ID, nil, nil))
+	redelivered := receiver.next(t)
+	assert.Equal(t, letters[0].ID, redelivered.header.Get(WebhookDeliveryHeader))
+	assert.JSONEq(t, string(letters[0].Event), string(redelivered.body))
+	require.Equal(t, http.StatusOK, serveSigned(t, e, key, http.MethodGet, "/webhooks/"+hook.ID+"/deadLetters", nil, &letters))
+	assert.Empty(t, letters)
+}
+
+func TestWebhookQuotesGoToTheirQuoter(t *testing.T) {
+	s, e := newWebhookServer(t, nil)
+	quoter := cryptoocax.GeneratePrivateKey()
+	other := cryptoocax.GeneratePrivateKey()
+	quoterReceiver := newWebhookReceiver(http.StatusOK)
+	defer quoterReceiver.Close()
+	otherReceiver := newWebhookReceiver(http.StatusOK)
+	defer otherReceiver.Close()
+
+	events := []EventKind{EventQuoteReceived}
+	require.Equal(t, http.StatusCreated, serveSigned(t, e, quoter, http.MethodPost, "/webhooks", WebhookRequest{URL: quoterReceiver.URL, Events: events}, nil))
+	require.Equal(t, http.StatusCreated, serveSigned(t, e, other, http.MethodPost, "/webhooks", WebhookRequest{URL: otherReceiver.URL, Events: events}, nil))
+
+	ev, err := s.quoteEvent(quoter.PublicKey().Address(), &types.QuoteData{RFQTxHash: common.HexToHash("0x01")})
+	require.NoError(t, err)
+	s.publish(ev)
+
+	delivery := quoterReceiver.next(t)
+	assert.Equal(t, string(EventQuoteReceived), delivery.header.Get(WebhookEventHeader))
+	select {
+	case <-otherReceiver.requests:
+		t.Fatal("quote delivered to another participant's webhook")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestWebhookResultsFilterQuotes(t *testing.T) {
+	s, e := newWebhookServer(t, nil)
+	quoters := []cryptoocax.PrivateKey{cryptoocax.GeneratePrivateKey(), cryptoocax.GeneratePrivateKey()}
+	receivers := make([]*webhookReceiver, len(quoters))
+	for i, quoter := range quoters {
+		receivers[i] = newWebhookReceiver(http.StatusOK)
+		defer receivers[i].Close()
+		require.Equal(t, http.StatusCreated, serveSigned(t, e, quoter, http.MethodPost, "/webhooks", WebhookRequest{URL: receivers[i].URL, Events: []EventKind{EventRFQClosed}}, nil))
+	}
+
+	rfqTxHash := common.HexToHash("0x01")
+	tx := newOpenRFQTx(t, rfqTxHash, common.HexToAddress("0x01"))
+	data := tx.EmbeddedData().(*types.RFQData)
+	data.Status = types.RFQStatusClosed
+	for _, quoter := range quoters {
+		data.Quotes = append(data.Quotes, types.NewQuote(quoter.PublicKey().Address(), &types.QuoteData{RFQTxHash: rfqTxHash, BidPrice: big.NewInt(1), AskPrice: big.NewInt(2)}))
+	}
+	s.BroadcastTx(tx, types.OpenRFQTxType)
+
+	// each quoter is only sent their own quote
+	for i, quoter := range quoters {
+		var ev Event
+		var rfq types.RFQData
+		require.NoError(t, json.Unmarshal(receivers[i].next(t).body, &ev))
+		assert.Equal(t, EventRFQClosed, ev.Kind)
+		require.NoError(t, json.Unmarshal(ev.Data, &rfq))
+		require.Len(t, rfq.Quotes, 1)
+		assert.Equal(t, quoter.PublicKey().Address(), rfq.Quotes[0].From)
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	wsWriteWait  = 10 * time.Second
+	wsPingPeriod = 30 * time.Second
+)
+
+// Secure this for production in terms of allowed origins
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// closeWebsocket returns the onClose of a websocket subscriber, telling the
+// client the server is going away when it is shutting down. The close frame
+// isn't waited on past the shutdown's deadline.
+func (s *Server) closeWebsocket(ws *websocket.Conn) func() {
+	return func() {
+		if s.shuttingDown() {
+			deadline := time.Now().Add(wsWriteWait)
+			if !s.closeDeadline.IsZero() && s.closeDeadline.Before(deadline) {
+				deadline = s.closeDeadline
+			}
+			msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+			ws.WriteControl(websocket.CloseMessage, msg, deadline)
+		}
+		ws.Close()
+	}
+}
+
+// wsWriteLoop writes the messages queued for sub to the connection and pings
+// idle connections so proxies don't drop them.
+func wsWriteLoop(conn *websocket.Conn, sub *subscriber) {
+	ticker := time.NewTicker(wsPingPeriod)
+	defer ticker.Stop()
+	defer sub.close()
+
+	for {
+		select {
+		case msg := <-sub.send:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.TextMessage, msg.data); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-sub.done:
+			return
+		}
+	}
+}
+
+// handleWsConnections serves the websocket feed. Clients subscribe to topics
+// with WSRequest messages and receive the matching events as Event messages.
+// Subscriptions can also be given in the query string, together with since
+// to first replay the events the client missed while disconnected.
+func (s *Server) handleWsConnections(c echo.Context) error {
+	reqs, since, err := subscriptionQuery(c.QueryParams())
+	if err != nil {
+		return writeError(c, http.StatusBadRequest, err)
+	}
+	ws, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+	if err != nil {
+		return err
+	}
+	sub := s.subscribe(callerFrom(c), reqs, since, true, s.closeWebsocket(ws))
+	defer s.hub.unregister(sub)
+	defer sub.close()
+
+	go wsWriteLoop(ws, sub)
+
+	for {
+		var req WSRequest
+		if err := ws.ReadJSON(&req); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				if sub.queueJSON(&WSAck{Type: messageError, Error: err.Error()}) != nil {
+					return nil
+				}
+				continue
+			}
+			return nil
+		}
+		if err := sub.queueJSON(sub.handle(&req)); err != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package api
+
+import (
+	"bufio"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/mocks/chainmocks"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/go-kit/log"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newOpenRFQTx(t *testing.T, rfqTxHash common.Hash, base common.Address) *types.Transaction {
+	t.Helper()
+	data := &types.RFQData{
+		RFQTxHash: rfqTxHash,
+		RFQRequest: &types.SignableData{
+			RequestorId:     "1",
+			BaseTokenAmount: big.NewInt(1000),
+			BaseToken:       &types.BaseToken{Address: base, Symbol: "MKR", Decimals: 18},
+			QuoteToken:      &types.QuoteToken{Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), Symbol: "USDC", Decimals: 6},
+			RFQDurationMs:   60000,
+		},
+		Status: types.RFQStatusOpen,
+	}
+	return types.NewTx(types.NewOpenRFQ(common.Address{}, data))
+}
+
+func TestWebsocketSubscriptions(t *testing.T) {
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, assert.AnError)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, mockChain, nil)
+
+	e := echo.New()
+	e.Use(s.authenticate)
+	e.GET("/ws", s.handleWsConnections)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	mkr := common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+
+	// invalid requests are rejected with an error message
+	require.NoError(t, conn.WriteJSON(WSRequest{ID: "1", Op: opSubscribe, Topic: TopicQuotes}))
+	var ack WSAck
+	require.NoError(t, conn.ReadJSON(&ack))
+	assert.Equal(t, messageError, ack.Type)
+	assert.Equal(t, "1", ack.ID)
+	assert.Equal(t, errTopicRFQ.Error(), ack.Error)
+
+	require.NoError(t, conn.WriteJSON(WSRequest{ID: "2", Op: opSubscribe, Topic: TopicRFQs, BaseToken: &mkr}))
+	require.NoError(t, conn.ReadJSON(&ack))
+	require.Equal(t, messageAck, ack.Type)
+	assert.Equal(t, "2", ack.ID)
+	subID := ack.Subscription
+	require.NotEmpty(t, subID)
+
+	// only the RFQ in the subscribed pair is delivered
+	wethRFQ := common.HexToHash("0x01")
+	mkrRFQ := common.HexToHash("0x02")
+	s.BroadcastTx(newOpenRFQTx(t, wethRFQ, weth), types.OpenRFQTxType)
+	s.BroadcastTx(newOpenRFQTx(t, mkrRFQ, mkr), types.OpenRFQTxType)
+
+	var ev Event
+	require.NoError(t, conn.ReadJSON(&ev))
+	assert.Equal(t, messageEvent, ev.Type)
+	assert.Equal(t, EventRFQOpened, ev.Kind)
+	assert.Equal(t, mkrRFQ, ev.RFQTxHash)
+
+	require.NoError(t, conn.WriteJSON(WSRequest{ID: "3", Op: opUnsubscribe, Subscription: subID}))
+	require.NoError(t, conn.ReadJSON(&ack))
+	assert.Equal(t, messageAck, ack.Type)
+	assert.Equal(t, subID, ack.Subscription)
+
+	// nothing is delivered once unsubscribed, the next message is the ack
+	s.BroadcastTx(newOpenRFQTx(t, mkrRFQ, mkr), types.OpenRFQTxType)
+	require.NoError(t, conn.WriteJSON(WSRequest{ID: "4", Op: opUnsubscribe, Subscription: subID}))
+	require.NoError(t, conn.ReadJSON(&ack))
+	assert.Equal(t, "4", ack.ID)
+	assert.Equal(t, errNoSubscription.Error(), ack.Error)
+}
+
+func TestSubscriptionMatches(t *testing.T) {
+	rfqTxHash := common.HexToHash("0x01")
+	base := common.HexToAddress("0x01")
+	quote := common.HexToAddress("0x02")
+	ev := func(kind EventKind) *event {
+		return &event{Event: Event{Kind: kind, RFQTxHash: rfqTxHash}, pair: pair{base, quote}}
+	}
+
+	tests := []struct {
+		req  WSRequest
+		kind EventKind
+		want bool
+	}{
+		{WSRequest{Topic: TopicRFQs}, EventRFQOpened, true},
+		{WSRequest{Topic: TopicRFQs}, EventQuoteReceived, false},
+		{WSRequest{Topic: TopicQuotes, RFQTxHash: &rfqTxHash}, EventQuoteReceived, true},
+		{WSRequest{Topic: TopicQuotes, RFQTxHash: &common.Hash{0x02}}, EventQuoteReceived, false},
+		{WSRequest{Topic: TopicPair, BaseToken: &base, QuoteToken: &quote}, EventAuctionMatched, true},
+		{WSRequest{Topic: TopicPair, BaseToken: &quote, QuoteToken: &base}, EventRFQOpened, false},
+		{WSRequest{Topic: TopicResults}, EventRFQClosed, true},
+		{WSRequest{Topic: TopicResults}, EventAuctionMatched, true},
+		{WSRequest{Topic: TopicResults}, EventRFQOpened, false},
+	}
+	for _, tt := range tests {
+		sub, err := newSubscription(&tt.req)
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, sub.matches(ev(tt.kind)), "%s %s", tt.req.Topic, tt.kind)
+	}
+}
+
+// memEventStore is an in-memory EventStore.
+type memEventStore struct {
+	events map[uint64][]byte
+}
+
+func (m *memEventStore) WriteEvent(seq uint64, data []byte) error {
+	m.events[seq] = data
+	return nil
+}
+
+func (m *memEventStore) DeleteEvent(seq uint64) error {
+	delete(m.events, seq)
+	return nil
+}
+
+func (m *memEventStore) GetEvents() ([][]byte, error) {
+	var seqs []uint64
+	for seq := range m.events {
+		seqs = append(seqs, seq)
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	events := make([][]byte, len(seqs))
+	for i, seq := range seqs {
+		events[i] = m.events[seq]
+	}
+	return events, nil
+}
+
+func TestWebsocketResume(t *testing.T) {
+	mkr := common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	store := &memEventStore{events: make(map[uint64][]byte)}
+	newServer := func() (*Server, string) {
+		mockChain := &chainmocks.ChainInterface{}
+		s := NewServer(ServerConfig{Logger: log.NewNopLogger(), EventStore: store, EventLogSize: 3}, mockChain, nil)
+		e := echo.New()
+		e.GET("/ws", s.handleWsConnections)
+		srv := httptest.NewServer(e)
+		t.Cleanup(srv.Close)
+		return s, "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws"
+	}
+
+	s, url := newServer()
+	for i := 1; i <= 4; i++ {
+		s.BroadcastTx(newOpenRFQTx(t, common.BigToHash(big.NewInt(int64(i))), mkr), types.OpenRFQTxType)
+	}
+	// only the last 3 events are kept
+	assert.Len(t, store.events, 3)
+
+	// a restarted server carries on from the stored log
+	s, url = newServer()
+	s.BroadcastTx(newOpenRFQTx(t, common.BigToHash(big.NewInt(5)), mkr), types.OpenRFQTxType)
+	assert.Len(t, store.events, 3)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?since=x", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.Dial(url+"?topic=rfqs&since=3", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	var ack WSAck
+	require.NoError(t, conn.ReadJSON(&ack))
+	require.Equal(t, messageAck, ack.Type)
+	assert.Equal(t, opSubscribe, ack.Op)
+
+	// the missed events are replayed before live events
+	s.BroadcastTx(newOpenRFQTx(t, common.BigToHash(big.NewInt(6)), mkr), types.OpenRFQTxType)
+	for seq := uint64(4); seq <= 6; seq++ {
+		var ev Event
+		require.NoError(t, conn.ReadJSON(&ev))
+		assert.Equal(t, seq, ev.Seq)
+		assert.Equal(t, common.BigToHash(new(big.Int).SetUint64(seq)), ev.RFQTxHash)
+	}
+
+	// resuming from before the oldest kept event reports the gap
+	gapped, _, err := websocket.DefaultDialer.Dial(url+"?topic=rfqs&since=1", nil)
+	require.NoError(t, err)
+	defer gapped.Close()
+	gapped.SetReadDeadline(time.Now().Add(5 * time.Second))
+	require.NoError(t, gapped.ReadJSON(&ack))
+	require.Equal(t, messageAck, ack.Type)
+	require.NoError(t, gapped.ReadJSON(&ack))
+	assert.Equal(t, messageError, ack.Type)
+	assert.Equal(t, opResume, ack.Op)
+	var ev Event
+	require.NoError(t, gapped.ReadJSON(&ev))
+	assert.Equal(t, uint64(4), ev.Seq)
+}
+
+// dialSigned opens a websocket to url authenticated as key. Connections are
+// signed a millisecond apart as serveSigned's requests are.
+func dialSigned(t *testing.T, url string, key cryptoocax.PrivateKey) *websocket.Conn {
+	t.Helper()
+	time.Sleep(time.Millisecond)
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	require.NoError(t, SignRequest(req, nil, key))
+	conn, _, err := websocket.DefaultDialer.Dial(url, req.Header)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	return conn
+}
+
+func TestEventsFilterQuotes(t *testing.T) {
+	requestor, quoterA, quoterB := cryptoocax.GeneratePrivateKey(), cryptoocax.GeneratePrivateKey(), cryptoocax.GeneratePrivateKey()
+	rfqTxHash := common.HexToHash("0x01")
+	mockChain := &chainmocks.ChainInterface{}
+	mockChain.On("GetParticipant", mock.Anything).Return(nil, assert.AnError)
+	mockChain.On("GetRFQRequestByHash", rfqTxHash).Return(&types.RFQRequest{From: requestor.PublicKey().Address()}, nil)
+	mockChain.On("GetClosedRFQByHash", rfqTxHash).Return(nil, assert.AnError)
+	s := NewServer(ServerConfig{Logger: log.NewNopLogger()}, mockChain, nil)
+
+	e := echo.New()
+	e.Use(s.authenticate)
+	e.GET("/ws", s.handleWsConnections)
+	e.GET("/events", s.handleGetEvents)
+	srv := httptest.NewServer(e)
+	defer srv.Close()
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws?topic=results"
+
+	conns := map[common.Address]*websocket.Conn{}
+	for _, key := range []cryptoocax.PrivateKey{requestor, quoterA} {
+		conn := dialSigned(t, wsURL, key)
+		var ack WSAck
+		require.NoError(t, conn.ReadJSON(&ack))
+		require.Equal(t, messageAck, ack.Type)
+		conns[key.PublicKey().Address()] = conn
+	}
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/events?topic=results", nil)
+	require.NoError(t, err)
+	require.NoError(t, SignRequest(req, nil, quoterA))
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Eventually(t, func() bool {
+		s.hub.mu.RLock()
+		defer s.hub.mu.RUnlock()
+		return len(s.hub.subscribers) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+
+	tx := newOpenRFQTx(t, rfqTxHash, common.HexToAddress("0x01"))
+	data := tx.EmbeddedData().(*types.RFQData)
+	data.Status = types.RFQStatusClosed
+	for _, key := range []cryptoocax.PrivateKey{quoterA, quoterB} {
+		data.Quotes = append(data.Quotes, types.NewQuote(key.PublicKey().Address(), &types.QuoteData{RFQTxHash: rfqTxHash, BidPrice: big.NewInt(1), AskPrice: big.NewInt(2)}))
+	}
+	s.BroadcastTx(tx, types.OpenRFQTxType)
+	s.BroadcastMatchResult(&types.MatchResult{
+		RFQTxHash: rfqTxHash,
+		BestBid:   &types.MatchedQuote{Quoter: quoterB.PublicKey().Address(), Price: big.NewInt(1)},
+		BestAsk:   &types.MatchedQuote{Quoter: quoterA.PublicKey().Address(), Price: big.NewInt(2)},
+	})
+
+	// the requestor sees every quote, a quoter only their own, whether on a
+	// websocket or an event stream
+	check := func(quoters []common.Address, closed, matched []byte) {
+		t.Helper()
+		var ev Event
+		var rfq types.RFQData
+		require.NoError(t, json.Unmarshal(closed, &ev))
+		require.Equal(t, EventRFQClosed, ev.Kind)
+		require.NoError(t, json.Unmarshal(ev.Data, &rfq))
+		var got []common.Address
+		for _, quote := range rfq.Quotes {
+			got = append(got, quote.From)
+		}
+		assert.Equal(t, quoters, got)
+
+		var result types.MatchResult
+		require.NoError(t, json.Unmarshal(matched, &ev))
+		require.Equal(t, EventAuctionMatched, ev.Kind)
+		require.NoError(t, json.Unmarshal(ev.Data, &result))
+		assert.Equal(t, len(quoters) == 2, result.BestBid != nil)
+		assert.NotNil(t, result.BestAsk)
+	}
+	read := func(conn *websocket.Conn) []byte {
+		_, msg, err := conn.ReadMessage()
+		require.NoError(t, err)
+		return msg
+	}
+	all := []common.Address{quoterA.PublicKey().Address(), quoterB.PublicKey().Address()}
+	conn := conns[requestor.PublicKey().Address()]
+	check(all, read(conn), read(conn))
+	conn = conns[quoterA.PublicKey().Address()]
+	check(all[:1], read(conn), read(conn))
+	r := bufio.NewReader(resp.Body)
+	check(all[:1], []byte(readSSE(t, r).data), []byte(readSSE(t, r).data))
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// dealer generates the threshold key quotes are sealed to and writes one key
+// share file per validator, share-<index>.json, to -out. It is a trusted
+// setup: run it offline, hand each validator its own file over a secure
+// channel and delete the files afterwards, as whoever holds threshold of them
+// can read sealed quotes.
+func main() {
+	t := flag.Int("threshold", 1, "number of validators needed to decrypt a quote")
+	n := flag.Int("validators", 1, "number of validators holding a key share")
+	out := flag.String("out", ".keyshares", "directory the key share files are written to")
+	flag.Parse()
+
+	pub, shares, err := threshold.Deal(*t, *n)
+	if err != nil {
+		log.Fatalf("Failed to deal the threshold key: %v", err)
+	}
+	if err := os.MkdirAll(*out, 0700); err != nil {
+		log.Fatal(err)
+	}
+	for _, share := range shares {
+		path := filepath.Join(*out, fmt.Sprintf("share-%d.json", share.Index))
+		if err := threshold.WriteKeyShare(path, share); err != nil {
+			log.Fatalf("Failed to write key share %d: %v", share.Index, err)
+		}
+		fmt.Println("wrote", path)
+	}
+	fmt.Println("group public key", hexutil.Encode(pub.Bytes()))
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const (
+	AppUrl = "http://localhost:9999"
+)
+
+// participant signs a participant registry record with the registry admin's
+// key (hex encoded in ADMIN_KEY) and submits it to the relayer.
+func main() {
+	address := flag.String("address", "", "the participant's address")
+	role := flag.String("role", string(types.RoleRequestor), "requestor, market_maker or auditor")
+	status := flag.String("status", string(types.ParticipantActive), "active, suspended or revoked")
+	maxAmount := flag.String("maxAmount", "", "maximum base token amount per RFQ or quote, empty for no limit")
+	maxOpenAmount := flag.String("maxOpenAmount", "", "maximum base token amount of a requestor's open RFQs, empty for no limit")
+	maxDailyAmount := flag.String("maxDailyAmount", "", "maximum base token amount of the RFQs a requestor opens in 24 hours, empty for no limit")
+	var pairLimits pairLimitFlags
+	flag.Var(&pairLimits, "pairLimit", "maximum open amount in a token pair as baseToken:quoteToken:amount, may be repeated")
+	expiresIn := flag.Duration("expiresIn", 0, "how long the onboarding is valid for, 0 for no expiry")
+	flag.Parse()
+
+	if !common.IsHexAddress(*address) {
+		log.Fatal("Please provide a valid participant address")
+	}
+	keyBytes, err := hexutil.Decode(os.Getenv("ADMIN_KEY"))
+	if err != nil {
+		log.Fatalf("Failed to decode ADMIN_KEY: %v", err)
+	}
+	adminKey, err := cryptoocax.PrivateKeyFromBytes(keyBytes)
+	if err != nil {
+		log.Fatalf("Failed to load admin key: %v", err)
+	}
+
+	now := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	participant := &types.Participant{
+		Address:   common.HexToAddress(*address),
+		Role:      types.ParticipantRole(*role),
+		Status:    types.ParticipantStatus(*status),
+		UpdatedAt: now,
+	}
+	participant.Limits.MaxBaseTokenAmount = parseAmount("maxAmount", *maxAmount)
+	participant.Limits.MaxOpenAmount = parseAmount("maxOpenAmount", *maxOpenAmount)
+	participant.Limits.MaxDailyAmount = parseAmount("maxDailyAmount", *maxDailyAmount)
+	participant.Limits.Pairs = pairLimits
+	if *expiresIn > 0 {
+		participant.ExpiresAt = now + uint64(expiresIn.Milliseconds())
+	}
+	if err := participant.Validate(); err != nil {
+		log.Fatal(err)
+	}
+	if err := participant.Sign(*adminKey); err != nil {
+		log.Fatalf("Failed to sign participant: %v", err)
+	}
+
+	body, err := json.Marshal(participant)
+	if err != nil {
+		log.Fatal(err)
+	}
+	resp, err := http.Post(AppUrl+"/participants", "application/json", bytes.NewReader(body))
+	if err != nil {
+		log.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	out, _ := io.ReadAll(resp.Body)
+	fmt.Printf("%s %s\n", resp.Status, out)
+}
+
+// parseAmount parses a limit flag, an empty value is no limit.
+func parseAmount(name, value string) *big.Int {
+	if value == "" {
+		return nil
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		log.Fatalf("Invalid %s: %s", name, value)
+	}
+	return amount
+}
+
+// pairLimitFlags collects the repeated pairLimit flag.
+type pairLimitFlags []types.PairLimit
+
+func (f *pairLimitFlags) String() string {
+	return fmt.Sprint(*f)
+}
+
+func (f *pairLimitFlags) Set(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 || !common.IsHexAddress(parts[0]) || !common.IsHexAddress(parts[1]) {
+		return fmt.Errorf("expected baseToken:quoteToken:amount, got %s", value)
+	}
+	amount, ok := new(big.Int).SetString(parts[2], 10)
+	if !ok {
+		return fmt.Errorf("invalid amount %s", parts[2])
+	}
+	*f = append(*f, types.PairLimit{
+		BaseToken:     common.HexToAddress(parts[0]),
+		QuoteToken:    common.HexToAddress(parts[1]),
+		MaxOpenAmount: amount,
+	})
+	return nil
+}
@@ -10,9 +10,12 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/OCAX-labs/rfqrelayer/api"
 	"github.com/OCAX-labs/rfqrelayer/common"
 	"github.com/OCAX-labs/rfqrelayer/core/types"
 	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 const (
@@ -34,7 +37,7 @@ func main() {
 
 	url := AppUrl + "/openRFQs/" + *rfqTxRef
 	fmt.Printf("Sending request to: %s\n", url)
-	resp, err := http.Get(url)
+	resp, err := signedGet(url, privateKey)
 	if err != nil {
 		log.Fatalf("Failed to send request: %v", err)
 	}
@@ -77,6 +80,13 @@ func main() {
 		EncryptionPublicKeys: []*cryptoocax.PublicKey{},
 	}
 
+	// seal the prices to the validators' threshold key when the relayer requires it
+	if pub := fetchEncryptionKey(privateKey); pub != nil {
+		if err := quoteData.SealPrices(pub); err != nil {
+			log.Fatalf("Failed to seal quote prices: %v", err)
+		}
+	}
+
 	quote := types.NewQuote(from, &quoteData)
 	tx := types.NewTx(quote)
 	signedTx, err := tx.Sign(privateKey)
@@ -109,7 +119,8 @@ func main() {
 			"baseTokenAmount": %d,
 			"bidPrice": %d,
 			"askPrice": %d,
-			"encryptionPublicKeys": []
+			"encryptionPublicKeys": [],
+			"encryptedQuote": "%s"
 		},
 		"signature": "%s"
 	}\n`,
@@ -126,10 +137,53 @@ func main() {
 		quoteData.BaseTokenAmount,
 		quoteData.BidPrice,
 		quoteData.AskPrice,
+		quoteData.EncryptedQuote,
 		signature.String(),
 	)
 }
 
+// fetchEncryptionKey returns the relayer's quote encryption key, or nil when
+// quotes are accepted in the clear
+func fetchEncryptionKey(privateKey cryptoocax.PrivateKey) *threshold.PublicKey {
+	resp, err := signedGet(AppUrl+"/encryptionKey", privateKey)
+	if err != nil {
+		log.Fatalf("Failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil
+	}
+
+	var key struct {
+		PublicKey string `json:"publicKey"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&key); err != nil {
+		log.Fatalf("Failed to decode encryption key: %v", err)
+	}
+	pubBytes, err := hexutil.Decode(key.PublicKey)
+	if err != nil {
+		log.Fatalf("Failed to decode encryption key: %v", err)
+	}
+	pub, err := threshold.PublicKeyFromBytes(pubBytes)
+	if err != nil {
+		log.Fatalf("Failed to decode encryption key: %v", err)
+	}
+	return pub
+}
+
+// signedGet sends a GET request signed by the quoter so it is served when the
+// relayer requires signed requests
+func signedGet(url string, key cryptoocax.PrivateKey) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := api.SignRequest(req, nil, key); err != nil {
+		return nil, err
+	}
+	return http.DefaultClient.Do(req)
+}
+
 // helper function to generate random bid and ask prices
 // the function takes an integer (i) as input and returns a random number that
 // is between 0.9*i and 1.1*i the returned number is then converted to a big Int
@@ -57,10 +57,11 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to sign data: %v", err)
 	}
+	if err := signedTx.Verify(); err != nil {
+		log.Fatalf("Failed to verify signature: %v", err)
+	}
 	v, r, s := signedTx.RawSignatureValues()
-	vStr := fmt.Sprintf("0x%x", v)
-	rStr := fmt.Sprintf("0x%x", r)
-	sStr := fmt.Sprintf("0x%x", s)
+	signature := cryptoocax.Signature{V: v, R: r, S: s}
 	fmt.Printf(`{
     "from": "%s",
     "data": {
@@ -78,9 +79,7 @@ func main() {
         },
         "rfqDurationMs": %d
     },
-    "v": "%s",
-    "r": "%s",
-    "s": "%s"
+    "signature": "%s"
 }\n`,
 		addr.Hex(),
 		uid,
@@ -92,8 +91,6 @@ func main() {
 		signableData.QuoteToken.Symbol,
 		signableData.QuoteToken.Decimals,
 		signableData.RFQDurationMs,
-		vStr,
-		rStr,
-		sStr,
+		signature.String(),
 	)
 }
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/rawdb"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/OCAX-labs/rfqrelayer/matching"
+	"github.com/OCAX-labs/rfqrelayer/rfqdb/pebble"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	cache   = 1048
+	handles = 2
+)
+
+// verify replays the matching of a closed auction and checks the recorded
+// match result and its validator signature. The auction is loaded from a
+// node's database (which must not be in use by a running node) or from a
+// snapshot previously exported with -export. The JSON report is written to
+// stdout and the exit code is 1 when verification fails.
+func main() {
+	dbPath := flag.String("db", "", "path to the node's pebble database")
+	rfqTxRef := flag.String("rfqTxRef", "", "the RFQ tx reference of the auction to verify (with -db)")
+	snapshotPath := flag.String("snapshot", "", "path to an exported auction snapshot")
+	exportPath := flag.String("export", "", "write the auction loaded from -db to this snapshot file")
+	engineName := flag.String("engine", "", "matching engine to replay with, defaults to the recorded engine")
+	flag.Parse()
+
+	var (
+		snapshot *matching.Snapshot
+		err      error
+	)
+	switch {
+	case *snapshotPath != "":
+		snapshot, err = loadSnapshot(*snapshotPath)
+	case *dbPath != "" && *rfqTxRef != "":
+		snapshot, err = loadFromDB(*dbPath, common.HexToHash(*rfqTxRef))
+	default:
+		log.Fatal("Please provide either -snapshot or -db and -rfqTxRef")
+	}
+	if err != nil {
+		log.Fatalf("Failed to load auction: %v", err)
+	}
+
+	if *exportPath != "" {
+		if err := writeJSON(*exportPath, snapshot); err != nil {
+			log.Fatalf("Failed to export snapshot: %v", err)
+		}
+	}
+
+	name := *engineName
+	if name == "" && snapshot.MatchResult != nil {
+		name = snapshot.MatchResult.Engine
+	}
+	if name == "" {
+		name = matching.BestPriceEngineName
+	}
+	engine, err := matching.NewEngine(name)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	report := matching.Verify(engine, snapshot)
+	out, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println(string(out))
+
+	if !report.Verified {
+		os.Exit(1)
+	}
+}
+
+func loadSnapshot(path string) (*matching.Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := new(matching.Snapshot)
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func loadFromDB(path string, rfqTxHash common.Hash) (*matching.Snapshot, error) {
+	db, err := pebble.New(path, cache, handles, "rfq", true)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+
+	closedData, err := rawdb.NewTable(db, rawdb.ClosedRFQsTable).Get(rfqTxHash.Bytes())
+	if err != nil || len(closedData) == 0 {
+		return nil, fmt.Errorf("closedRFQ with hash [%x] not found", rfqTxHash)
+	}
+	closedRFQ := new(types.OpenRFQ)
+	if err := rlp.DecodeBytes(closedData, closedRFQ); err != nil {
+		return nil, fmt.Errorf("error decoding ClosedRFQ: %w", err)
+	}
+
+	snapshot := &matching.Snapshot{ClosedRFQ: closedRFQ}
+	matchData, err := rawdb.NewTable(db, rawdb.MatchedRFQsTable).Get(rfqTxHash.Bytes())
+	if err == nil && len(matchData) > 0 {
+		snapshot.MatchResult = new(types.MatchResult)
+		if err := rlp.DecodeBytes(matchData, snapshot.MatchResult); err != nil {
+			return nil, fmt.Errorf("error decoding match result: %w", err)
+		}
+	}
+	return snapshot, nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"bytes"
 	"container/heap"
+	"errors"
 	"fmt"
 	"math/big"
 	"sync"
@@ -19,15 +20,32 @@ import (
 	"github.com/go-kit/log"
 )
 
+var (
+	// ErrTxNotFound is returned for transactions that are not in any block.
+	ErrTxNotFound = errors.New("transaction not found")
+	// ErrBlockNotFound is returned for blocks that are not in the chain.
+	ErrBlockNotFound = errors.New("block not found")
+	// ErrRFQNotFound is returned for RFQ requests, open and closed RFQs
+	// unknown to this node.
+	ErrRFQNotFound = errors.New("RFQ not found")
+)
+
 //go:generate mockery --name=ChainInterface --output=../mocks --case=underscore
 type ChainInterface interface {
 	GetTxByHash(hash common.Hash) (*types.Transaction, error)
+	GetIncludedTx(hash common.Hash) (*types.Transaction, *types.TxInclusion, error)
 	GetBlockByHash(hash common.Hash) (*types.Block, error)
 	GetBlock(height *big.Int) (*types.Block, error)
 	GetBlockHeader(height *big.Int) (*types.Header, error)
 	GetRFQRequests() ([]*types.RFQRequest, error)
+	GetRFQRequestByHash(hash common.Hash) (*types.RFQRequest, error)
 	GetOpenRFQRequests() ([]*types.OpenRFQ, error)
 	GetClosedRFQRequests() ([]*types.OpenRFQ, error)
+	GetClosedRFQByHash(hash common.Hash) (*types.OpenRFQ, error)
+	WriteParticipant(p *types.Participant) error
+	GetParticipant(addr common.Address) (*types.Participant, error)
+	GetParticipants() ([]*types.Participant, error)
+	CheckParticipant(addr common.Address, role types.ParticipantRole, amount *big.Int, at uint64) error
 	WriteRFQTxs(tx *types.Transaction) error
 	GetOpenRFQByHash(hash common.Hash) (*types.OpenRFQ, error)
 	GetAuctionQuotes(rfqTxhash common.Hash) ([]*types.Quote, error)
@@ -47,6 +65,11 @@ type Blockchain struct {
 	// as quotes are received the openRFQS are updated by appending to the quotes array
 	openRFQS     []*types.OpenRFQ
 	auctionQueue types.AuctionQueue
+	// quit stops the auction queue manager, which closes auctionsDone once
+	// it has returned
+	quit         chan struct{}
+	auctionsDone chan struct{}
+	stopOnce     sync.Once
 
 	openRFQsMap map[common.Hash]*types.OpenRFQ
 	// tracks all closed RFQS which are not yet matched in memory
@@ -61,9 +84,26 @@ type Blockchain struct {
 	matchedRFQSTable rfqdb.Database
 	settledRFQSTable rfqdb.Database
 	quotesTable      rfqdb.Database
+	// rfqIndexTable lists the RFQs by status, requestor and token pair
+	rfqIndexTable rfqdb.Database
+	// marketStatsTable aggregates closed auctions per token pair
+	marketStatsTable rfqdb.Database
+	// mempoolTable keeps the pending transactions over a restart
+	mempoolTable rfqdb.Database
+	// riskUsageTable keeps the RFQs counted against risk limits
+	riskUsageTable rfqdb.Database
+
+	// onboarded participants, maintained by the registry admin
+	participantsTable rfqdb.Database
+	participantAdmin  common.Address
+	enforceWhitelist  bool
+
+	// the API's log of RFQ lifecycle events, keyed by sequence number
+	eventsTable rfqdb.Database
+	// the API's webhooks and their undeliverable events
+	webhooksTable    rfqdb.Database
+	deadLettersTable rfqdb.Database
 
-	// TODO: Remove this
-	txStore map[common.Hash]*types.Transaction
 	// blockStore map[common.Hash]*Block
 	genesisBlock *types.Block
 
@@ -81,12 +121,20 @@ type EventChan chan types.TxEvent
 func NewBlockchain(l log.Logger, genesis *types.Block, db *pebble.Database, validator bool) (*Blockchain, error) {
 
 	// initialize tables in the kv store for storing the differnt types of Txs
-	rfqRequestsTable := rawdb.NewTable(db, "rfqRequests")
-	openRFQSTable := rawdb.NewTable(db, "openRFQs")
-	closedRFQSTable := rawdb.NewTable(db, "closeRFQs")
-	matchedRFQSTable := rawdb.NewTable(db, "matchedRFQs")
-	settledRFQSTable := rawdb.NewTable(db, "settledRFQs")
-	quotesTable := rawdb.NewTable(db, "quotes")
+	rfqRequestsTable := rawdb.NewTable(db, rawdb.RFQRequestsTable)
+	openRFQSTable := rawdb.NewTable(db, rawdb.OpenRFQsTable)
+	closedRFQSTable := rawdb.NewTable(db, rawdb.ClosedRFQsTable)
+	matchedRFQSTable := rawdb.NewTable(db, rawdb.MatchedRFQsTable)
+	settledRFQSTable := rawdb.NewTable(db, rawdb.SettledRFQsTable)
+	quotesTable := rawdb.NewTable(db, rawdb.QuotesTable)
+	participantsTable := rawdb.NewTable(db, rawdb.ParticipantsTable)
+	eventsTable := rawdb.NewTable(db, rawdb.EventsTable)
+	webhooksTable := rawdb.NewTable(db, rawdb.WebhooksTable)
+	deadLettersTable := rawdb.NewTable(db, rawdb.WebhookDeadLettersTable)
+	rfqIndexTable := rawdb.NewTable(db, rawdb.RFQIndexTable)
+	marketStatsTable := rawdb.NewTable(db, rawdb.MarketStatsTable)
+	mempoolTable := rawdb.NewTable(db, rawdb.MempoolTable)
+	riskUsageTable := rawdb.NewTable(db, rawdb.RiskUsageTable)
 	bc := &Blockchain{
 		headers: []*types.Header{},
 		db:      db,
@@ -108,19 +156,50 @@ func NewBlockchain(l log.Logger, genesis *types.Block, db *pebble.Database, vali
 		matchedRFQSTable: matchedRFQSTable,
 		settledRFQSTable: settledRFQSTable,
 		quotesTable:      quotesTable,
-		// mapping of OpenRfqs to TxHash for quick lookup retrieval from the db
-		txStore: make(map[common.Hash]*types.Transaction),
+		rfqIndexTable:    rfqIndexTable,
+		marketStatsTable: marketStatsTable,
+		mempoolTable:     mempoolTable,
+		riskUsageTable:   riskUsageTable,
+
+		participantsTable: participantsTable,
+		eventsTable:       eventsTable,
+		webhooksTable:     webhooksTable,
+		deadLettersTable:  deadLettersTable,
+	}
+	if err := bc.reindexRFQs(); err != nil {
+		return nil, err
+	}
+	if err := bc.rebuildMarketStats(); err != nil {
+		return nil, err
+	}
+	if blocks, err := rawdb.BackfillTxLookupEntries(db); err != nil {
+		return nil, err
+	} else if blocks > 0 {
+		l.Log("msg", "Indexed the transactions of stored blocks", "blocks", blocks)
 	}
 	bc.EventChan = make(EventChan)
 	bc.auctionQueue = make(types.AuctionQueue, 0)
 	heap.Init(&bc.auctionQueue)
+	bc.quit = make(chan struct{})
+	bc.auctionsDone = make(chan struct{})
+	if err := bc.loadOpenRFQs(); err != nil {
+		return nil, err
+	}
+
+	if err := bc.loadHeaders(); err != nil {
+		return nil, err
+	}
 
 	if validator {
 		bc.SetValidator(NewBlockValidator(bc))
-		err := bc.addBlockWithoutValidation(genesis)
-		if err != nil {
-			return nil, err
+		// a validator restarting on its own database keeps its chain
+		if len(bc.headers) == 0 {
+			if err := bc.addBlockWithoutValidation(genesis); err != nil {
+				return nil, err
+			}
 		}
+	}
+	if len(bc.headers) > 0 {
 		genesis, err := bc.GetBlock(big.NewInt(0))
 		if err != nil {
 			return nil, err
@@ -129,14 +208,34 @@ func NewBlockchain(l log.Logger, genesis *types.Block, db *pebble.Database, vali
 			return nil, fmt.Errorf("failed to load genesis block")
 		}
 		bc.genesisBlock = genesis
+		bc.currentBlock.Store(bc.headers[len(bc.headers)-1])
+	} else {
+		bc.currentBlock.Store(nil)
 	}
-
-	bc.currentBlock.Store(nil)
 	bc.startAuctionQueueManager()
 
 	return bc, nil
 }
 
+// loadHeaders reads the headers of the chain stored by an earlier run.
+func (bc *Blockchain) loadHeaders() error {
+	for height := uint64(0); ; height++ {
+		hash := rawdb.ReadCanonicalHash(bc.db, height)
+		if hash == (common.Hash{}) {
+			break
+		}
+		header := rawdb.ReadHeader(bc.db, hash, height)
+		if header == nil {
+			return fmt.Errorf("header [%x] of block %d not found", hash, height)
+		}
+		bc.headers = append(bc.headers, header)
+	}
+	if len(bc.headers) > 0 {
+		bc.logger.Log("msg", "Loaded the chain from the kv store", "height", len(bc.headers)-1)
+	}
+	return nil
+}
+
 func (bc *Blockchain) SetValidator(v Validator) {
 	bc.validator = v
 }
@@ -155,7 +254,10 @@ func (bc *Blockchain) VerifyBlock(b *types.Block) error {
 		}
 	}
 
-	// validate transactions
+	// validate transactions, the participants sending them were checked
+	// against the whitelist when the transactions were admitted: the
+	// registry changes over time, checking blocks against it would reject
+	// history a node replays after a participant is removed
 	for _, tx := range b.Transactions() {
 		if err := tx.Verify(); err != nil {
 			fmt.Printf("Failed to verify transaction: %+v\n", tx)
@@ -169,51 +271,65 @@ func (bc *Blockchain) VerifyBlock(b *types.Block) error {
 	return bc.addBlockWithoutValidation(b)
 }
 
+// GetBlockByHash returns the block of the chain with the given block or
+// header hash.
 func (bc *Blockchain) GetBlockByHash(hash common.Hash) (*types.Block, error) {
-	bc.lock.Lock()
-	defer bc.lock.Unlock()
-
-	// block, ok := bc.blockStore[hash]
-	// if !ok {
-	// 	return nil, fmt.Errorf("block with hash [%x] not found", hash)
-	// }
-	return &types.Block{}, nil
+	height := rawdb.ReadHeaderNumber(bc.db, hash)
+	if height == nil {
+		return nil, fmt.Errorf("%w: [%x]", ErrBlockNotFound, hash)
+	}
+	block, err := bc.GetBlock(new(big.Int).SetUint64(*height))
+	if err != nil {
+		return nil, err
+	}
+	if block.Hash() != hash && block.Header().Hash() != hash {
+		return nil, fmt.Errorf("%w: [%x]", ErrBlockNotFound, hash)
+	}
+	return block, nil
 }
 
 func (bc *Blockchain) GetTxByHash(hash common.Hash) (*types.Transaction, error) {
-	bc.lock.Lock()
-	defer bc.lock.Unlock()
+	tx, _, err := bc.GetIncludedTx(hash)
+	return tx, err
+}
 
-	tx, ok := bc.txStore[hash]
-	if !ok {
-		return nil, fmt.Errorf("transaction with hash [%x] not found", hash)
+// GetIncludedTx returns a transaction included in a block, found through the
+// transaction index, and where it was included.
+func (bc *Blockchain) GetIncludedTx(hash common.Hash) (*types.Transaction, *types.TxInclusion, error) {
+	entry := rawdb.ReadTxLookupEntry(bc.db, hash)
+	if entry == nil {
+		return nil, nil, fmt.Errorf("%w: [%x]", ErrTxNotFound, hash)
 	}
-	return tx, nil
+	block := rawdb.ReadBlock(bc.db, entry.HeaderHash, entry.BlockHeight)
+	if block == nil || entry.Index >= uint64(len(block.Transactions())) {
+		return nil, nil, fmt.Errorf("block [%x] of transaction [%x] not found", entry.HeaderHash, hash)
+	}
+	return block.Transactions()[entry.Index], &types.TxInclusion{
+		BlockHash:   block.Hash(),
+		BlockHeight: entry.BlockHeight,
+		Index:       entry.Index,
+		Timestamp:   block.Timestamp(),
+	}, nil
 }
 
 func (bc *Blockchain) GetBlock(height *big.Int) (*types.Block, error) {
-	currHeight := bc.Height()
-	reqHeight := height.Int64()
-	if height.Cmp(currHeight) == 1 {
-		return nil, fmt.Errorf("blockchain height [%s] is less than requested height [%d]", currHeight.String(), reqHeight)
-	}
-	bc.lock.RLock()
 	blockHeader, err := bc.GetBlockHeader(height)
 	if err != nil {
 		return nil, err
 	}
-	block := rawdb.ReadBlock(bc.db, blockHeader.Hash(), uint64(reqHeight))
+	block := rawdb.ReadBlock(bc.db, blockHeader.Hash(), blockHeader.Height.Uint64())
 	if block == nil {
-		return nil, fmt.Errorf("block with hash [%x] not found", blockHeader.Hash())
+		return nil, fmt.Errorf("%w: [%x]", ErrBlockNotFound, blockHeader.Hash())
 	}
-	bc.lock.RUnlock()
-
 	return block, nil
 }
 
 func (bc *Blockchain) GetBlockHeader(height *big.Int) (*types.Header, error) {
-	if height.Cmp(big.NewInt(int64(len(bc.headers)))) == 1 {
-		return nil, fmt.Errorf("blockchain height [%d] is less than requested height [%d]", len(bc.headers), height.Int64())
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	if height.Sign() < 0 || height.Cmp(big.NewInt(int64(len(bc.headers)))) >= 0 {
+		return nil, fmt.Errorf("%w: blockchain height [%d] is less than requested height [%d]", ErrBlockNotFound, len(bc.headers)-1, height.Int64())
 	}
 	return bc.headers[height.Int64()], nil
 }
@@ -252,7 +368,7 @@ func (bc *Blockchain) GetOpenRFQByHash(hash common.Hash) (*types.OpenRFQ, error)
 
 	openRFQ, ok := bc.openRFQsMap[hash]
 	if !ok {
-		return nil, fmt.Errorf("openRFQ with hash [%x] not found", hash)
+		return nil, fmt.Errorf("openRFQ with hash [%x]: %w", hash, ErrRFQNotFound)
 	}
 	return openRFQ, nil
 }
@@ -280,41 +396,59 @@ func (bc *Blockchain) startAuctionQueueManager() {
 	go func() {
 		ticker := time.NewTicker(time.Millisecond * 1000) // adjust the duration to your needs
 		defer ticker.Stop()
+		defer close(bc.auctionsDone)
 
 		for {
 			select {
 			case <-ticker.C:
 				bc.processAuctionQueue()
+			case <-bc.quit:
+				return
 			}
 		}
 	}()
 }
 
 func (bc *Blockchain) processAuctionQueue() {
-	// bc.lock.Lock() // remember to use your lock to prevent data races
-	// defer bc.lock.Unlock()
 	now := time.Now().UnixNano() / int64(time.Millisecond)
-	// current Unix timestamp
-	var startAuction int64
-	var endAuction int64
+	// the ended auctions are taken off the queue under the lock, which is
+	// released before they are handed on as storing them takes it again
+	bc.lock.Lock()
 	if len(bc.auctionQueue) > 0 {
-		startAuction = bc.auctionQueue[0].Data.RFQStartTime
-		endAuction = bc.auctionQueue[0].Data.RFQEndTime
-		fmt.Printf("now: [%d] startAuction: [%d] endAuction: [%d] \n", now, startAuction, endAuction)
+		fmt.Printf("now: [%d] startAuction: [%d] endAuction: [%d] \n", now, bc.auctionQueue[0].Data.RFQStartTime, bc.auctionQueue[0].Data.RFQEndTime)
 	}
+	var ended []*types.OpenRFQ
 	for len(bc.auctionQueue) > 0 && bc.auctionQueue[0].Data.RFQEndTime <= now {
-		auction := heap.Pop(&bc.auctionQueue).(*types.OpenRFQ)
+		ended = append(ended, heap.Pop(&bc.auctionQueue).(*types.OpenRFQ))
+	}
+	bc.lock.Unlock()
+
+	for _, auction := range ended {
 		fmt.Printf("Auction Ended: %x\n", auction.Data.RFQTxHash)
 		auction.Data.Close()
 		closedAuctionTx := types.NewTx(auction)
-		bc.WriteRFQTxs(closedAuctionTx)
-		bc.EventChan <- types.TxEvent{TxType: types.OpenRFQTxType, TxHash: closedAuctionTx.Hash(), Transaction: closedAuctionTx}
+		// the validator signs the closed auction before it is persisted and
+		// submitted on chain
+		select {
+		case bc.EventChan <- types.TxEvent{TxType: types.OpenRFQTxType, TxHash: closedAuctionTx.Hash(), Transaction: closedAuctionTx}:
+		case <-bc.quit:
+			// the auctions not handed on are saved as open by Stop and
+			// closed once the node is back
+			return
+		}
+	}
+}
 
-		// Pop the auction from the queue
-		// Do something with the auction that has ended
-		// For example, close the auction and save it to the DB
-		// Remember to broadcast the closing event to your ws server
+// AuctionQueueStatus returns the number of open auctions waiting to close and
+// the end time in unix milliseconds of the next one, 0 when none is queued.
+func (bc *Blockchain) AuctionQueueStatus() (int, int64) {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	if len(bc.auctionQueue) == 0 {
+		return 0, 0
 	}
+	return len(bc.auctionQueue), bc.auctionQueue[0].Data.RFQEndTime
 }
 
 func (bc *Blockchain) WriteRFQTxs(tx *types.Transaction) error {
@@ -358,6 +492,9 @@ func (bc *Blockchain) WriteRFQTxs(tx *types.Transaction) error {
 		// as all other transaction types refer to this RFQ they will be saved in their
 		// respective tables with the same key
 		err = bc.rfqRequestsTable.Put(tx.Hash().Bytes(), encRFQ.Bytes())
+		if err == nil {
+			err = bc.indexRFQRequest(tx.Hash(), rfqRequest, uint64(time.Now().UnixMilli()))
+		}
 	case types.OpenRFQTxType:
 
 		v, r, s := tx.RawSignatureValues()
@@ -400,6 +537,12 @@ func (bc *Blockchain) WriteRFQTxs(tx *types.Transaction) error {
 		default:
 			return fmt.Errorf("unknown RFQ status: %s", openRFQ.Data.Status)
 		}
+		if err == nil {
+			err = bc.indexOpenRFQ(openRFQ.Data)
+		}
+		if err == nil && openRFQ.Data.Status == types.RFQStatusClosed {
+			err = bc.recordClosedRFQ(openRFQ.Data)
+		}
 
 	case types.QuoteTxType:
 		// get the raw signature values
@@ -413,6 +556,10 @@ func (bc *Blockchain) WriteRFQTxs(tx *types.Transaction) error {
 			S:    s,
 		}
 
+		if err := bc.recordQuoteLatency(tx.ReferenceTxHash(), uint64(time.Now().UnixMilli())); err != nil {
+			return err
+		}
+
 		// retrieve existing quotes
 		existingQuotesBytes, _ := bc.quotesTable.Get(tx.ReferenceTxHash().Bytes())
 		var existingQuotes types.Quotes
@@ -468,6 +615,19 @@ func (bc *Blockchain) GetRFQRequests() ([]*types.RFQRequest, error) {
 	return rfqRequests, nil
 }
 
+func (bc *Blockchain) GetRFQRequestByHash(hash common.Hash) (*types.RFQRequest, error) {
+	data, err := bc.rfqRequestsTable.Get(hash.Bytes())
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("rfqRequest with hash [%x]: %w", hash, ErrRFQNotFound)
+	}
+
+	var rfqRequest types.RFQRequest
+	if err := rlp.DecodeBytes(data, &rfqRequest); err != nil {
+		return nil, fmt.Errorf("error decoding RFQRequest: %w", err)
+	}
+	return &rfqRequest, nil
+}
+
 func (bc *Blockchain) GetOpenRFQRequests() ([]*types.OpenRFQ, error) {
 	var openRFQs []*types.OpenRFQ
 
@@ -571,9 +731,14 @@ func (bc *Blockchain) UpdateActiveRFQ(rfqTxHash common.Hash, quote *types.Quote)
 
 func (bc *Blockchain) addBlockWithoutValidation(b *types.Block) error {
 	bc.lock.Lock()
-	bc.headers = append(bc.headers, b.Header())
 	// write the block which includes all transactions to the kv store
 	rawdb.WriteBlock(bc.db, b)
+	if err := rawdb.WriteTxLookupEntries(bc.db, b); err != nil {
+		bc.lock.Unlock()
+		return err
+	}
+	rawdb.WriteCanonicalHash(bc.db, b.Header().Hash(), b.HeightU64())
+	bc.headers = append(bc.headers, b.Header())
 
 	bc.lock.Unlock()
 	bc.logger.Log("msg", "Block saved to the kv store", "hash", b.Hash(), "height", b.Height().String(), "txs", len(b.Transactions()))
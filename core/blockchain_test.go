@@ -16,6 +16,7 @@ import (
 	"github.com/OCAX-labs/rfqrelayer/rfqdb/pebble"
 	"github.com/go-kit/log"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const dbPath = "../.testdb"
@@ -193,6 +194,66 @@ func TestGetHeader(t *testing.T) {
 
 }
 
+func TestGetIncludedTx(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"getincludedtx")
+	defer teardown()
+
+	block := randomBlockWithSignature(t, testKey, 1, getPrevBlockHash(t, bc, big.NewInt(0)))
+	assert.Nil(t, bc.VerifyBlock(block))
+
+	want := block.Transactions()[1]
+	tx, inclusion, err := bc.GetIncludedTx(want.Hash())
+	assert.Nil(t, err)
+	transactionsEqual(t, want, tx)
+	assert.Equal(t, &types.TxInclusion{
+		BlockHash:   block.Hash(),
+		BlockHeight: 1,
+		Index:       1,
+		Timestamp:   block.Timestamp(),
+	}, inclusion)
+
+	_, err = bc.GetTxByHash(RandomHash())
+	assert.ErrorIs(t, err, ErrTxNotFound)
+}
+
+func TestGetBlockByHash(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"getblockbyhash")
+	defer teardown()
+
+	block := randomBlockWithSignature(t, testKey, 1, getPrevBlockHash(t, bc, big.NewInt(0)))
+	assert.Nil(t, bc.VerifyBlock(block))
+
+	// blocks are found by the block hash and the hash of their header
+	for _, hash := range []common.Hash{block.Hash(), block.Header().Hash()} {
+		b, err := bc.GetBlockByHash(hash)
+		assert.Nil(t, err)
+		assert.Equal(t, block.Header(), b.Header())
+		assert.Equal(t, len(block.Transactions()), len(b.Transactions()))
+	}
+
+	_, err := bc.GetBlockByHash(RandomHash())
+	assert.ErrorIs(t, err, ErrBlockNotFound)
+}
+
+func TestLoadHeaders(t *testing.T) {
+	db, teardown := setupDB(t, dbPath+"loadheaders")
+	defer teardown()
+
+	bc, err := NewBlockchain(log.NewNopLogger(), randomBlockWithSignature(t, testKey, 0, common.Hash{}), db, true)
+	assert.Nil(t, err)
+	for i := 0; i < 2; i++ {
+		block := randomBlockWithSignature(t, testKey, uint64(i+1), getPrevBlockHash(t, bc, big.NewInt(int64(i))))
+		assert.Nil(t, bc.VerifyBlock(block))
+	}
+
+	// a restarted node keeps its chain rather than starting from a new genesis
+	restarted, err := NewBlockchain(log.NewNopLogger(), randomBlockWithSignature(t, testKey, 0, common.Hash{}), db, true)
+	assert.Nil(t, err)
+	assert.Equal(t, bc.headers, restarted.headers)
+	assert.Equal(t, bc.genesisBlock.Hash(), restarted.genesisBlock.Hash())
+	assert.Equal(t, bc.headers[2], restarted.CurrentBlock())
+}
+
 func TestAddBlockToHigh(t *testing.T) {
 	bc, teardown := newBlockchainWithGenesis(t, dbPath+"addblocktohigh")
 	defer teardown()
@@ -205,7 +266,6 @@ func newBlockchainWithGenesis(t *testing.T, dbPath string) (*Blockchain, func())
 	// Create the database
 	cleanDb(t, dbPath)
 	db, teardown := setupDB(t, dbPath)
-	defer teardown()
 	bc, err := NewBlockchain(log.NewNopLogger(), randomBlockWithSignature(t, testKey, 0, common.Hash{}), db, true)
 	assert.Nil(t, err)
 	assert.NotNil(t, bc.genesisBlock)
@@ -359,3 +419,23 @@ func setupDB(t *testing.T, dbPath string) (db *pebble.Database, teardown func())
 		removeTestDB()
 	}
 }
+
+func TestAuctionQueueStatus(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"auctionqueue")
+	defer teardown()
+
+	depth, next := bc.AuctionQueueStatus()
+	assert.Equal(t, 0, depth)
+	assert.Equal(t, int64(0), next)
+
+	// auctions are queued by the time they end, not by when they opened
+	now := time.Now().UnixMilli()
+	for _, start := range []int64{now + 60000, now + 30000} {
+		tx := randomTxWithSignature(t, testKey)
+		require.NoError(t, bc.WriteRFQTxs(tx))
+		require.NoError(t, bc.WriteRFQTxs(openRFQTx(tx, start, types.RFQStatusOpen)))
+	}
+	depth, next = bc.AuctionQueueStatus()
+	assert.Equal(t, 2, depth)
+	assert.Equal(t, now+35000, next)
+}
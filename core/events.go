@@ -0,0 +1,37 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+func eventKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+// WriteEvent stores an encoded lifecycle event under its sequence number.
+func (bc *Blockchain) WriteEvent(seq uint64, data []byte) error {
+	if err := bc.eventsTable.Put(eventKey(seq), data); err != nil {
+		return fmt.Errorf("error writing event to kv store tables: %s", err.Error())
+	}
+	return nil
+}
+
+// DeleteEvent removes the event with the given sequence number.
+func (bc *Blockchain) DeleteEvent(seq uint64) error {
+	return bc.eventsTable.Delete(eventKey(seq))
+}
+
+// GetEvents returns the stored events in sequence order.
+func (bc *Blockchain) GetEvents() ([][]byte, error) {
+	it := bc.eventsTable.NewIterator(nil, nil)
+	defer it.Release()
+
+	var events [][]byte
+	for it.Next() {
+		events = append(events, append([]byte(nil), it.Value()...))
+	}
+	return events, it.Error()
+}
@@ -0,0 +1,23 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventLogStore(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"events")
+	defer teardown()
+
+	// keys sort by sequence number, not lexically by their decimal form
+	for _, seq := range []uint64{2, 256, 1} {
+		require.NoError(t, bc.WriteEvent(seq, []byte{byte(seq)}))
+	}
+	require.NoError(t, bc.DeleteEvent(2))
+
+	events, err := bc.GetEvents()
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{{1}, {0}}, events)
+}
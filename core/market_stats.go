@@ -0,0 +1,391 @@
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Market data is aggregated per token pair as auctions close, so reading it
+// never scans the RFQ tables. A pair's totals are kept under
+//
+//	statsTotalPrefix + base token address + quote token address
+//
+// and the auctions that closed in each hour under the same key followed by
+// the start of the hour (uint64 big endian). Only public RFQs are counted,
+// the size of a private RFQ would show in a quiet period.
+var (
+	statsTotalPrefix   = []byte("t")
+	statsPeriodPrefix  = []byte("h")
+	statsLatencyPrefix = []byte("l") // rfqTxHash -> latencies of its quotes so far
+	statsClosedPrefix  = []byte("c") // rfqTxHash -> pair + period it was counted in
+	statsMatchedPrefix = []byte("m") // rfqTxHash -> set once its spread is counted
+)
+
+// StatsPeriodMs is the length of the periods market data is kept for, in
+// milliseconds. Longer intervals are merged from them.
+const StatsPeriodMs = uint64(time.Hour / time.Millisecond)
+
+// SpreadBoundsBps are the upper bounds, in basis points of the mid price, of
+// the buckets the best bid/ask spreads of matched auctions are counted in.
+// The first bucket holds crossed markets and the last is open ended.
+var SpreadBoundsBps = []int64{0, 1, 5, 10, 25, 50, 100, 250}
+
+// LatencyBoundsMs are the upper bounds, in milliseconds after the RFQ opened,
+// of the buckets quote response times are counted in. The last bucket is
+// open ended.
+var LatencyBoundsMs = []uint64{100, 250, 500, 1000, 2500, 5000, 10000, 30000}
+
+var ErrInvalidInterval = errors.New("interval must be a positive multiple of an hour")
+
+// PairStats aggregates the closed auctions of a token pair, over the life of
+// the node or over one period.
+type PairStats struct {
+	BaseToken  common.Address
+	QuoteToken common.Address
+	// Time is the start of the period in unix milliseconds, 0 for totals
+	Time uint64
+
+	// RFQs is the number of closed auctions, QuotedRFQs those that received
+	// at least one quote
+	RFQs       uint64
+	QuotedRFQs uint64
+	Quotes     uint64
+	// RequestedVolume is the base token amount of the closed auctions and
+	// QuotedVolume that of the auctions that received quotes
+	RequestedVolume *big.Int
+	QuotedVolume    *big.Int
+
+	// Spreads counts the matched auctions with both a bid and an ask in the
+	// buckets of SpreadBoundsBps
+	Spreads []uint64
+
+	// Latencies counts quote response times in the buckets of
+	// LatencyBoundsMs, with their sum and range
+	Latencies    []uint64
+	LatencySumMs uint64
+	LatencyMinMs uint64
+	LatencyMaxMs uint64
+}
+
+func newPairStats(base, quote common.Address, t uint64) *PairStats {
+	return &PairStats{
+		BaseToken:       base,
+		QuoteToken:      quote,
+		Time:            t,
+		RequestedVolume: new(big.Int),
+		QuotedVolume:    new(big.Int),
+		Spreads:         make([]uint64, len(SpreadBoundsBps)+1),
+		Latencies:       make([]uint64, len(LatencyBoundsMs)+1),
+	}
+}
+
+// QuoteLatencies is the number of quote response times counted.
+func (s *PairStats) QuoteLatencies() uint64 {
+	var n uint64
+	for _, count := range s.Latencies {
+		n += count
+	}
+	return n
+}
+
+func (s *PairStats) addLatency(ms uint64) {
+	if s.QuoteLatencies() == 0 || ms < s.LatencyMinMs {
+		s.LatencyMinMs = ms
+	}
+	if ms > s.LatencyMaxMs {
+		s.LatencyMaxMs = ms
+	}
+	s.LatencySumMs += ms
+	i := 0
+	for i < len(LatencyBoundsMs) && ms >= LatencyBoundsMs[i] {
+		i++
+	}
+	s.Latencies[i]++
+}
+
+func (s *PairStats) addSpread(bps int64) {
+	i := 0
+	for i < len(SpreadBoundsBps) && bps >= SpreadBoundsBps[i] {
+		i++
+	}
+	s.Spreads[i]++
+}
+
+// merge adds the counts of other, a period of the same pair.
+func (s *PairStats) merge(other *PairStats) {
+	if other.QuoteLatencies() > 0 {
+		if s.QuoteLatencies() == 0 || other.LatencyMinMs < s.LatencyMinMs {
+			s.LatencyMinMs = other.LatencyMinMs
+		}
+		if other.LatencyMaxMs > s.LatencyMaxMs {
+			s.LatencyMaxMs = other.LatencyMaxMs
+		}
+	}
+	s.RFQs += other.RFQs
+	s.QuotedRFQs += other.QuotedRFQs
+	s.Quotes += other.Quotes
+	s.RequestedVolume.Add(s.RequestedVolume, other.RequestedVolume)
+	s.QuotedVolume.Add(s.QuotedVolume, other.QuotedVolume)
+	for i := range s.Spreads {
+		s.Spreads[i] += other.Spreads[i]
+	}
+	for i := range s.Latencies {
+		s.Latencies[i] += other.Latencies[i]
+	}
+	s.LatencySumMs += other.LatencySumMs
+}
+
+// spreadBps is the spread between the best bid and ask in basis points of
+// their mid price, negative when the market is crossed.
+func spreadBps(bid, ask *big.Int) (int64, bool) {
+	mid := new(big.Int).Add(bid, ask)
+	if mid.Sign() <= 0 {
+		return 0, false
+	}
+	spread := new(big.Int).Sub(ask, bid)
+	spread.Mul(spread, big.NewInt(20000)) // 10000 bps over (bid + ask) / 2
+	return spread.Quo(spread, mid).Int64(), true
+}
+
+func statsPairKey(prefix []byte, base, quote common.Address) []byte {
+	return append(append(append([]byte{}, prefix...), base.Bytes()...), quote.Bytes()...)
+}
+
+func statsPeriodKey(base, quote common.Address, t uint64) []byte {
+	return binary.BigEndian.AppendUint64(statsPairKey(statsPeriodPrefix, base, quote), t)
+}
+
+func statsRFQKey(prefix []byte, rfqTxHash common.Hash) []byte {
+	return append(append([]byte{}, prefix...), rfqTxHash.Bytes()...)
+}
+
+func (bc *Blockchain) readPairStats(key []byte, base, quote common.Address, t uint64) (*PairStats, error) {
+	data, err := bc.marketStatsTable.Get(key)
+	if err != nil || len(data) == 0 {
+		return newPairStats(base, quote, t), nil
+	}
+	stats := new(PairStats)
+	if err := rlp.DecodeBytes(data, stats); err != nil {
+		return nil, fmt.Errorf("error decoding pair stats: %w", err)
+	}
+	return stats, nil
+}
+
+// updatePairStats applies update to the totals of a pair and to the period
+// starting at t, and sets the marker key in the same batch.
+func (bc *Blockchain) updatePairStats(base, quote common.Address, t uint64, update func(*PairStats), marker, value []byte) error {
+	batch := bc.marketStatsTable.NewBatch()
+	for _, agg := range []struct {
+		key  []byte
+		time uint64
+	}{
+		{statsPairKey(statsTotalPrefix, base, quote), 0},
+		{statsPeriodKey(base, quote, t), t},
+	} {
+		stats, err := bc.readPairStats(agg.key, base, quote, agg.time)
+		if err != nil {
+			return err
+		}
+		update(stats)
+		enc, err := rlp.EncodeToBytes(stats)
+		if err != nil {
+			return fmt.Errorf("error encoding pair stats: %s", err.Error())
+		}
+		if err := batch.Put(agg.key, enc); err != nil {
+			return err
+		}
+	}
+	if err := batch.Put(marker, value); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("error writing market stats: %s", err.Error())
+	}
+	return nil
+}
+
+// recordQuoteLatency notes how long after its RFQ opened a quote arrived,
+// the latency is counted once the auction closes.
+func (bc *Blockchain) recordQuoteLatency(rfqTxHash common.Hash, at uint64) error {
+	openRFQ, ok := bc.openRFQsMap[rfqTxHash]
+	if !ok || openRFQ.Data == nil || openRFQ.Data.IsPrivate() || at < uint64(openRFQ.Data.RFQStartTime) {
+		return nil
+	}
+	key := statsRFQKey(statsLatencyPrefix, rfqTxHash)
+	var latencies []uint64
+	if data, err := bc.marketStatsTable.Get(key); err == nil && len(data) > 0 {
+		if err := rlp.DecodeBytes(data, &latencies); err != nil {
+			return fmt.Errorf("error decoding quote latencies: %w", err)
+		}
+	}
+	enc, err := rlp.EncodeToBytes(append(latencies, at-uint64(openRFQ.Data.RFQStartTime)))
+	if err != nil {
+		return fmt.Errorf("error encoding quote latencies: %s", err.Error())
+	}
+	return bc.marketStatsTable.Put(key, enc)
+}
+
+// recordClosedRFQ counts a closed public auction in the stats of its pair,
+// for the period it ended in. Auctions are only counted once.
+func (bc *Blockchain) recordClosedRFQ(data *types.RFQData) error {
+	request := data.RFQRequest
+	if data.IsPrivate() || request == nil || request.BaseToken == nil || request.QuoteToken == nil {
+		return nil
+	}
+	marker := statsRFQKey(statsClosedPrefix, data.RFQTxHash)
+	if counted, _ := bc.marketStatsTable.Has(marker); counted {
+		return nil
+	}
+
+	var latencies []uint64
+	latencyKey := statsRFQKey(statsLatencyPrefix, data.RFQTxHash)
+	if enc, err := bc.marketStatsTable.Get(latencyKey); err == nil && len(enc) > 0 {
+		if err := rlp.DecodeBytes(enc, &latencies); err != nil {
+			return fmt.Errorf("error decoding quote latencies: %w", err)
+		}
+	}
+
+	base, quote := request.BaseToken.Address, request.QuoteToken.Address
+	period := uint64(data.RFQEndTime) / StatsPeriodMs * StatsPeriodMs
+	amount := request.BaseTokenAmount
+	if amount == nil {
+		amount = new(big.Int)
+	}
+	err := bc.updatePairStats(base, quote, period, func(stats *PairStats) {
+		stats.RFQs++
+		stats.RequestedVolume.Add(stats.RequestedVolume, amount)
+		if len(data.Quotes) > 0 {
+			stats.QuotedRFQs++
+			stats.Quotes += uint64(len(data.Quotes))
+			stats.QuotedVolume.Add(stats.QuotedVolume, amount)
+		}
+		for _, ms := range latencies {
+			stats.addLatency(ms)
+		}
+	}, marker, binary.BigEndian.AppendUint64(statsPairKey(nil, base, quote), period))
+	if err != nil {
+		return err
+	}
+	return bc.marketStatsTable.Delete(latencyKey)
+}
+
+// recordMatchResult counts the spread between the best bid and ask of a
+// counted auction. One-sided auctions have no spread.
+func (bc *Blockchain) recordMatchResult(result *types.MatchResult) error {
+	if result.BestBid == nil || result.BestAsk == nil || result.BestBid.Price == nil || result.BestAsk.Price == nil {
+		return nil
+	}
+	bps, ok := spreadBps(result.BestBid.Price, result.BestAsk.Price)
+	if !ok {
+		return nil
+	}
+	closed, err := bc.marketStatsTable.Get(statsRFQKey(statsClosedPrefix, result.RFQTxHash))
+	if err != nil || len(closed) != 2*common.AddressLength+8 {
+		// private or not closed on this node
+		return nil
+	}
+	marker := statsRFQKey(statsMatchedPrefix, result.RFQTxHash)
+	if counted, _ := bc.marketStatsTable.Has(marker); counted {
+		return nil
+	}
+	base := common.BytesToAddress(closed[:common.AddressLength])
+	quote := common.BytesToAddress(closed[common.AddressLength : 2*common.AddressLength])
+	period := binary.BigEndian.Uint64(closed[2*common.AddressLength:])
+	return bc.updatePairStats(base, quote, period, func(stats *PairStats) {
+		stats.addSpread(bps)
+	}, marker, []byte{1})
+}
+
+// rebuildMarketStats aggregates the closed auctions of a database written
+// before market data was kept. Quote latencies were not recorded for them.
+func (bc *Blockchain) rebuildMarketStats() error {
+	it := bc.marketStatsTable.NewIterator(statsClosedPrefix, nil)
+	counted := it.Next()
+	it.Release()
+	if counted {
+		return nil
+	}
+
+	closedRFQs, err := bc.GetClosedRFQRequests()
+	if err != nil {
+		return err
+	}
+	for _, closedRFQ := range closedRFQs {
+		if closedRFQ.Data == nil {
+			continue
+		}
+		if err := bc.recordClosedRFQ(closedRFQ.Data); err != nil {
+			return err
+		}
+		data, err := bc.matchedRFQSTable.Get(closedRFQ.Data.RFQTxHash.Bytes())
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		result := new(types.MatchResult)
+		if err := rlp.DecodeBytes(data, result); err != nil {
+			return fmt.Errorf("error decoding match result: %w", err)
+		}
+		if err := bc.recordMatchResult(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMarketStats returns the totals of every pair with closed auctions.
+func (bc *Blockchain) GetMarketStats() ([]*PairStats, error) {
+	it := bc.marketStatsTable.NewIterator(statsTotalPrefix, nil)
+	defer it.Release()
+
+	var pairs []*PairStats
+	for it.Next() {
+		stats := new(PairStats)
+		if err := rlp.DecodeBytes(it.Value(), stats); err != nil {
+			return nil, fmt.Errorf("error decoding pair stats: %w", err)
+		}
+		pairs = append(pairs, stats)
+	}
+	return pairs, it.Error()
+}
+
+// GetPairStats returns the totals of a pair, empty if none of its auctions
+// have closed.
+func (bc *Blockchain) GetPairStats(base, quote common.Address) (*PairStats, error) {
+	return bc.readPairStats(statsPairKey(statsTotalPrefix, base, quote), base, quote, 0)
+}
+
+// GetPairStatsHistory returns the stats of a pair over the intervals, in
+// milliseconds, that overlap the time range from to to. Intervals start at
+// multiples of their length and those without closed auctions are left out.
+func (bc *Blockchain) GetPairStatsHistory(base, quote common.Address, from, to, interval uint64) ([]*PairStats, error) {
+	if interval == 0 || interval%StatsPeriodMs != 0 {
+		return nil, ErrInvalidInterval
+	}
+	prefix := statsPairKey(statsPeriodPrefix, base, quote)
+	it := bc.marketStatsTable.NewIterator(prefix, binary.BigEndian.AppendUint64(nil, from/interval*interval))
+	defer it.Release()
+
+	var history []*PairStats
+	for it.Next() {
+		period := new(PairStats)
+		if err := rlp.DecodeBytes(it.Value(), period); err != nil {
+			return nil, fmt.Errorf("error decoding pair stats: %w", err)
+		}
+		if to != 0 && period.Time > to {
+			break
+		}
+		start := period.Time / interval * interval
+		if len(history) == 0 || history[len(history)-1].Time != start {
+			history = append(history, newPairStats(base, quote, start))
+		}
+		history[len(history)-1].merge(period)
+	}
+	return history, it.Error()
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketStats(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"marketstats")
+	defer teardown()
+
+	requestor := cryptoocax.GeneratePrivateKey()
+	quoter := cryptoocax.GeneratePrivateKey()
+	start := time.Now().UnixMilli() - 2000
+
+	// a public RFQ that receives two quotes about 2s after opening, and a
+	// private one that isn't counted
+	request := randomTxWithSignature(t, requestor)
+	private := randomTxWithSignature(t, requestor)
+	for _, tx := range []*types.Transaction{request, private} {
+		require.NoError(t, bc.WriteRFQTxs(tx))
+		open := openRFQTx(tx, start, types.RFQStatusOpen)
+		if tx == private {
+			open.EmbeddedData().(*types.RFQData).Recipients = []common.Address{quoter.PublicKey().Address()}
+		}
+		require.NoError(t, bc.WriteRFQTxs(open))
+	}
+
+	data := request.EmbeddedData().(*types.SignableData)
+	var quotes []*types.Quote
+	for _, bid := range []int64{98, 99} {
+		quote := types.NewQuote(quoter.PublicKey().Address(), &types.QuoteData{
+			RFQTxHash:       request.Hash(),
+			BaseToken:       data.BaseToken,
+			QuoteToken:      data.QuoteToken,
+			BaseTokenAmount: data.BaseTokenAmount,
+			BidPrice:        big.NewInt(bid),
+			AskPrice:        big.NewInt(101),
+		})
+		require.NoError(t, bc.WriteRFQTxs(types.NewTx(quote)))
+		quotes = append(quotes, quote)
+	}
+
+	closed := openRFQTx(request, start, types.RFQStatusClosed)
+	closed.EmbeddedData().(*types.RFQData).Quotes = quotes
+	// an auction is only counted once
+	require.NoError(t, bc.WriteRFQTxs(closed))
+	require.NoError(t, bc.WriteRFQTxs(closed))
+	closedPrivate := openRFQTx(private, start, types.RFQStatusClosed)
+	closedPrivate.EmbeddedData().(*types.RFQData).Recipients = []common.Address{quoter.PublicKey().Address()}
+	require.NoError(t, bc.WriteRFQTxs(closedPrivate))
+
+	// spread of 99/101 is 200bps of the mid price
+	result := &types.MatchResult{
+		RFQTxHash: request.Hash(),
+		BestBid:   &types.MatchedQuote{Price: big.NewInt(99)},
+		BestAsk:   &types.MatchedQuote{Price: big.NewInt(101)},
+	}
+	require.NoError(t, bc.WriteMatchResult(result))
+	require.NoError(t, bc.WriteMatchResult(result))
+
+	base, quote := data.BaseToken.Address, data.QuoteToken.Address
+	stats, err := bc.GetPairStats(base, quote)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), stats.RFQs)
+	assert.Equal(t, uint64(1), stats.QuotedRFQs)
+	assert.Equal(t, uint64(2), stats.Quotes)
+	assert.Equal(t, data.BaseTokenAmount.String(), stats.RequestedVolume.String())
+	assert.Equal(t, data.BaseTokenAmount.String(), stats.QuotedVolume.String())
+	assert.Equal(t, []uint64{0, 0, 0, 0, 0, 0, 0, 1, 0}, stats.Spreads)
+	assert.Equal(t, uint64(2), stats.QuoteLatencies())
+	assert.GreaterOrEqual(t, stats.LatencyMinMs, uint64(2000))
+	assert.GreaterOrEqual(t, stats.LatencyMaxMs, stats.LatencyMinMs)
+
+	pairs, err := bc.GetMarketStats()
+	require.NoError(t, err)
+	assert.Len(t, pairs, 1)
+
+	// the auction is counted in the hour it ended, merged into a day
+	day := uint64(24 * time.Hour / time.Millisecond)
+	history, err := bc.GetPairStatsHistory(base, quote, 0, 0, day)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, uint64(start+5000)/day*day, history[0].Time)
+	assert.Equal(t, uint64(2), history[0].Quotes)
+
+	history, err = bc.GetPairStatsHistory(base, quote, uint64(start+5000)+StatsPeriodMs, 0, StatsPeriodMs)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	_, err = bc.GetPairStatsHistory(base, quote, 0, 0, StatsPeriodMs/2)
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+}
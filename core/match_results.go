@@ -0,0 +1,42 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// WriteMatchResult stores the signed outcome of matching a closed auction,
+// keyed by the hash of the RFQ request that started it.
+func (bc *Blockchain) WriteMatchResult(result *types.MatchResult) error {
+	enc, err := rlp.EncodeToBytes(result)
+	if err != nil {
+		return fmt.Errorf("error encoding match result: %s", err.Error())
+	}
+
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	if err := bc.matchedRFQSTable.Put(result.RFQTxHash.Bytes(), enc); err != nil {
+		return fmt.Errorf("error writing match result to kv store tables: %s", err.Error())
+	}
+	return bc.recordMatchResult(result)
+}
+
+// GetMatchResult returns the recorded match result of an auction.
+func (bc *Blockchain) GetMatchResult(rfqTxHash common.Hash) (*types.MatchResult, error) {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	data, err := bc.matchedRFQSTable.Get(rfqTxHash.Bytes())
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("match result for RFQ [%x] not found", rfqTxHash)
+	}
+	result := new(types.MatchResult)
+	if err := rlp.DecodeBytes(data, result); err != nil {
+		return nil, fmt.Errorf("error decoding match result: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,60 @@
+package core
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+// WriteMempool replaces the saved mempool with txs, keyed by their position
+// so they are read back in the order they were added.
+func (bc *Blockchain) WriteMempool(txs []*types.Transaction) error {
+	it := bc.mempoolTable.NewIterator(nil, nil)
+	var keys [][]byte
+	for it.Next() {
+		keys = append(keys, append([]byte(nil), it.Key()...))
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	batch := bc.mempoolTable.NewBatch()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	for i, tx := range txs {
+		enc, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		key := make([]byte, 8)
+		binary.BigEndian.PutUint64(key, uint64(i))
+		if err := batch.Put(key, enc); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("error writing mempool to kv store tables: %s", err.Error())
+	}
+	return nil
+}
+
+// ReadMempool returns the saved mempool.
+func (bc *Blockchain) ReadMempool() ([]*types.Transaction, error) {
+	it := bc.mempoolTable.NewIterator(nil, nil)
+	defer it.Release()
+
+	var txs []*types.Transaction
+	for it.Next() {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(it.Value()); err != nil {
+			return nil, fmt.Errorf("error decoding mempool transaction: %w", err)
+		}
+		txs = append(txs, tx)
+	}
+	return txs, it.Error()
+}
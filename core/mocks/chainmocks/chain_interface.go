@@ -17,6 +17,20 @@ type ChainInterface struct {
 	mock.Mock
 }
 
+// CheckParticipant provides a mock function with given fields: addr, role, amount, at
+func (_m *ChainInterface) CheckParticipant(addr common.Address, role types.ParticipantRole, amount *big.Int, at uint64) error {
+	ret := _m.Called(addr, role, amount, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(common.Address, types.ParticipantRole, *big.Int, uint64) error); ok {
+		r0 = rf(addr, role, amount, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAuctionQuotes provides a mock function with given fields: rfqTxhash
 func (_m *ChainInterface) GetAuctionQuotes(rfqTxhash common.Hash) ([]*types.Quote, error) {
 	ret := _m.Called(rfqTxhash)
@@ -121,6 +135,32 @@ func (_m *ChainInterface) GetBlockHeader(height *big.Int) (*types.Header, error)
 	return r0, r1
 }
 
+// GetClosedRFQByHash provides a mock function with given fields: hash
+func (_m *ChainInterface) GetClosedRFQByHash(hash common.Hash) (*types.OpenRFQ, error) {
+	ret := _m.Called(hash)
+
+	var r0 *types.OpenRFQ
+	var r1 error
+	if rf, ok := ret.Get(0).(func(common.Hash) (*types.OpenRFQ, error)); ok {
+		return rf(hash)
+	}
+	if rf, ok := ret.Get(0).(func(common.Hash) *types.OpenRFQ); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.OpenRFQ)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetClosedRFQRequests provides a mock function with given fields:
 func (_m *ChainInterface) GetClosedRFQRequests() ([]*types.OpenRFQ, error) {
 	ret := _m.Called()
@@ -147,6 +187,41 @@ func (_m *ChainInterface) GetClosedRFQRequests() ([]*types.OpenRFQ, error) {
 	return r0, r1
 }
 
+// GetIncludedTx provides a mock function with given fields: hash
+func (_m *ChainInterface) GetIncludedTx(hash common.Hash) (*types.Transaction, *types.TxInclusion, error) {
+	ret := _m.Called(hash)
+
+	var r0 *types.Transaction
+	var r1 *types.TxInclusion
+	var r2 error
+	if rf, ok := ret.Get(0).(func(common.Hash) (*types.Transaction, *types.TxInclusion, error)); ok {
+		return rf(hash)
+	}
+	if rf, ok := ret.Get(0).(func(common.Hash) *types.Transaction); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(common.Hash) *types.TxInclusion); ok {
+		r1 = rf(hash)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*types.TxInclusion)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(common.Hash) error); ok {
+		r2 = rf(hash)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetOpenRFQByHash provides a mock function with given fields: hash
 func (_m *ChainInterface) GetOpenRFQByHash(hash common.Hash) (*types.OpenRFQ, error) {
 	ret := _m.Called(hash)
@@ -199,6 +274,84 @@ func (_m *ChainInterface) GetOpenRFQRequests() ([]*types.OpenRFQ, error) {
 	return r0, r1
 }
 
+// GetParticipant provides a mock function with given fields: addr
+func (_m *ChainInterface) GetParticipant(addr common.Address) (*types.Participant, error) {
+	ret := _m.Called(addr)
+
+	var r0 *types.Participant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(common.Address) (*types.Participant, error)); ok {
+		return rf(addr)
+	}
+	if rf, ok := ret.Get(0).(func(common.Address) *types.Participant); ok {
+		r0 = rf(addr)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Participant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(common.Address) error); ok {
+		r1 = rf(addr)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetParticipants provides a mock function with given fields:
+func (_m *ChainInterface) GetParticipants() ([]*types.Participant, error) {
+	ret := _m.Called()
+
+	var r0 []*types.Participant
+	var r1 error
+	if rf, ok := ret.Get(0).(func() ([]*types.Participant, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() []*types.Participant); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Participant)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRFQRequestByHash provides a mock function with given fields: hash
+func (_m *ChainInterface) GetRFQRequestByHash(hash common.Hash) (*types.RFQRequest, error) {
+	ret := _m.Called(hash)
+
+	var r0 *types.RFQRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(common.Hash) (*types.RFQRequest, error)); ok {
+		return rf(hash)
+	}
+	if rf, ok := ret.Get(0).(func(common.Hash) *types.RFQRequest); ok {
+		r0 = rf(hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.RFQRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(common.Hash) error); ok {
+		r1 = rf(hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRFQRequests provides a mock function with given fields:
 func (_m *ChainInterface) GetRFQRequests() ([]*types.RFQRequest, error) {
 	ret := _m.Called()
@@ -265,6 +418,20 @@ func (_m *ChainInterface) UpdateActiveRFQ(rfqTxHash common.Hash, quote *types.Qu
 	return r0
 }
 
+// WriteParticipant provides a mock function with given fields: p
+func (_m *ChainInterface) WriteParticipant(p *types.Participant) error {
+	ret := _m.Called(p)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*types.Participant) error); ok {
+		r0 = rf(p)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // WriteRFQTxs provides a mock function with given fields: tx
 func (_m *ChainInterface) WriteRFQTxs(tx *types.Transaction) error {
 	ret := _m.Called(tx)
@@ -0,0 +1,130 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	ErrParticipantNotFound = errors.New("participant is not whitelisted")
+	ErrNotRegistryAdmin    = errors.New("participant record is not signed by the registry admin")
+	ErrStaleParticipant    = errors.New("participant record is older than the stored record")
+)
+
+// SetParticipantRegistry configures the admin allowed to sign participant
+// records and whether transactions must come from whitelisted participants.
+func (bc *Blockchain) SetParticipantRegistry(admin common.Address, enforce bool) {
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	bc.participantAdmin = admin
+	bc.enforceWhitelist = enforce
+}
+
+// WriteParticipant stores an admin signed participant record, replacing any
+// earlier record for the same address.
+func (bc *Blockchain) WriteParticipant(p *types.Participant) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	signer, err := p.Signer()
+	if err != nil {
+		return err
+	}
+
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	if bc.participantAdmin == (common.Address{}) || signer != bc.participantAdmin {
+		return ErrNotRegistryAdmin
+	}
+	if existing, err := bc.readParticipant(p.Address); err == nil && existing.UpdatedAt >= p.UpdatedAt {
+		return ErrStaleParticipant
+	}
+
+	enc, err := rlp.EncodeToBytes(p)
+	if err != nil {
+		return fmt.Errorf("error encoding participant: %s", err.Error())
+	}
+	if err := bc.participantsTable.Put(p.Address.Bytes(), enc); err != nil {
+		return fmt.Errorf("error writing participant to kv store tables: %s", err.Error())
+	}
+	return nil
+}
+
+func (bc *Blockchain) GetParticipant(addr common.Address) (*types.Participant, error) {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	return bc.readParticipant(addr)
+}
+
+func (bc *Blockchain) readParticipant(addr common.Address) (*types.Participant, error) {
+	data, err := bc.participantsTable.Get(addr.Bytes())
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrParticipantNotFound, addr.Hex())
+	}
+	p := new(types.Participant)
+	if err := rlp.DecodeBytes(data, p); err != nil {
+		return nil, fmt.Errorf("error decoding participant: %w", err)
+	}
+	return p, nil
+}
+
+func (bc *Blockchain) GetParticipants() ([]*types.Participant, error) {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	var participants []*types.Participant
+
+	it := bc.participantsTable.NewIterator(nil, nil)
+	defer it.Release()
+
+	for it.Next() {
+		p := new(types.Participant)
+		if err := rlp.DecodeBytes(it.Value(), p); err != nil {
+			return nil, fmt.Errorf("error decoding participant: %w", err)
+		}
+		participants = append(participants, p)
+	}
+	return participants, it.Error()
+}
+
+// CheckParticipant reports whether addr is onboarded to act in role for the
+// given base token amount at the unix time in milliseconds. It always passes
+// when whitelist enforcement is disabled.
+func (bc *Blockchain) CheckParticipant(addr common.Address, role types.ParticipantRole, amount *big.Int, at uint64) error {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	if !bc.enforceWhitelist {
+		return nil
+	}
+	p, err := bc.readParticipant(addr)
+	if err != nil {
+		return err
+	}
+	if err := p.CheckRole(role, at); err != nil {
+		return err
+	}
+	return p.CheckAmount(amount)
+}
+
+// VerifyTxParticipant checks that client submitted transactions (RFQ requests
+// and quotes) come from whitelisted participants. Transactions created by
+// validators are not checked. It is checked as transactions are admitted,
+// not when blocks holding them are verified.
+func (bc *Blockchain) VerifyTxParticipant(tx *types.Transaction, at uint64) error {
+	switch tx.Type() {
+	case types.RFQRequestTxType:
+		return bc.CheckParticipant(*tx.From(), types.RoleRequestor, tx.EmbeddedData().(*types.SignableData).BaseTokenAmount, at)
+	case types.QuoteTxType:
+		return bc.CheckParticipant(*tx.From(), types.RoleMarketMaker, tx.EmbeddedData().(*types.QuoteData).BaseTokenAmount, at)
+	}
+	return nil
+}
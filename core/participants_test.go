@@ -0,0 +1,96 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParticipantRegistry(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"participants")
+	defer teardown()
+
+	admin := cryptoocax.GeneratePrivateKey()
+	bc.SetParticipantRegistry(admin.PublicKey().Address(), true)
+
+	mm := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	amount := big.NewInt(1000)
+	assert.ErrorIs(t, bc.CheckParticipant(mm, types.RoleMarketMaker, amount, 1), ErrParticipantNotFound)
+
+	p := &types.Participant{
+		Address:   mm,
+		Role:      types.RoleMarketMaker,
+		Status:    types.ParticipantActive,
+		Limits:    types.ParticipantLimits{MaxBaseTokenAmount: big.NewInt(5000)},
+		UpdatedAt: 1,
+	}
+
+	// records not signed by the admin are rejected
+	require.NoError(t, p.Sign(cryptoocax.GeneratePrivateKey()))
+	assert.ErrorIs(t, bc.WriteParticipant(p), ErrNotRegistryAdmin)
+
+	require.NoError(t, p.Sign(admin))
+	require.NoError(t, bc.WriteParticipant(p))
+	assert.ErrorIs(t, bc.WriteParticipant(p), ErrStaleParticipant)
+
+	assert.NoError(t, bc.CheckParticipant(mm, types.RoleMarketMaker, amount, 1))
+	assert.ErrorIs(t, bc.CheckParticipant(mm, types.RoleRequestor, amount, 1), types.ErrParticipantRole)
+	assert.ErrorIs(t, bc.CheckParticipant(mm, types.RoleMarketMaker, big.NewInt(6000), 1), types.ErrParticipantLimit)
+
+	suspended := *p
+	suspended.Status = types.ParticipantSuspended
+	suspended.UpdatedAt = 2
+	require.NoError(t, suspended.Sign(admin))
+	require.NoError(t, bc.WriteParticipant(&suspended))
+	assert.ErrorIs(t, bc.CheckParticipant(mm, types.RoleMarketMaker, amount, 1), types.ErrParticipantInactive)
+
+	participants, err := bc.GetParticipants()
+	require.NoError(t, err)
+	require.Len(t, participants, 1)
+	assert.Equal(t, types.ParticipantSuspended, participants[0].Status)
+
+	bc.SetParticipantRegistry(admin.PublicKey().Address(), false)
+	assert.NoError(t, bc.CheckParticipant(mm, types.RoleMarketMaker, amount, 1))
+
+	// risk limits are stored with the record and covered by its signature
+	requestor := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	pair := types.PairLimit{
+		BaseToken:     common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"),
+		QuoteToken:    common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		MaxOpenAmount: big.NewInt(300),
+	}
+	limited := &types.Participant{
+		Address: requestor,
+		Role:    types.RoleRequestor,
+		Status:  types.ParticipantActive,
+		Limits: types.ParticipantLimits{
+			MaxDailyAmount: big.NewInt(2000),
+			Pairs:          []types.PairLimit{pair},
+		},
+		UpdatedAt: 1,
+	}
+	require.NoError(t, limited.Sign(admin))
+	require.NoError(t, bc.WriteParticipant(limited))
+	stored, err := bc.GetParticipant(requestor)
+	require.NoError(t, err)
+	assert.Equal(t, limited.Hash(), stored.Hash())
+	assert.Equal(t, big.NewInt(2000), stored.Limits.MaxDailyAmount)
+	assert.Equal(t, pair.MaxOpenAmount, stored.Limits.PairLimit(pair.BaseToken, pair.QuoteToken))
+}
+
+func TestVerifyBlockIgnoresRegistry(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"participantsblock")
+	defer teardown()
+	bc.SetParticipantRegistry(cryptoocax.GeneratePrivateKey().PublicKey().Address(), true)
+
+	// the senders were checked when their transactions were admitted, a
+	// block holding them is accepted whatever the registry holds today
+	block := randomBlockWithSignature(t, testKey, 1, getPrevBlockHash(t, bc, big.NewInt(0)))
+	assert.ErrorIs(t, bc.VerifyTxParticipant(block.Transactions()[0], 1), ErrParticipantNotFound)
+	assert.NoError(t, bc.VerifyBlock(block))
+}
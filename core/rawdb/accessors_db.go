@@ -2,8 +2,8 @@ package rawdb
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
-	"fmt"
 
 	"github.com/OCAX-labs/rfqrelayer/common"
 	"github.com/OCAX-labs/rfqrelayer/core/types"
@@ -18,6 +18,9 @@ var logger log.Logger
 func WriteBlock(db db.KeyValueWriter, block *types.Block) {
 	WriteBody(db, block.Header().Hash(), block.HeightU64(), block.Body())
 	WriteHeader(db, block.Header())
+	// blocks are also looked up by the block hash reported by the API, which
+	// differs from the hash of the header they are stored under
+	WriteHeaderNumber(db, block.Hash(), block.HeightU64())
 }
 
 // WriteBody stores a block body into the database.
@@ -83,6 +86,34 @@ func WriteHeaderNumber(db db.KeyValueWriter, hash common.Hash, number uint64) {
 	}
 }
 
+// ReadHeaderNumber returns the height of the block with the given header or
+// block hash, or nil if it is not stored.
+func ReadHeaderNumber(db db.KeyValueReader, hash common.Hash) *uint64 {
+	data, _ := db.Get(headerHeightKey(hash))
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteCanonicalHash stores the header hash of the chain's block at height.
+func WriteCanonicalHash(db db.KeyValueWriter, hash common.Hash, height uint64) {
+	if err := db.Put(headerHashKey(height), hash.Bytes()); err != nil {
+		level.Error(logger).Log("message", "Failed to store number to hash mapping", "err", err)
+	}
+}
+
+// ReadCanonicalHash returns the header hash of the chain's block at height,
+// or the zero hash if there is none.
+func ReadCanonicalHash(db db.KeyValueReader, height uint64) common.Hash {
+	data, _ := db.Get(headerHashKey(height))
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
 func ReadTransaction(db db.KeyValueReader, hash common.Hash) (*types.Transaction, error) {
 	data, _ := db.Get(transactionKey(hash))
 	if len(data) == 0 {
@@ -140,7 +171,6 @@ func ReadBody(db db.Reader, hash common.Hash, number uint64) *types.Body {
 	}
 	body := new(types.Body)
 	if err := rlp.Decode(bytes.NewReader(data), body); err != nil {
-		fmt.Errorf("Failed to decode block body: %v", err)
 		return nil
 	}
 	return body
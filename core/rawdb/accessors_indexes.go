@@ -0,0 +1,84 @@
+package rawdb
+
+import (
+	"fmt"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	db "github.com/OCAX-labs/rfqrelayer/rfqdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TxLookupEntry locates a transaction included in a block. HeaderHash is
+// the hash the block is stored under.
+type TxLookupEntry struct {
+	HeaderHash  common.Hash
+	BlockHeight uint64
+	Index       uint64
+}
+
+// WriteTxLookupEntries indexes every transaction of a block by its hash.
+func WriteTxLookupEntries(db db.KeyValueWriter, block *types.Block) error {
+	entry := TxLookupEntry{HeaderHash: block.Header().Hash(), BlockHeight: block.HeightU64()}
+	for i, tx := range block.Transactions() {
+		entry.Index = uint64(i)
+		data, err := rlp.EncodeToBytes(&entry)
+		if err != nil {
+			return err
+		}
+		if err := db.Put(txLookupKey(tx.Hash()), data); err != nil {
+			return fmt.Errorf("failed to store transaction lookup entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReadTxLookupEntry returns where the transaction with the given hash was
+// included, or nil if it is not in any block.
+func ReadTxLookupEntry(db db.KeyValueReader, hash common.Hash) *TxLookupEntry {
+	data, _ := db.Get(txLookupKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	entry := new(TxLookupEntry)
+	if err := rlp.DecodeBytes(data, entry); err != nil {
+		return nil
+	}
+	return entry
+}
+
+// BackfillTxLookupEntries indexes the transactions of every stored block,
+// once per database. It returns the number of blocks indexed.
+func BackfillTxLookupEntries(db db.Database) (int, error) {
+	if done, _ := db.Has(txIndexBackfilledKey); done {
+		return 0, nil
+	}
+
+	it := db.NewIterator(headerPrefix, nil)
+	defer it.Release()
+
+	blocks := 0
+	for it.Next() {
+		// skip any key that is not a header key: headerPrefix + num + hash
+		key := it.Key()
+		if len(key) != len(headerPrefix)+8+common.HashLength {
+			continue
+		}
+		header := new(types.Header)
+		if err := rlp.DecodeBytes(it.Value(), header); err != nil {
+			return blocks, fmt.Errorf("invalid block header RLP: %w", err)
+		}
+		block := ReadBlock(db, header.Hash(), header.Height.Uint64())
+		if block == nil {
+			continue
+		}
+		if err := WriteTxLookupEntries(db, block); err != nil {
+			return blocks, err
+		}
+		blocks++
+	}
+	if err := it.Error(); err != nil {
+		return blocks, err
+	}
+	return blocks, db.Put(txIndexBackfilledKey, []byte{1})
+}
@@ -0,0 +1,71 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/rfqdb/memorydb"
+)
+
+func testBlock(t *testing.T, height int64, txs int) *types.Block {
+	key := cryptoocax.GeneratePrivateKey()
+	var transactions types.Transactions
+	for i := 0; i < txs; i++ {
+		tx, err := types.NewTx(&types.RFQRequest{
+			From: key.PublicKey().Address(),
+			Data: &types.SignableData{
+				RequestorId:     "1",
+				BaseTokenAmount: big.NewInt(height*10 + int64(i)),
+				BaseToken:       &types.BaseToken{Symbol: "MKR", Decimals: 18},
+				QuoteToken:      &types.QuoteToken{Symbol: "WETH", Decimals: 18},
+				RFQDurationMs:   5000,
+			},
+		}).Sign(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		transactions = append(transactions, tx)
+	}
+	header := &types.Header{Version: 1, Height: big.NewInt(height), Timestamp: uint64(height)}
+	return types.NewBlock(header, transactions, key.PublicKey())
+}
+
+func TestBackfillTxLookupEntries(t *testing.T) {
+	db := memorydb.New()
+
+	// blocks written before the transaction index existed
+	blocks := []*types.Block{testBlock(t, 1, 2), testBlock(t, 2, 1)}
+	for _, block := range blocks {
+		WriteBlock(db, block)
+	}
+	if entry := ReadTxLookupEntry(db, blocks[0].Transactions()[0].Hash()); entry != nil {
+		t.Fatalf("unexpected lookup entry %+v", entry)
+	}
+
+	indexed, err := BackfillTxLookupEntries(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indexed != 2 {
+		t.Fatalf("indexed %d blocks, want 2", indexed)
+	}
+	for _, block := range blocks {
+		for i, tx := range block.Transactions() {
+			want := TxLookupEntry{HeaderHash: block.Header().Hash(), BlockHeight: block.HeightU64(), Index: uint64(i)}
+			if entry := ReadTxLookupEntry(db, tx.Hash()); entry == nil || *entry != want {
+				t.Errorf("lookup entry of tx %d in block %d: got %+v, want %+v", i, block.HeightU64(), entry, want)
+			}
+		}
+	}
+	if entry := ReadTxLookupEntry(db, common.Hash{}); entry != nil {
+		t.Errorf("unexpected lookup entry %+v", entry)
+	}
+
+	// the backfill only runs once
+	if indexed, err = BackfillTxLookupEntries(db); err != nil || indexed != 0 {
+		t.Fatalf("second backfill indexed %d blocks: %v", indexed, err)
+	}
+}
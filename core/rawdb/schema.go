@@ -11,10 +11,41 @@ const (
 	TransactionPrefix = 'T' // prefix for keys storing transactions
 )
 
+// Table prefixes of the RFQ state kept alongside the chain, see NewTable.
+const (
+	RFQRequestsTable = "rfqRequests"
+	OpenRFQsTable    = "openRFQs"
+	ClosedRFQsTable  = "closeRFQs"
+	MatchedRFQsTable = "matchedRFQs"
+	SettledRFQsTable = "settledRFQs"
+	QuotesTable      = "quotes"
+
+	ParticipantsTable = "participants"
+	EventsTable       = "events"
+
+	// WebhooksTable holds the API's webhook registrations and
+	// WebhookDeadLettersTable the events they could not be delivered
+	WebhooksTable           = "webhooks"
+	WebhookDeadLettersTable = "webhookDeadLetters"
+
+	// RFQIndexTable holds the secondary indexes of the RFQ tables
+	RFQIndexTable = "rfqIndex"
+	// MarketStatsTable holds the market data aggregated from closed auctions
+	MarketStatsTable = "marketStats"
+	// MempoolTable holds the transactions pending when the node stopped
+	MempoolTable = "mempool"
+	// RiskUsageTable holds the RFQs counted against requestors' risk limits
+	RiskUsageTable = "riskUsage"
+)
+
 var (
 	// databaseVersionKey tracks the current database version.
 	databaseVersionKey = []byte("DatabaseVersion")
 
+	// txIndexBackfilledKey is set once the transactions of the blocks stored
+	// before the transaction index existed have been indexed.
+	txIndexBackfilledKey = []byte("TransactionIndexBackfilled")
+
 	// headHeaderKey tracks the latest known header's hash.
 	// headHeaderKey = []byte("LastHeader")
 
@@ -27,6 +58,7 @@ var (
 	headerHeightPrefix = []byte("H") // headerNumberPrefix + hash -> num (uint64 big endian)
 
 	blockBodyPrefix = []byte("b") // blockBodyPrefix + num (uint64 big endian) + hash -> block body
+	txLookupPrefix  = []byte("l") // txLookupPrefix + hash -> transaction lookup entry
 )
 
 // encodeBlockNumber encodes a block number as big endian uint64
@@ -65,6 +97,11 @@ func blockKey(hash common.Hash) []byte {
 	return append([]byte{BlockPrefix}, hash.Bytes()...)
 }
 
+// txLookupKey = txLookupPrefix + hash
+func txLookupKey(hash common.Hash) []byte {
+	return append(append([]byte{}, txLookupPrefix...), hash.Bytes()...)
+}
+
 func transactionKey(hash common.Hash) []byte {
 	return append([]byte{TransactionPrefix}, hash.Bytes()...)
 }
@@ -0,0 +1,400 @@
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// The RFQ index lets the RFQ tables be paged through, filtered and sorted by
+// iterating key ranges instead of decoding every record. Each RFQ, keyed by
+// the hash of its request, is listed under
+//
+//	index prefix + field value + order + time (uint64 big endian) + hash
+//
+// for every field it can be looked up by, in ascending and descending order
+// of time. Every key holds the RFQ's rfqIndexEntry so the remaining filters
+// are applied without reading the record itself. The entry is also stored
+// under rfqEntryPrefix + hash to find the keys to replace when it changes.
+var (
+	rfqIndexAll       = []byte("a") // all RFQs
+	rfqIndexStatus    = []byte("s") // status + 0x00
+	rfqIndexRequestor = []byte("r") // requestor address
+	rfqIndexBase      = []byte("b") // base token address
+	rfqIndexPair      = []byte("p") // base token address + quote token address
+	rfqEntryPrefix    = []byte("e") // hash -> rfqIndexEntry
+)
+
+const (
+	rfqOrderAsc  byte = 0
+	rfqOrderDesc byte = 1
+
+	// rfqCursorLength is the length of the order, time and hash that end an
+	// index key and make up a cursor
+	rfqCursorLength = 1 + 8 + common.HashLength
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidStatus = errors.New("invalid RFQ status")
+)
+
+// RFQQuery selects a page of RFQs. Zero fields do not filter.
+type RFQQuery struct {
+	Status     types.RFQStatus
+	Requestor  *common.Address
+	BaseToken  *common.Address
+	QuoteToken *common.Address
+	// From and To bound the time of the RFQ in unix milliseconds, its start
+	// time once opened and otherwise the time its request was received
+	From uint64
+	To   uint64
+
+	// Desc lists the newest RFQs first
+	Desc bool
+	// Cursor continues the listing after the last RFQ of a previous page
+	Cursor []byte
+	// Limit is the most RFQs returned, all are returned when it is 0
+	Limit int
+}
+
+type rfqIndexEntry struct {
+	Time       uint64
+	Status     types.RFQStatus
+	Requestor  common.Address
+	BaseToken  common.Address
+	QuoteToken common.Address
+}
+
+func (e *rfqIndexEntry) prefixes() [][]byte {
+	return [][]byte{
+		rfqIndexAll,
+		append(append(append([]byte{}, rfqIndexStatus...), e.Status...), 0),
+		append(append([]byte{}, rfqIndexRequestor...), e.Requestor.Bytes()...),
+		append(append([]byte{}, rfqIndexBase...), e.BaseToken.Bytes()...),
+		append(append(append([]byte{}, rfqIndexPair...), e.BaseToken.Bytes()...), e.QuoteToken.Bytes()...),
+	}
+}
+
+func (q *RFQQuery) order() byte {
+	if q.Desc {
+		return rfqOrderDesc
+	}
+	return rfqOrderAsc
+}
+
+// prefix selects the narrowest index that covers the query, in the order
+// it is listed.
+func (q *RFQQuery) prefix() []byte {
+	var prefix []byte
+	switch {
+	case q.Requestor != nil:
+		prefix = append(append(prefix, rfqIndexRequestor...), q.Requestor.Bytes()...)
+	case q.BaseToken != nil && q.QuoteToken != nil:
+		prefix = append(append(append(prefix, rfqIndexPair...), q.BaseToken.Bytes()...), q.QuoteToken.Bytes()...)
+	case q.BaseToken != nil:
+		prefix = append(append(prefix, rfqIndexBase...), q.BaseToken.Bytes()...)
+	case q.Status != "":
+		prefix = append(append(append(prefix, rfqIndexStatus...), q.Status...), 0)
+	default:
+		prefix = append(prefix, rfqIndexAll...)
+	}
+	return append(prefix, q.order())
+}
+
+// start is where iteration begins within the selected index, after the
+// cursor or at the near end of the time range.
+func (q *RFQQuery) start() ([]byte, error) {
+	var start []byte
+	if q.Desc {
+		if q.To != 0 {
+			start = binary.BigEndian.AppendUint64(start, ^q.To)
+		}
+	} else {
+		start = binary.BigEndian.AppendUint64(start, q.From)
+	}
+	if q.Cursor == nil {
+		return start, nil
+	}
+	if len(q.Cursor) != rfqCursorLength || q.Cursor[0] != q.order() {
+		return nil, ErrInvalidCursor
+	}
+	// keys have a fixed length so appending a zero byte gives the first key
+	// after the cursor
+	after := append(append([]byte{}, q.Cursor[1:]...), 0)
+	if bytes.Compare(after, start) > 0 {
+		return after, nil
+	}
+	return start, nil
+}
+
+// past reports whether an RFQ at time t is beyond the far end of the time
+// range, and so are all that follow it.
+func (q *RFQQuery) past(t uint64) bool {
+	if q.Desc {
+		return t < q.From
+	}
+	return q.To != 0 && t > q.To
+}
+
+func (q *RFQQuery) matches(e *rfqIndexEntry) bool {
+	return (q.Status == "" || e.Status == q.Status) &&
+		(q.Requestor == nil || e.Requestor == *q.Requestor) &&
+		(q.BaseToken == nil || e.BaseToken == *q.BaseToken) &&
+		(q.QuoteToken == nil || e.QuoteToken == *q.QuoteToken)
+}
+
+func rfqIndexKey(prefix []byte, order byte, t uint64, hash common.Hash) []byte {
+	if order == rfqOrderDesc {
+		t = ^t
+	}
+	key := append(append([]byte{}, prefix...), order)
+	key = binary.BigEndian.AppendUint64(key, t)
+	return append(key, hash.Bytes()...)
+}
+
+func (bc *Blockchain) readRFQIndexEntry(hash common.Hash) (*rfqIndexEntry, error) {
+	data, err := bc.rfqIndexTable.Get(append(append([]byte{}, rfqEntryPrefix...), hash.Bytes()...))
+	if err != nil || len(data) == 0 {
+		return nil, nil
+	}
+	entry := new(rfqIndexEntry)
+	if err := rlp.DecodeBytes(data, entry); err != nil {
+		return nil, fmt.Errorf("error decoding RFQ index entry: %w", err)
+	}
+	return entry, nil
+}
+
+// writeRFQIndex lists the RFQ with the given request hash under entry,
+// replacing the keys of its previous entry.
+func (bc *Blockchain) writeRFQIndex(hash common.Hash, entry *rfqIndexEntry) error {
+	old, err := bc.readRFQIndexEntry(hash)
+	if err != nil {
+		return err
+	}
+	enc, err := rlp.EncodeToBytes(entry)
+	if err != nil {
+		return fmt.Errorf("error encoding RFQ index entry: %s", err.Error())
+	}
+
+	batch := bc.rfqIndexTable.NewBatch()
+	if old != nil {
+		for _, prefix := range old.prefixes() {
+			for _, order := range []byte{rfqOrderAsc, rfqOrderDesc} {
+				if err := batch.Delete(rfqIndexKey(prefix, order, old.Time, hash)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	for _, prefix := range entry.prefixes() {
+		for _, order := range []byte{rfqOrderAsc, rfqOrderDesc} {
+			if err := batch.Put(rfqIndexKey(prefix, order, entry.Time, hash), enc); err != nil {
+				return err
+			}
+		}
+	}
+	if err := batch.Put(append(append([]byte{}, rfqEntryPrefix...), hash.Bytes()...), enc); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return fmt.Errorf("error writing RFQ index: %s", err.Error())
+	}
+	return nil
+}
+
+// indexRFQRequest lists a newly received RFQ request as requested, or adds
+// its requestor if a validator has already opened it.
+func (bc *Blockchain) indexRFQRequest(hash common.Hash, rfqRequest *types.RFQRequest, at uint64) error {
+	entry, err := bc.readRFQIndexEntry(hash)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		entry = &rfqIndexEntry{Time: at, Status: types.RFQStatusRequested}
+		setIndexTokens(entry, rfqRequest.Data)
+	} else if entry.Requestor != (common.Address{}) {
+		return nil
+	}
+	entry.Requestor = rfqRequest.From
+	return bc.writeRFQIndex(hash, entry)
+}
+
+// indexOpenRFQ lists an opened or closed RFQ under its start time and new
+// status. The requestor is only known when this node received the request.
+func (bc *Blockchain) indexOpenRFQ(data *types.RFQData) error {
+	existing, err := bc.readRFQIndexEntry(data.RFQTxHash)
+	if err != nil {
+		return err
+	}
+	entry := &rfqIndexEntry{Time: uint64(data.RFQStartTime), Status: data.Status}
+	if existing != nil {
+		entry.Requestor = existing.Requestor
+	}
+	setIndexTokens(entry, data.RFQRequest)
+	return bc.writeRFQIndex(data.RFQTxHash, entry)
+}
+
+func setIndexTokens(entry *rfqIndexEntry, data *types.SignableData) {
+	if data == nil {
+		return
+	}
+	if data.BaseToken != nil {
+		entry.BaseToken = data.BaseToken.Address
+	}
+	if data.QuoteToken != nil {
+		entry.QuoteToken = data.QuoteToken.Address
+	}
+}
+
+// queryRFQIndex walks the index selected by q and calls keep with the hash
+// of every matching RFQ until q.Limit of them are kept. It returns the
+// cursor of the last kept RFQ when another matching RFQ follows it.
+func (bc *Blockchain) queryRFQIndex(q *RFQQuery, keep func(hash common.Hash) (bool, error)) ([]byte, error) {
+	prefix := q.prefix()
+	start, err := q.start()
+	if err != nil {
+		return nil, err
+	}
+
+	it := bc.rfqIndexTable.NewIterator(prefix, start)
+	defer it.Release()
+
+	var (
+		kept   int
+		cursor []byte
+	)
+	for it.Next() {
+		entry := new(rfqIndexEntry)
+		if err := rlp.DecodeBytes(it.Value(), entry); err != nil {
+			return nil, fmt.Errorf("error decoding RFQ index entry: %w", err)
+		}
+		if q.past(entry.Time) {
+			break
+		}
+		if !q.matches(entry) {
+			continue
+		}
+		if q.Limit > 0 && kept == q.Limit {
+			return cursor, it.Error()
+		}
+		key := it.Key()
+		ok, err := keep(common.BytesToHash(key[len(key)-common.HashLength:]))
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			kept++
+			cursor = append([]byte{}, key[len(key)-rfqCursorLength:]...)
+		}
+	}
+	return nil, it.Error()
+}
+
+// QueryRFQRequests returns a page of RFQ requests selected by q, skipping
+// those keep rejects, and the cursor of the next page if there is one. The
+// status of a request is that of the RFQ it started.
+func (bc *Blockchain) QueryRFQRequests(q RFQQuery, keep func(*types.RFQRequest) bool) ([]*types.RFQRequest, []byte, error) {
+	var rfqRequests []*types.RFQRequest
+	next, err := bc.queryRFQIndex(&q, func(hash common.Hash) (bool, error) {
+		data, err := bc.rfqRequestsTable.Get(hash.Bytes())
+		if err != nil || len(data) == 0 {
+			// RFQs opened by other validators are listed without their request
+			return false, nil
+		}
+		rfqRequest := new(types.RFQRequest)
+		if err := rlp.DecodeBytes(data, rfqRequest); err != nil {
+			return false, fmt.Errorf("error decoding RFQRequest: %w", err)
+		}
+		if keep != nil && !keep(rfqRequest) {
+			return false, nil
+		}
+		rfqRequests = append(rfqRequests, rfqRequest)
+		return true, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return rfqRequests, next, nil
+}
+
+// QueryOpenRFQs returns a page of the open or closed RFQs selected by q,
+// whose status must be RFQStatusOpen or RFQStatusClosed.
+func (bc *Blockchain) QueryOpenRFQs(q RFQQuery, keep func(*types.OpenRFQ) bool) ([]*types.OpenRFQ, []byte, error) {
+	table := bc.openRFQSTable
+	switch q.Status {
+	case types.RFQStatusOpen:
+	case types.RFQStatusClosed:
+		table = bc.closedRFQSTable
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidStatus, q.Status)
+	}
+
+	var openRFQs []*types.OpenRFQ
+	next, err := bc.queryRFQIndex(&q, func(hash common.Hash) (bool, error) {
+		data, err := table.Get(hash.Bytes())
+		if err != nil || len(data) == 0 {
+			return false, nil
+		}
+		openRFQ := new(types.OpenRFQ)
+		if err := rlp.DecodeBytes(data, openRFQ); err != nil {
+			return false, fmt.Errorf("error decoding OpenRFQ: %w", err)
+		}
+		if keep != nil && !keep(openRFQ) {
+			return false, nil
+		}
+		openRFQs = append(openRFQs, openRFQ)
+		return true, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return openRFQs, next, nil
+}
+
+// reindexRFQs builds the RFQ index from the RFQ tables of a database
+// written before it existed. Requests are listed at time 0 until opened.
+func (bc *Blockchain) reindexRFQs() error {
+	it := bc.rfqIndexTable.NewIterator(rfqEntryPrefix, nil)
+	indexed := it.Next()
+	it.Release()
+	if indexed {
+		return nil
+	}
+
+	it = bc.rfqRequestsTable.NewIterator(nil, nil)
+	for it.Next() {
+		var rfqRequest types.RFQRequest
+		if err := rlp.DecodeBytes(it.Value(), &rfqRequest); err != nil {
+			it.Release()
+			return fmt.Errorf("error decoding RFQRequest: %w", err)
+		}
+		if err := bc.indexRFQRequest(common.BytesToHash(it.Key()), &rfqRequest, 0); err != nil {
+			it.Release()
+			return err
+		}
+	}
+	it.Release()
+
+	// closed RFQs are also left in the open table, so they are indexed last
+	for _, rfqs := range []func() ([]*types.OpenRFQ, error){bc.GetOpenRFQRequests, bc.GetClosedRFQRequests} {
+		openRFQs, err := rfqs()
+		if err != nil {
+			return err
+		}
+		for _, openRFQ := range openRFQs {
+			if openRFQ.Data == nil {
+				continue
+			}
+			if err := bc.indexOpenRFQ(openRFQ.Data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openRFQTx(rfqRequest *types.Transaction, start int64, status types.RFQStatus) *types.Transaction {
+	data := &types.RFQData{
+		RFQTxHash:    rfqRequest.Hash(),
+		RFQRequest:   rfqRequest.EmbeddedData().(*types.SignableData),
+		RFQStartTime: start,
+		RFQEndTime:   start + 5000,
+		Status:       status,
+	}
+	return types.NewTx(types.NewOpenRFQ(testKey.PublicKey().Address(), data))
+}
+
+func TestQueryRFQs(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"rfqindex")
+	defer teardown()
+
+	alice := cryptoocax.GeneratePrivateKey()
+	bob := cryptoocax.GeneratePrivateKey()
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	// alice's RFQs open at 1000, 2000 and 3000, bob's at 4000 for USDC and
+	// the last of alice's is closed
+	var requests []*types.Transaction
+	for i, key := range []cryptoocax.PrivateKey{alice, alice, alice, bob} {
+		tx := randomTxWithSignature(t, key)
+		if i == 3 {
+			tx = randomTx(key.PublicKey())
+			tx.EmbeddedData().(*types.SignableData).QuoteToken.Address = usdc
+			var err error
+			tx, err = tx.Sign(key)
+			require.NoError(t, err)
+		}
+		require.NoError(t, bc.WriteRFQTxs(tx))
+		require.NoError(t, bc.WriteRFQTxs(openRFQTx(tx, int64(i+1)*1000, types.RFQStatusOpen)))
+		requests = append(requests, tx)
+	}
+	require.NoError(t, bc.WriteRFQTxs(openRFQTx(requests[2], 3000, types.RFQStatusClosed)))
+
+	// a request that has not been opened yet
+	pending := randomTxWithSignature(t, bob)
+	require.NoError(t, bc.WriteRFQTxs(pending))
+
+	openHashes := func(rfqs []*types.OpenRFQ) []common.Hash {
+		var hashes []common.Hash
+		for _, rfq := range rfqs {
+			hashes = append(hashes, rfq.Data.RFQTxHash)
+		}
+		return hashes
+	}
+
+	open, next, err := bc.QueryOpenRFQs(RFQQuery{Status: types.RFQStatusOpen}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	assert.Equal(t, []common.Hash{requests[0].Hash(), requests[1].Hash(), requests[3].Hash()}, openHashes(open))
+
+	closed, _, err := bc.QueryOpenRFQs(RFQQuery{Status: types.RFQStatusClosed}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{requests[2].Hash()}, openHashes(closed))
+
+	// newest first, one page at a time
+	q := RFQQuery{Status: types.RFQStatusOpen, Desc: true, Limit: 2}
+	open, next, err = bc.QueryOpenRFQs(q, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{requests[3].Hash(), requests[1].Hash()}, openHashes(open))
+	require.NotNil(t, next)
+	q.Cursor = next
+	open, next, err = bc.QueryOpenRFQs(q, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{requests[0].Hash()}, openHashes(open))
+	assert.Nil(t, next)
+
+	// a cursor only continues a listing in the same order
+	q.Desc = false
+	_, _, err = bc.QueryOpenRFQs(q, nil)
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	requestor := alice.PublicKey().Address()
+	open, _, err = bc.QueryOpenRFQs(RFQQuery{Status: types.RFQStatusOpen, Requestor: &requestor, From: 1500}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{requests[1].Hash()}, openHashes(open))
+
+	open, _, err = bc.QueryOpenRFQs(RFQQuery{Status: types.RFQStatusOpen, QuoteToken: &usdc}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{requests[3].Hash()}, openHashes(open))
+
+	// RFQs rejected by keep don't count towards the limit
+	open, _, err = bc.QueryOpenRFQs(RFQQuery{Status: types.RFQStatusOpen, Limit: 1}, func(rfq *types.OpenRFQ) bool {
+		return rfq.Data.RFQTxHash != requests[0].Hash()
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []common.Hash{requests[1].Hash()}, openHashes(open))
+
+	rfqRequests, _, err := bc.QueryRFQRequests(RFQQuery{Status: types.RFQStatusRequested}, nil)
+	require.NoError(t, err)
+	require.Len(t, rfqRequests, 1)
+	assert.Equal(t, bob.PublicKey().Address(), rfqRequests[0].From)
+
+	rfqRequests, _, err = bc.QueryRFQRequests(RFQQuery{To: 2000}, nil)
+	require.NoError(t, err)
+	assert.Len(t, rfqRequests, 2)
+}
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+)
+
+// WriteRiskUsage stores an encoded RFQ counted against its requestor's risk
+// limits.
+func (bc *Blockchain) WriteRiskUsage(rfqTxHash common.Hash, data []byte) error {
+	if err := bc.riskUsageTable.Put(rfqTxHash.Bytes(), data); err != nil {
+		return fmt.Errorf("error writing risk usage to kv store tables: %s", err.Error())
+	}
+	return nil
+}
+
+// DeleteRiskUsage removes an RFQ that no longer counts against any limit.
+func (bc *Blockchain) DeleteRiskUsage(rfqTxHash common.Hash) error {
+	return bc.riskUsageTable.Delete(rfqTxHash.Bytes())
+}
+
+// GetRiskUsage returns the stored RFQs counted against risk limits.
+func (bc *Blockchain) GetRiskUsage() ([][]byte, error) {
+	it := bc.riskUsageTable.NewIterator(nil, nil)
+	defer it.Release()
+
+	var usage [][]byte
+	for it.Next() {
+		usage = append(usage, append([]byte(nil), it.Value()...))
+	}
+	return usage, it.Error()
+}
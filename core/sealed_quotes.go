@@ -0,0 +1,92 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// GetClosedRFQByHash returns the closed auction record for the RFQ request
+// with the given transaction hash.
+func (bc *Blockchain) GetClosedRFQByHash(hash common.Hash) (*types.OpenRFQ, error) {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	return bc.readClosedRFQ(hash)
+}
+
+func (bc *Blockchain) readClosedRFQ(hash common.Hash) (*types.OpenRFQ, error) {
+	data, err := bc.closedRFQSTable.Get(hash.Bytes())
+	if err != nil || len(data) == 0 {
+		return nil, fmt.Errorf("closedRFQ with hash [%x]: %w", hash, ErrRFQNotFound)
+	}
+
+	var closedRFQ types.OpenRFQ
+	if err := rlp.DecodeBytes(data, &closedRFQ); err != nil {
+		return nil, fmt.Errorf("error decoding ClosedRFQ: %w", err)
+	}
+	return &closedRFQ, nil
+}
+
+// RevealQuotes sets the prices of the sealed quotes of a closed auction by
+// combining the validators' verified decryption shares, keyed by quote hash.
+// The shares are stored with the quotes so the reveal can be checked later. Both the closed auction record and the
+// quotes table are updated.
+func (bc *Blockchain) RevealQuotes(rfqTxHash common.Hash, shares map[common.Hash][]*threshold.DecryptionShare) (*types.OpenRFQ, error) {
+	bc.lock.Lock()
+	defer bc.lock.Unlock()
+
+	closedRFQ, err := bc.readClosedRFQ(rfqTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	reveal := func(quotes []*types.Quote) error {
+		for _, quote := range quotes {
+			quoteShares, ok := shares[quote.Hash()]
+			if !ok || !quote.Data.IsSealed() {
+				continue
+			}
+			if err := quote.Data.RevealPrices(quoteShares); err != nil {
+				return fmt.Errorf("error revealing quote [%x]: %w", quote.Hash(), err)
+			}
+		}
+		return nil
+	}
+
+	if err := reveal(closedRFQ.Data.Quotes); err != nil {
+		return nil, err
+	}
+	encRFQ := new(bytes.Buffer)
+	if err := closedRFQ.EncodeRLP(encRFQ); err != nil {
+		return nil, err
+	}
+	if err := bc.closedRFQSTable.Put(rfqTxHash.Bytes(), encRFQ.Bytes()); err != nil {
+		return nil, fmt.Errorf("error writing transaction to kv store tables: %s", err.Error())
+	}
+
+	existingQuotesBytes, _ := bc.quotesTable.Get(rfqTxHash.Bytes())
+	if len(existingQuotesBytes) == 0 {
+		return closedRFQ, nil
+	}
+	var quotes types.Quotes
+	if err := rlp.DecodeBytes(existingQuotesBytes, &quotes); err != nil {
+		return nil, fmt.Errorf("error decoding existing quotes: %s", err.Error())
+	}
+	if err := reveal(quotes); err != nil {
+		return nil, err
+	}
+	encQuotes := new(bytes.Buffer)
+	if err := rlp.Encode(encQuotes, quotes); err != nil {
+		return nil, fmt.Errorf("error encoding quotes: %s", err.Error())
+	}
+	if err := bc.quotesTable.Put(rfqTxHash.Bytes(), encQuotes.Bytes()); err != nil {
+		return nil, fmt.Errorf("error writing transaction to kv store tables: %s", err.Error())
+	}
+
+	return closedRFQ, nil
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"fmt"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Stop stops closing auctions and saves the open auctions with the quotes
+// they received, which are otherwise only held in memory, for NewBlockchain
+// to restore. The chain is not written to by the node afterwards. If ctx is
+// done before the auction queue stops nothing is saved, as the queue may
+// still be writing.
+func (bc *Blockchain) Stop(ctx context.Context) error {
+	bc.stopOnce.Do(func() { close(bc.quit) })
+	select {
+	case <-bc.auctionsDone:
+	case <-ctx.Done():
+		return fmt.Errorf("error waiting for the auction queue to stop: %w", ctx.Err())
+	}
+	return bc.saveOpenRFQs()
+}
+
+// saveOpenRFQs writes the auctions that have not been stored as closed to the
+// open RFQ table. An auction the queue closed but that was not stored yet is
+// saved as open so it is closed again once the node is back.
+func (bc *Blockchain) saveOpenRFQs() error {
+	bc.lock.RLock()
+	defer bc.lock.RUnlock()
+
+	batch := bc.openRFQSTable.NewBatch()
+	for rfqTxHash, openRFQ := range bc.openRFQsMap {
+		data := *openRFQ.Data
+		data.Status = types.RFQStatusOpen
+		data.QuotesRoot = common.Hash{}
+		saved := *openRFQ
+		saved.Data = &data
+
+		enc := new(bytes.Buffer)
+		if err := saved.EncodeRLP(enc); err != nil {
+			return err
+		}
+		if err := batch.Put(rfqTxHash.Bytes(), enc.Bytes()); err != nil {
+			return err
+		}
+	}
+	return batch.Write()
+}
+
+// loadOpenRFQs restores the auctions that were open when the node stopped.
+// Those that ended meanwhile are closed by the auction queue straight away.
+func (bc *Blockchain) loadOpenRFQs() error {
+	it := bc.openRFQSTable.NewIterator(nil, nil)
+	defer it.Release()
+
+	for it.Next() {
+		closed, err := bc.closedRFQSTable.Has(it.Key())
+		if err != nil {
+			return err
+		}
+		if closed {
+			continue
+		}
+		openRFQ := new(types.OpenRFQ)
+		if err := rlp.DecodeBytes(it.Value(), openRFQ); err != nil {
+			return fmt.Errorf("error decoding OpenRFQ: %w", err)
+		}
+		if openRFQ.Data == nil || openRFQ.Data.Status != types.RFQStatusOpen {
+			continue
+		}
+		bc.openRFQS = append(bc.openRFQS, openRFQ)
+		bc.openRFQsMap[common.BytesToHash(it.Key())] = openRFQ
+		heap.Push(&bc.auctionQueue, openRFQ)
+	}
+	return it.Error()
+}
@@ -0,0 +1,86 @@
+package core
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStopSavesOpenAuctions(t *testing.T) {
+	db, teardown := setupDB(t, dbPath+"shutdown")
+	defer teardown()
+	genesis := randomBlockWithSignature(t, testKey, 0, common.Hash{})
+	bc, err := NewBlockchain(log.NewNopLogger(), genesis, db, true)
+	require.NoError(t, err)
+
+	// one auction is still open with a quote held in memory, the other closed
+	now := time.Now().UnixMilli()
+	open := randomTxWithSignature(t, testKey)
+	closed := randomTxWithSignature(t, testKey)
+	for _, tx := range []*types.Transaction{open, closed} {
+		require.NoError(t, bc.WriteRFQTxs(tx))
+		require.NoError(t, bc.WriteRFQTxs(openRFQTx(tx, now, types.RFQStatusOpen)))
+	}
+	require.NoError(t, bc.WriteRFQTxs(openRFQTx(closed, now, types.RFQStatusClosed)))
+	data := open.EmbeddedData().(*types.SignableData)
+	quote := &types.Quote{From: testKey.PublicKey().Address(), Data: &types.QuoteData{
+		RFQTxHash:       open.Hash(),
+		BaseToken:       data.BaseToken,
+		QuoteToken:      data.QuoteToken,
+		BaseTokenAmount: data.BaseTokenAmount,
+		BidPrice:        big.NewInt(99),
+		AskPrice:        big.NewInt(101),
+	}}
+	require.NoError(t, bc.UpdateActiveRFQ(open.Hash(), quote))
+
+	require.NoError(t, bc.Stop(context.Background()))
+	// stopping twice is harmless
+	require.NoError(t, bc.Stop(context.Background()))
+
+	restarted, err := NewBlockchain(log.NewNopLogger(), genesis, db, true)
+	require.NoError(t, err)
+	defer restarted.Stop(context.Background())
+	openRFQ, err := restarted.GetOpenRFQByHash(open.Hash())
+	require.NoError(t, err)
+	require.Len(t, openRFQ.Data.Quotes, 1)
+	assert.Equal(t, quote.Data.BidPrice.String(), openRFQ.Data.Quotes[0].Data.BidPrice.String())
+	_, err = restarted.GetOpenRFQByHash(closed.Hash())
+	assert.ErrorIs(t, err, ErrRFQNotFound)
+
+	depth, next := restarted.AuctionQueueStatus()
+	assert.Equal(t, 1, depth)
+	assert.Equal(t, now+5000, next)
+}
+
+func TestMempool(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"mempool")
+	defer teardown()
+
+	txs := []*types.Transaction{randomTxWithSignature(t, testKey), randomTxWithSignature(t, testKey)}
+	require.NoError(t, bc.WriteMempool(txs))
+	saved, err := bc.ReadMempool()
+	require.NoError(t, err)
+	require.Len(t, saved, 2)
+	for i, tx := range txs {
+		assert.Equal(t, tx.Hash(), saved[i].Hash())
+		assert.Equal(t, *tx.From(), *saved[i].From())
+	}
+
+	// the saved mempool is replaced as a whole
+	require.NoError(t, bc.WriteMempool(txs[1:]))
+	saved, err = bc.ReadMempool()
+	require.NoError(t, err)
+	require.Len(t, saved, 1)
+	assert.Equal(t, txs[1].Hash(), saved[0].Hash())
+	require.NoError(t, bc.WriteMempool(nil))
+	saved, err = bc.ReadMempool()
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+}
@@ -127,6 +127,15 @@ func (b *Body) DecodeRLP(s *rlp.Stream) error {
 // 	return nil
 // }
 
+// TxInclusion is where a transaction was included in the chain.
+type TxInclusion struct {
+	BlockHash   common.Hash
+	BlockHeight uint64
+	// Index is the position of the transaction in the block
+	Index     uint64
+	Timestamp uint64
+}
+
 type Block struct {
 	header *Header
 
@@ -371,23 +380,7 @@ func (b *Block) MerkleRoot() common.Hash {
 		return EmptyTxsHash
 	}
 
-	for len(hashes) > 1 {
-		if len(hashes)%2 == 1 {
-			hashes = append(hashes, hashes[len(hashes)-1])
-		}
-
-		var newLevel []common.Hash
-		for i := 0; i < len(hashes); i += 2 {
-			var appendHash common.Hash
-			appendBytes := crypto.Keccak256(hashes[i][:], hashes[i+1][:])
-			copy(appendHash[:], appendBytes)
-			newLevel = append(newLevel, appendHash)
-		}
-
-		hashes = newLevel
-	}
-
-	return hashes[0]
+	return MerkleRoot(hashes)
 }
 
 func CalculateTxHash(txs []*Transaction) (hash common.Hash, err error) {
@@ -0,0 +1,55 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+)
+
+var (
+	ErrUnknownDealerGroup = errors.New("unknown dealer group")
+	ErrNoRecipients       = errors.New("private RFQ has no recipients")
+)
+
+// DealerGroups maps a dealer group name to the market makers in the group.
+// Groups are configured per validator and let requestors name a set of
+// dealers instead of listing them.
+type DealerGroups map[string][]common.Address
+
+// Recipients resolves the market makers a private RFQ is open to: the
+// explicit recipients and the members of its dealer group, deduplicated and
+// sorted. It returns nil for public RFQs.
+func (g DealerGroups) Recipients(d *SignableData) ([]common.Address, error) {
+	if !d.IsPrivate() {
+		return nil, nil
+	}
+	members := d.Recipients
+	if d.DealerGroup != "" {
+		group, ok := g[d.DealerGroup]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownDealerGroup, d.DealerGroup)
+		}
+		members = append(append([]common.Address(nil), members...), group...)
+	}
+
+	seen := make(map[common.Address]bool, len(members))
+	recipients := make([]common.Address, 0, len(members))
+	for _, addr := range members {
+		if addr == (common.Address{}) || seen[addr] {
+			continue
+		}
+		seen[addr] = true
+		recipients = append(recipients, addr)
+	}
+	// an empty set would make the RFQ public
+	if len(recipients) == 0 {
+		return nil, ErrNoRecipients
+	}
+	sort.Slice(recipients, func(i, j int) bool {
+		return bytes.Compare(recipients[i][:], recipients[j][:]) < 0
+	})
+	return recipients, nil
+}
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealerGroupsRecipients(t *testing.T) {
+	dealerA := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	dealerB := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	dealerC := common.HexToAddress("0x00000000000000000000000000000000000000c3")
+	groups := DealerGroups{
+		"tier1": {dealerC, dealerA},
+		"empty": {},
+	}
+
+	recipients, err := groups.Recipients(&SignableData{})
+	require.NoError(t, err)
+	assert.Nil(t, recipients, "public RFQs have no recipients")
+
+	recipients, err = groups.Recipients(&SignableData{Recipients: []common.Address{dealerB, dealerA}, DealerGroup: "tier1"})
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{dealerA, dealerB, dealerC}, recipients)
+
+	_, err = groups.Recipients(&SignableData{DealerGroup: "tier2"})
+	assert.ErrorIs(t, err, ErrUnknownDealerGroup)
+
+	// a private RFQ must not fall back to being public
+	_, err = groups.Recipients(&SignableData{DealerGroup: "empty"})
+	assert.ErrorIs(t, err, ErrNoRecipients)
+}
+
+func TestPrivateRFQ(t *testing.T) {
+	privateKey := cryptoocax.GeneratePrivateKey()
+	dealer := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+	other := cryptoocax.GeneratePrivateKey().PublicKey().Address()
+
+	newRFQData := func(request *SignableData) *RFQData {
+		return &RFQData{
+			RFQTxHash:    common.HexToHash("0x1234567890"),
+			RFQRequest:   request,
+			RFQStartTime: 1609459200000,
+			RFQEndTime:   1609459260000,
+			Status:       RFQStatusOpen,
+		}
+	}
+	request := &SignableData{
+		RequestorId:     "1234",
+		BaseTokenAmount: big.NewInt(1000),
+		BaseToken:       &BaseToken{},
+		QuoteToken:      &QuoteToken{},
+		RFQDurationMs:   60000,
+	}
+
+	// public RFQs encode as before recipients were added
+	public := newRFQData(request)
+	enc, err := rlp.EncodeToBytes(public)
+	require.NoError(t, err)
+	var elems []interface{}
+	require.NoError(t, rlp.DecodeBytes(enc, &elems))
+	assert.Len(t, elems, 8)
+	assert.NoError(t, public.CheckQuoter(other))
+
+	privateRequest := *request
+	privateRequest.DealerGroup = "tier1"
+	private := newRFQData(&privateRequest)
+	private.Recipients = []common.Address{dealer}
+	assert.True(t, private.IsPrivate())
+	assert.NoError(t, private.CheckQuoter(dealer))
+	assert.ErrorIs(t, private.CheckQuoter(other), ErrNotRecipient)
+
+	// the recipients survive the signed open RFQ transaction
+	signedTx, err := NewTx(NewOpenRFQ(privateKey.PublicKey().Address(), private)).Sign(privateKey)
+	require.NoError(t, err)
+	buf := new(bytes.Buffer)
+	require.NoError(t, signedTx.EncodeRLP(buf))
+	decodedTx := new(Transaction)
+	require.NoError(t, decodedTx.DecodeRLP(rlp.NewStream(buf, 0)))
+	assert.Equal(t, signedTx.Hash(), decodedTx.Hash())
+	require.NoError(t, decodedTx.Verify())
+
+	decoded := decodedTx.EmbeddedData().(*RFQData)
+	assert.Equal(t, []common.Address{dealer}, decoded.Recipients)
+	assert.Equal(t, "tier1", decoded.RFQRequest.DealerGroup)
+	assert.ErrorIs(t, decoded.CheckQuoter(other), ErrNotRecipient)
+}
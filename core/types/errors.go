@@ -0,0 +1,92 @@
+package types
+
+import (
+	"strings"
+)
+
+// ErrorCode identifies a failure reported to clients. Codes are stable so
+// clients can branch on them, the messages that go with them may change.
+type ErrorCode string
+
+// Codes of the validation errors of transactions and their fields.
+const (
+	CodeMissingField       ErrorCode = "MISSING_FIELD"
+	CodeInvalidAddress     ErrorCode = "INVALID_ADDRESS"
+	CodeInvalidChecksum    ErrorCode = "INVALID_CHECKSUM"
+	CodeInvalidSymbol      ErrorCode = "INVALID_SYMBOL"
+	CodeInvalidDecimals    ErrorCode = "INVALID_DECIMALS"
+	CodeInvalidAmount      ErrorCode = "INVALID_AMOUNT"
+	CodeInvalidDuration    ErrorCode = "INVALID_DURATION"
+	CodeInvalidRequestorId ErrorCode = "INVALID_REQUESTOR_ID"
+	CodeInvalidTimestamp   ErrorCode = "INVALID_TIMESTAMP"
+	CodeInvalidRecipient   ErrorCode = "INVALID_RECIPIENT"
+	CodeInvalidSignature   ErrorCode = "INVALID_SIGNATURE"
+)
+
+// Error is an error of the catalogue, identified by its code.
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+// NewError returns an error with the given code.
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// FieldError is the validation error of one field of a request. It wraps the
+// catalogue error it reports, so errors.Is matches against that error.
+type FieldError struct {
+	Field   string    `json:"field"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+
+	err *Error
+}
+
+// NewFieldError reports err for field, message describes the failure and
+// defaults to err's message.
+func NewFieldError(field string, err *Error, message string) *FieldError {
+	if message == "" {
+		message = err.Message
+	}
+	return &FieldError{Field: field, Code: err.Code, Message: message, err: err}
+}
+
+func (e *FieldError) Error() string { return e.Message }
+
+func (e *FieldError) Unwrap() error { return e.err }
+
+// ValidationErrors are the field errors of an invalid request.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, fieldErr := range e {
+		errs[i] = fieldErr
+	}
+	return errs
+}
+
+// add records a failure of field.
+func (e *ValidationErrors) add(field string, err *Error, message string) {
+	*e = append(*e, NewFieldError(field, err, message))
+}
+
+// err returns the errors, nil when every field is valid.
+func (e ValidationErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
@@ -161,3 +161,103 @@ func DeriveSha(list DerivableList, hasher TrieHasher) common.Hash {
 	}
 	return hasher.Hash()
 }
+
+// Domain prefixes of the Merkle tree hashes, so a leaf, an inner node and a
+// root can't be taken for one another.
+const (
+	merkleLeafPrefix byte = 0x00
+	merkleNodePrefix byte = 0x01
+	merkleRootPrefix byte = 0x02
+)
+
+// MerkleRoot computes the root of a binary keccak256 Merkle tree over hashes.
+// Leaves and inner nodes are hashed with their own prefix, the last node of
+// an odd level is promoted to the next level as is, and the root commits to
+// the number of leaves. It returns the zero hash for an empty set.
+func MerkleRoot(hashes []common.Hash) common.Hash {
+	if len(hashes) == 0 {
+		return common.Hash{}
+	}
+
+	level := make([]common.Hash, len(hashes))
+	for i, hash := range hashes {
+		level[i] = merkleLeaf(hash)
+	}
+	for len(level) > 1 {
+		level = merkleParentLevel(level)
+	}
+	return merkleRoot(uint64(len(hashes)), level[0])
+}
+
+// MerkleProof returns the sibling hashes needed to recompute the Merkle root
+// of hashes from the leaf at index, ordered from the leaf level upwards.
+// Levels where the leaf's node is promoted contribute no sibling.
+func MerkleProof(hashes []common.Hash, index int) ([]common.Hash, error) {
+	if index < 0 || index >= len(hashes) {
+		return nil, fmt.Errorf("merkle leaf index %d out of range [0, %d)", index, len(hashes))
+	}
+
+	level := make([]common.Hash, len(hashes))
+	for i, hash := range hashes {
+		level[i] = merkleLeaf(hash)
+	}
+	var proof []common.Hash
+	for len(level) > 1 {
+		if sibling := index ^ 1; sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		level = merkleParentLevel(level)
+		index /= 2
+	}
+	return proof, nil
+}
+
+// VerifyMerkleProof reports whether leaf at index is included in the Merkle
+// tree over count leaves with the given root. The proof must hold exactly
+// the siblings of the leaf's path in a tree of count leaves.
+func VerifyMerkleProof(root, leaf common.Hash, index, count uint64, proof []common.Hash) bool {
+	if index >= count {
+		return false
+	}
+	hash := merkleLeaf(leaf)
+	for size, i := count, index; size > 1; size, i = (size+1)/2, i/2 {
+		if i^1 >= size {
+			continue
+		}
+		if len(proof) == 0 {
+			return false
+		}
+		if i%2 == 0 {
+			hash = merkleNode(hash, proof[0])
+		} else {
+			hash = merkleNode(proof[0], hash)
+		}
+		proof = proof[1:]
+	}
+	return len(proof) == 0 && merkleRoot(count, hash) == root
+}
+
+func merkleParentLevel(level []common.Hash) []common.Hash {
+	parents := make([]common.Hash, 0, (len(level)+1)/2)
+	for i := 0; i+1 < len(level); i += 2 {
+		parents = append(parents, merkleNode(level[i], level[i+1]))
+	}
+	if len(level)%2 == 1 {
+		parents = append(parents, level[len(level)-1])
+	}
+	return parents
+}
+
+func merkleLeaf(leaf common.Hash) common.Hash {
+	return common.BytesToHash(crypto.Keccak256([]byte{merkleLeafPrefix}, leaf[:]))
+}
+
+func merkleNode(left, right common.Hash) common.Hash {
+	return common.BytesToHash(crypto.Keccak256([]byte{merkleNodePrefix}, left[:], right[:]))
+}
+
+func merkleRoot(count uint64, top common.Hash) common.Hash {
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], count)
+	return common.BytesToHash(crypto.Keccak256([]byte{merkleRootPrefix}, n[:], top[:]))
+}
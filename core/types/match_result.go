@@ -0,0 +1,74 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var ErrMatchResultNotSigned = errors.New("match result is not signed")
+
+// MatchedQuote is the winning quote for one side of an auction.
+type MatchedQuote struct {
+	QuoteHash common.Hash    `json:"quoteHash"`
+	Quoter    common.Address `json:"quoter"`
+	Price     *big.Int       `json:"price"`
+}
+
+// MatchResult records the outcome of running a matching engine over a closed
+// auction. It is signed by the validator that ran the engine so that anyone
+// replaying the auction can check the recorded result against their own.
+type MatchResult struct {
+	RFQTxHash  common.Hash `json:"rfqTxHash"`
+	QuotesRoot common.Hash `json:"quotesRoot"`
+	Engine     string      `json:"engine"`
+	// BestBid and BestAsk are nil when no quote was made on that side
+	BestBid *MatchedQuote `json:"bestBid" rlp:"nil"`
+	BestAsk *MatchedQuote `json:"bestAsk" rlp:"nil"`
+
+	Validator common.Address `json:"validator"`
+	Signature hexutil.Bytes  `json:"signature"`
+}
+
+// Hash returns the hash signed by the validator, covering every field except
+// the signature itself.
+func (m *MatchResult) Hash() common.Hash {
+	return rlpHash([]interface{}{
+		m.RFQTxHash,
+		m.QuotesRoot,
+		m.Engine,
+		m.BestBid,
+		m.BestAsk,
+		m.Validator,
+	})
+}
+
+// Sign signs the match result with the validator's key.
+func (m *MatchResult) Sign(pk cryptoocax.PrivateKey) error {
+	m.Validator = pk.PublicKey().Address()
+	sig, err := pk.Sign(m.Hash().Bytes())
+	if err != nil {
+		return err
+	}
+	m.Signature = sig.ToBytes()
+	return nil
+}
+
+// Verify checks that the match result was signed by its validator.
+func (m *MatchResult) Verify() error {
+	if len(m.Signature) == 0 {
+		return ErrMatchResultNotSigned
+	}
+	signer, err := RecoverAddress(m.Hash(), m.Signature)
+	if err != nil {
+		return err
+	}
+	if signer != m.Validator {
+		return fmt.Errorf("signature does not match validator %s", m.Validator.Hex())
+	}
+	return nil
+}
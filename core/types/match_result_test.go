@@ -0,0 +1,34 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchResultSignAndRLP(t *testing.T) {
+	result := &MatchResult{
+		RFQTxHash:  common.HexToHash("0x1234567890"),
+		QuotesRoot: common.HexToHash("0xabcdef"),
+		Engine:     "best-price",
+		BestBid:    &MatchedQuote{QuoteHash: common.HexToHash("0x01"), Quoter: common.HexToAddress("0x02"), Price: big.NewInt(100)},
+	}
+	require.NoError(t, result.Sign(cryptoocax.GeneratePrivateKey()))
+	require.NoError(t, result.Verify())
+
+	enc, err := rlp.EncodeToBytes(result)
+	require.NoError(t, err)
+	decoded := new(MatchResult)
+	require.NoError(t, rlp.DecodeBytes(enc, decoded))
+	assert.Nil(t, decoded.BestAsk)
+	assert.Equal(t, result.BestBid, decoded.BestBid)
+	require.NoError(t, decoded.Verify())
+
+	decoded.BestBid.Price = big.NewInt(101)
+	assert.Error(t, decoded.Verify())
+}
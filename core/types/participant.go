@@ -0,0 +1,164 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+type ParticipantRole string
+
+const (
+	RoleRequestor   ParticipantRole = "requestor"
+	RoleMarketMaker ParticipantRole = "market_maker"
+	RoleAuditor     ParticipantRole = "auditor"
+)
+
+type ParticipantStatus string
+
+const (
+	ParticipantActive    ParticipantStatus = "active"
+	ParticipantSuspended ParticipantStatus = "suspended"
+	ParticipantRevoked   ParticipantStatus = "revoked"
+)
+
+var (
+	ErrParticipantNotSigned = errors.New("participant record is not signed")
+	ErrParticipantInactive  = errors.New("participant is not active")
+	ErrParticipantExpired   = errors.New("participant onboarding has expired")
+	ErrParticipantRole      = errors.New("participant is not onboarded for this role")
+	ErrParticipantLimit     = errors.New("amount exceeds the participant's limit")
+)
+
+// ParticipantLimits are the trading limits agreed during onboarding. Zero
+// values mean no limit. Amounts are in base token units.
+type ParticipantLimits struct {
+	// MaxBaseTokenAmount caps the size of a single RFQ or quote
+	MaxBaseTokenAmount *big.Int `json:"maxBaseTokenAmount"`
+	// MaxOpenAmount caps the total amount of a requestor's open RFQs
+	MaxOpenAmount *big.Int `json:"maxOpenAmount,omitempty" rlp:"optional"`
+	// MaxDailyAmount caps the total amount of the RFQs a requestor opens in
+	// any 24 hours
+	MaxDailyAmount *big.Int `json:"maxDailyAmount,omitempty" rlp:"optional"`
+	// Pairs cap the open amount in individual token pairs
+	Pairs []PairLimit `json:"pairs,omitempty" rlp:"optional"`
+}
+
+// PairLimit caps the total amount of a requestor's open RFQs selling
+// BaseToken for QuoteToken.
+type PairLimit struct {
+	BaseToken     common.Address `json:"baseToken"`
+	QuoteToken    common.Address `json:"quoteToken"`
+	MaxOpenAmount *big.Int       `json:"maxOpenAmount"`
+}
+
+// PairLimit returns the limit for the token pair, nil if it has none.
+func (l *ParticipantLimits) PairLimit(baseToken, quoteToken common.Address) *big.Int {
+	for _, pair := range l.Pairs {
+		if pair.BaseToken == baseToken && pair.QuoteToken == quoteToken {
+			return pair.MaxOpenAmount
+		}
+	}
+	return nil
+}
+
+// Participant is an onboarded marketplace participant. Records are signed by
+// the registry admin so every node can verify an update independently.
+type Participant struct {
+	Address common.Address    `json:"address"`
+	Role    ParticipantRole   `json:"role"`
+	Status  ParticipantStatus `json:"status"`
+	Limits  ParticipantLimits `json:"limits"`
+	// ExpiresAt is the unix time in milliseconds the onboarding lapses, 0 for never
+	ExpiresAt uint64 `json:"expiresAt"`
+	// UpdatedAt is the unix time in milliseconds of the update, later updates
+	// replace earlier ones
+	UpdatedAt uint64 `json:"updatedAt"`
+
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Validate checks the participant record is well formed.
+func (p *Participant) Validate() error {
+	switch p.Role {
+	case RoleRequestor, RoleMarketMaker, RoleAuditor:
+	default:
+		return fmt.Errorf("invalid participant role %q", p.Role)
+	}
+	switch p.Status {
+	case ParticipantActive, ParticipantSuspended, ParticipantRevoked:
+	default:
+		return fmt.Errorf("invalid participant status %q", p.Status)
+	}
+	if p.Address == (common.Address{}) {
+		return errors.New("invalid participant address")
+	}
+	for _, pair := range p.Limits.Pairs {
+		if pair.BaseToken == (common.Address{}) || pair.QuoteToken == (common.Address{}) {
+			return errors.New("invalid pair limit token address")
+		}
+	}
+	return nil
+}
+
+// Hash returns the hash signed by the admin, covering every field except the
+// signature itself.
+func (p *Participant) Hash() common.Hash {
+	return rlpHash([]interface{}{
+		p.Address,
+		p.Role,
+		p.Status,
+		p.Limits,
+		p.ExpiresAt,
+		p.UpdatedAt,
+	})
+}
+
+// Sign signs the participant record with the admin key.
+func (p *Participant) Sign(pk cryptoocax.PrivateKey) error {
+	sig, err := pk.Sign(p.Hash().Bytes())
+	if err != nil {
+		return err
+	}
+	p.Signature = sig.ToBytes()
+	return nil
+}
+
+// Signer returns the address that signed the participant record.
+func (p *Participant) Signer() (common.Address, error) {
+	if len(p.Signature) == 0 {
+		return common.Address{}, ErrParticipantNotSigned
+	}
+	return RecoverAddress(p.Hash(), p.Signature)
+}
+
+// CheckRole reports whether the participant may act in role at the given
+// unix time in milliseconds.
+func (p *Participant) CheckRole(role ParticipantRole, at uint64) error {
+	if p.Status != ParticipantActive {
+		return fmt.Errorf("%w: %s", ErrParticipantInactive, p.Status)
+	}
+	if p.ExpiresAt != 0 && at > p.ExpiresAt {
+		return ErrParticipantExpired
+	}
+	if p.Role != role {
+		return fmt.Errorf("%w: %s", ErrParticipantRole, role)
+	}
+	return nil
+}
+
+// CheckAmount reports whether amount is within the participant's limits.
+func (p *Participant) CheckAmount(amount *big.Int) error {
+	max := p.Limits.MaxBaseTokenAmount
+	if max == nil || max.Sign() == 0 || amount == nil {
+		return nil
+	}
+	if amount.Cmp(max) > 0 {
+		return fmt.Errorf("%w: %s > %s", ErrParticipantLimit, amount, max)
+	}
+	return nil
+}
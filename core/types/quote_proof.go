@@ -0,0 +1,65 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+)
+
+// ErrQuoteNotFound is returned for a quote that is not part of an auction.
+var ErrQuoteNotFound = errors.New("quote not found")
+
+// QuoteProof proves that a quote was part of the quote set committed to by a
+// closed auction's QuotesRoot.
+type QuoteProof struct {
+	QuoteHash common.Hash `json:"quoteHash"`
+	Index     uint64      `json:"index"`
+	// Count is the number of quotes the root commits to
+	Count      uint64        `json:"count"`
+	Proof      []common.Hash `json:"proof"`
+	QuotesRoot common.Hash   `json:"quotesRoot"`
+}
+
+// Verify reports whether the proof links the quote hash to the quotes root.
+func (p *QuoteProof) Verify() bool {
+	return VerifyMerkleProof(p.QuotesRoot, p.QuoteHash, p.Index, p.Count, p.Proof)
+}
+
+// QuoteHashes returns the hashes of the auction's quotes in the order they
+// were received.
+func (d *RFQData) QuoteHashes() []common.Hash {
+	hashes := make([]common.Hash, len(d.Quotes))
+	for i, quote := range d.Quotes {
+		hashes[i] = quote.Hash()
+	}
+	return hashes
+}
+
+// QuotesMerkleRoot computes the Merkle root over the auction's quote hashes.
+func (d *RFQData) QuotesMerkleRoot() common.Hash {
+	return MerkleRoot(d.QuoteHashes())
+}
+
+// QuoteProof builds the inclusion proof of the quote with the given hash
+// against the auction's committed quotes root.
+func (d *RFQData) QuoteProof(quoteHash common.Hash) (*QuoteProof, error) {
+	hashes := d.QuoteHashes()
+	for i, hash := range hashes {
+		if hash != quoteHash {
+			continue
+		}
+		proof, err := MerkleProof(hashes, i)
+		if err != nil {
+			return nil, err
+		}
+		return &QuoteProof{
+			QuoteHash:  quoteHash,
+			Index:      uint64(i),
+			Count:      uint64(len(hashes)),
+			Proof:      proof,
+			QuotesRoot: d.QuotesRoot,
+		}, nil
+	}
+	return nil, fmt.Errorf("quote [%x] in auction [%x]: %w", quoteHash, d.RFQTxHash, ErrQuoteNotFound)
+}
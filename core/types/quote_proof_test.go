@@ -0,0 +1,118 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleProofs(t *testing.T) {
+	for n := 1; n <= 7; n++ {
+		hashes := make([]common.Hash, n)
+		for i := range hashes {
+			hashes[i] = common.BytesToHash([]byte{byte(i + 1)})
+		}
+		root := MerkleRoot(hashes)
+		count := uint64(n)
+
+		for i, leaf := range hashes {
+			proof, err := MerkleProof(hashes, i)
+			require.NoError(t, err)
+			assert.True(t, VerifyMerkleProof(root, leaf, uint64(i), count, proof), "n=%d i=%d", n, i)
+			assert.False(t, VerifyMerkleProof(root, common.Hash{0xff}, uint64(i), count, proof), "n=%d i=%d", n, i)
+			// the proof must be exactly the leaf's path in a tree of count
+			// leaves
+			assert.False(t, VerifyMerkleProof(root, leaf, uint64(i), count+1, proof), "n=%d i=%d", n, i)
+			assert.False(t, VerifyMerkleProof(root, leaf, uint64(i), count, append(proof, leaf)), "n=%d i=%d", n, i)
+			if len(proof) > 0 {
+				assert.False(t, VerifyMerkleProof(root, leaf, uint64(i), count, proof[1:]), "n=%d i=%d", n, i)
+			}
+		}
+		assert.False(t, VerifyMerkleProof(root, hashes[0], count, count, nil), "n=%d", n)
+	}
+
+	_, err := MerkleProof(nil, 0)
+	assert.Error(t, err)
+}
+
+func TestMerkleRootIsUnambiguous(t *testing.T) {
+	a, b, c := common.Hash{0x0a}, common.Hash{0x0b}, common.Hash{0x0c}
+
+	// repeating the last leaf gives another root
+	assert.NotEqual(t, MerkleRoot([]common.Hash{a, b, c}), MerkleRoot([]common.Hash{a, b, c, c}))
+	assert.NotEqual(t, MerkleRoot([]common.Hash{a}), MerkleRoot([]common.Hash{a, a}))
+
+	// an inner node doesn't pass for a leaf of a shallower proof
+	leaves := []common.Hash{a, b, c, common.Hash{0x0d}}
+	root := MerkleRoot(leaves)
+	inner := merkleNode(merkleLeaf(a), merkleLeaf(b))
+	proof, err := MerkleProof(leaves, 0)
+	require.NoError(t, err)
+	for count := uint64(1); count <= 4; count++ {
+		assert.False(t, VerifyMerkleProof(root, inner, 0, count, proof[1:]), "count=%d", count)
+	}
+}
+
+func TestClosedRFQQuoteProof(t *testing.T) {
+	privateKey := cryptoocax.GeneratePrivateKey()
+	from := privateKey.PublicKey().Address()
+
+	rfqData := &RFQData{
+		RFQTxHash:    common.HexToHash("0x1234567890"),
+		RFQRequest:   &SignableData{RequestorId: "1234", BaseTokenAmount: big.NewInt(1000), BaseToken: &BaseToken{}, QuoteToken: &QuoteToken{}},
+		RFQStartTime: 1609459200000,
+		RFQEndTime:   1609459260000,
+		Status:       RFQStatusOpen,
+	}
+	for i := 0; i < 3; i++ {
+		rfqData.Quotes = append(rfqData.Quotes, &Quote{
+			From: from,
+			Data: &QuoteData{
+				QuoterId:             "quoter",
+				RFQTxHash:            rfqData.RFQTxHash,
+				BaseToken:            &BaseToken{},
+				QuoteToken:           &QuoteToken{},
+				BaseTokenAmount:      big.NewInt(1000),
+				BidPrice:             big.NewInt(int64(100 + i)),
+				AskPrice:             big.NewInt(int64(200 + i)),
+				EncryptionPublicKeys: []*cryptoocax.PublicKey{},
+			},
+			V: big.NewInt(0), R: big.NewInt(1), S: big.NewInt(1),
+		})
+	}
+	rfqData.Close()
+	assert.Equal(t, MerkleRoot(rfqData.QuoteHashes()), rfqData.QuotesRoot)
+
+	// the quotes root survives an RLP round trip
+	buf := new(bytes.Buffer)
+	require.NoError(t, rfqData.EncodeRLP(buf))
+	decoded := new(RFQData)
+	require.NoError(t, decoded.DecodeRLP(rlp.NewStream(buf, 0)))
+	assert.Equal(t, rfqData.QuotesRoot, decoded.QuotesRoot)
+
+	quoteHash := rfqData.Quotes[2].Hash()
+	proof, err := decoded.QuoteProof(quoteHash)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), proof.Index)
+	assert.True(t, proof.Verify())
+
+	_, err = decoded.QuoteProof(common.Hash{0x01})
+	assert.Error(t, err)
+
+	// and through the signed closed RFQ transaction recorded on chain
+	signedTx, err := NewTx(NewOpenRFQ(from, rfqData)).Sign(privateKey)
+	require.NoError(t, err)
+	buf.Reset()
+	require.NoError(t, signedTx.EncodeRLP(buf))
+	decodedTx := new(Transaction)
+	require.NoError(t, decodedTx.DecodeRLP(rlp.NewStream(buf, 0)))
+	assert.Equal(t, signedTx.Hash(), decodedTx.Hash())
+	assert.Equal(t, rfqData.QuotesRoot, decodedTx.EmbeddedData().(*RFQData).QuotesRoot)
+	require.NoError(t, decodedTx.Verify())
+}
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	ErrQuoteNotSealed    = errors.New("quote is not sealed")
+	ErrRevealNotProven   = errors.New("revealed prices have no decryption shares")
+	ErrRevealedPriceDiff = errors.New("revealed prices differ from the sealed prices")
+)
+
+// QuotePrices is the plaintext sealed inside an encrypted quote.
+type QuotePrices struct {
+	BidPrice *big.Int
+	AskPrice *big.Int
+}
+
+// IsSealed reports whether the quote prices are encrypted to the threshold key.
+func (q *QuoteData) IsSealed() bool {
+	return len(q.EncryptedQuote) > 0
+}
+
+// SealPrices encrypts the bid and ask prices to the validators' threshold key
+// and clears them from the quote, so they can only be recovered once enough
+// validators release their decryption shares after the auction closes.
+// Quoters must seal before signing the quote.
+func (q *QuoteData) SealPrices(pub *threshold.PublicKey) error {
+	plaintext, err := rlp.EncodeToBytes(&QuotePrices{BidPrice: q.BidPrice, AskPrice: q.AskPrice})
+	if err != nil {
+		return err
+	}
+	ct, err := threshold.Encrypt(pub, plaintext)
+	if err != nil {
+		return err
+	}
+	enc, err := ct.Bytes()
+	if err != nil {
+		return err
+	}
+	q.EncryptedQuote = enc
+	q.BidPrice = new(big.Int)
+	q.AskPrice = new(big.Int)
+	return nil
+}
+
+// Ciphertext returns the decoded encrypted prices of a sealed quote.
+func (q *QuoteData) Ciphertext() (*threshold.Ciphertext, error) {
+	if !q.IsSealed() {
+		return nil, ErrQuoteNotSealed
+	}
+	return threshold.CiphertextFromBytes(q.EncryptedQuote)
+}
+
+// RevealPrices sets the bid and ask prices by combining the validators'
+// decryption shares, which must have been checked with threshold.VerifyShare.
+// The shares are kept with the quote for VerifyReveal.
+func (q *QuoteData) RevealPrices(shares []*threshold.DecryptionShare) error {
+	prices, err := q.sealedPrices(shares)
+	if err != nil {
+		return err
+	}
+	q.BidPrice = prices.BidPrice
+	q.AskPrice = prices.AskPrice
+	q.DecryptionShares = shares
+	return nil
+}
+
+// VerifyReveal checks the revealed prices of a sealed quote by decrypting
+// its signed ciphertext again with the decryption shares kept with it, so
+// prices changed after the reveal are caught. Plaintext quotes have nothing
+// to check.
+func (q *QuoteData) VerifyReveal() error {
+	if !q.IsSealed() {
+		return nil
+	}
+	if len(q.DecryptionShares) == 0 {
+		return ErrRevealNotProven
+	}
+	prices, err := q.sealedPrices(q.DecryptionShares)
+	if err != nil {
+		return err
+	}
+	if !equalPrice(prices.BidPrice, q.BidPrice) || !equalPrice(prices.AskPrice, q.AskPrice) {
+		return ErrRevealedPriceDiff
+	}
+	return nil
+}
+
+// sealedPrices decrypts the sealed prices with every one of shares.
+func (q *QuoteData) sealedPrices(shares []*threshold.DecryptionShare) (*QuotePrices, error) {
+	ct, err := q.Ciphertext()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := threshold.Combine(ct, shares, len(shares))
+	if err != nil {
+		return nil, err
+	}
+	prices := new(QuotePrices)
+	if err := rlp.DecodeBytes(plaintext, prices); err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+func equalPrice(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+// signingData returns the quote data covered by the quoter's signature. For
+// sealed quotes the signature commits to the ciphertext only, so revealing the
+// prices after the auction, and keeping the decryption shares, does not
+// change the quote hash or invalidate it.
+func (q *QuoteData) signingData() *QuoteData {
+	if !q.IsSealed() {
+		return q
+	}
+	cpy, _ := q.deepCopy()
+	cpy.BidPrice = new(big.Int)
+	cpy.AskPrice = new(big.Int)
+	cpy.DecryptionShares = nil
+	return cpy
+}
+
+// Hash returns the hash of the quote, identical to the hash of the quote
+// transaction that carried it.
+func (q *Quote) Hash() common.Hash {
+	return prefixedRlpHash(QuoteTxType, q.data())
+}
+
+// signingData returns the auction data covered by the validator's signature,
+// with sealed quotes in the form their quoters signed them.
+func (d *RFQData) signingData() *RFQData {
+	cpy, _ := d.deepCopy()
+	for i, quote := range d.Quotes {
+		if quote == nil || quote.Data == nil || !quote.Data.IsSealed() {
+			continue
+		}
+		q := *quote
+		q.Data = quote.Data.signingData()
+		cpy.Quotes[i] = &q
+	}
+	return cpy
+}
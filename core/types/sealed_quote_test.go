@@ -0,0 +1,123 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealedQuoteRevealKeepsHash(t *testing.T) {
+	privateKey := cryptoocax.GeneratePrivateKey()
+	from := privateKey.PublicKey().Address()
+
+	pub, shares, err := threshold.Deal(2, 3)
+	require.NoError(t, err)
+
+	quoteData := &QuoteData{
+		QuoterId:        "1234",
+		RFQTxHash:       common.HexToHash("0x1234567890"),
+		QuoteExpiryTime: 1609459200,
+		BaseToken: &BaseToken{
+			Address:  common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"),
+			Symbol:   "ABC",
+			Decimals: 18,
+		},
+		QuoteToken: &QuoteToken{
+			Address:  common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+			Symbol:   "XYZ",
+			Decimals: 18,
+		},
+		BaseTokenAmount:      big.NewInt(10000),
+		BidPrice:             big.NewInt(200),
+		AskPrice:             big.NewInt(300),
+		EncryptionPublicKeys: []*cryptoocax.PublicKey{},
+	}
+	require.NoError(t, quoteData.SealPrices(pub))
+	assert.True(t, quoteData.IsSealed())
+	assert.Zero(t, quoteData.BidPrice.Sign())
+	assert.Zero(t, quoteData.AskPrice.Sign())
+
+	signedTx, err := NewTx(NewQuote(from, quoteData)).Sign(privateKey)
+	require.NoError(t, err)
+	require.NoError(t, signedTx.Verify())
+
+	// the ciphertext survives an RLP round trip
+	buf := new(bytes.Buffer)
+	require.NoError(t, signedTx.EncodeRLP(buf))
+	decodedTx := new(Transaction)
+	require.NoError(t, decodedTx.DecodeRLP(rlp.NewStream(buf, 0)))
+	assert.Equal(t, signedTx.Hash(), decodedTx.Hash())
+
+	v, r, s := decodedTx.RawSignatureValues()
+	quote := &Quote{From: *decodedTx.From(), Data: decodedTx.EmbeddedData().(*QuoteData), V: v, R: r, S: s}
+	assert.Equal(t, signedTx.Hash(), quote.Hash())
+
+	ct, err := quote.Data.Ciphertext()
+	require.NoError(t, err)
+	d1, err := shares[0].DecryptionShare(ct)
+	require.NoError(t, err)
+	d3, err := shares[2].DecryptionShare(ct)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, quote.Data.VerifyReveal(), ErrRevealNotProven)
+	require.NoError(t, quote.Data.RevealPrices([]*threshold.DecryptionShare{d1, d3}))
+	assert.Equal(t, big.NewInt(200), quote.Data.BidPrice)
+	assert.Equal(t, big.NewInt(300), quote.Data.AskPrice)
+	assert.Equal(t, signedTx.Hash(), quote.Hash())
+	require.NoError(t, quote.Data.VerifyReveal())
+
+	// the decryption shares survive an RLP round trip without changing the hash
+	enc, err := rlp.EncodeToBytes(quote)
+	require.NoError(t, err)
+	decoded := new(Quote)
+	require.NoError(t, rlp.DecodeBytes(enc, decoded))
+	assert.Len(t, decoded.Data.DecryptionShares, 2)
+	assert.Equal(t, signedTx.Hash(), decoded.Hash())
+	require.NoError(t, decoded.Data.VerifyReveal())
+
+	// as they do inside a closed auction
+	closedRFQ := &OpenRFQ{From: from, Data: &RFQData{
+		RFQTxHash: quoteData.RFQTxHash,
+		RFQRequest: &SignableData{
+			RequestorId:     "1",
+			BaseTokenAmount: quoteData.BaseTokenAmount,
+			BaseToken:       quoteData.BaseToken,
+			QuoteToken:      quoteData.QuoteToken,
+			RFQDurationMs:   60000,
+		},
+		Status: RFQStatusClosed,
+		Quotes: []*Quote{quote},
+	}}
+	enc, err = rlp.EncodeToBytes(closedRFQ)
+	require.NoError(t, err)
+	decodedRFQ := new(OpenRFQ)
+	require.NoError(t, rlp.DecodeBytes(enc, decodedRFQ))
+	assert.True(t, decodedRFQ.Data.Quotes[0].Data.IsSealed())
+	assert.Len(t, decodedRFQ.Data.Quotes[0].Data.DecryptionShares, 2)
+	assert.Equal(t, signedTx.Hash(), decodedRFQ.Data.Quotes[0].Hash())
+	require.NoError(t, decodedRFQ.Data.Quotes[0].Data.VerifyReveal())
+
+	// a price changed after the reveal no longer matches the ciphertext
+	decoded.Data.BidPrice = big.NewInt(201)
+	assert.ErrorIs(t, decoded.Data.VerifyReveal(), ErrRevealedPriceDiff)
+	assert.Equal(t, signedTx.Hash(), decoded.Hash())
+
+	// and neither do shares from too few validators
+	decoded.Data.BidPrice = big.NewInt(200)
+	decoded.Data.DecryptionShares = decoded.Data.DecryptionShares[:1]
+	assert.ErrorIs(t, decoded.Data.VerifyReveal(), threshold.ErrDecryptionFailed)
+}
+
+func TestRevealPricesUnsealedQuote(t *testing.T) {
+	quoteData := &QuoteData{BidPrice: big.NewInt(1), AskPrice: big.NewInt(2)}
+	assert.ErrorIs(t, quoteData.RevealPrices(nil), ErrQuoteNotSealed)
+	_, err := quoteData.Ciphertext()
+	assert.ErrorIs(t, err, ErrQuoteNotSealed)
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenUnmarshalChecksum(t *testing.T) {
+	var token Token
+	assert.NoError(t, json.Unmarshal([]byte(`{"address":"0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2","symbol":"MKR","decimals":18}`), &token))
+	assert.NoError(t, json.Unmarshal([]byte(`{"address":"0x9f8f72aa9304c8b593d555f12ef6589cc3a579a2","symbol":"MKR","decimals":18}`), &token))
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"address":"0x9F8f72aA9304c8B593d555F12eF6589cC3A579A2","symbol":"MKR","decimals":18}`), &token), ErrInvalidChecksum)
+}
+
+func TestQuoteCheckRFQ(t *testing.T) {
+	mkr := &Token{Address: common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"), Symbol: "MKR", Decimals: 18}
+	usdc := &Token{Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), Symbol: "USDC", Decimals: 6}
+	rfq := &SignableData{BaseTokenAmount: big.NewInt(100), BaseToken: mkr, QuoteToken: usdc}
+
+	quote := &QuoteData{BaseToken: mkr, QuoteToken: usdc, BaseTokenAmount: big.NewInt(100)}
+	assert.NoError(t, quote.CheckRFQ(rfq))
+
+	quote.BaseTokenAmount = big.NewInt(101)
+	assert.ErrorIs(t, quote.CheckRFQ(rfq), ErrQuoteAmount)
+
+	// the same token quoted in different units
+	quote.BaseTokenAmount = big.NewInt(100)
+	quote.QuoteToken = &Token{Address: usdc.Address, Symbol: "USDC", Decimals: 18}
+	assert.ErrorIs(t, quote.CheckRFQ(rfq), ErrQuoteTokenMismatch)
+
+	quote.QuoteToken, quote.BaseToken = mkr, usdc
+	assert.ErrorIs(t, quote.CheckRFQ(rfq), ErrQuoteTokenMismatch)
+}
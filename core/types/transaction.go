@@ -17,18 +17,24 @@ import (
 )
 
 var (
-	ErrInvalidSig         = errors.New("invalid transaction v, r, s values")
 	ErrInvalidTxType      = errors.New("transaction type not valid in this context")
 	ErrTxTypeNotSupported = errors.New("transaction type not supported")
 	errShortTypedTx       = errors.New("typed transaction too short")
-	errInvalidAddress     = errors.New("invalid Ethereum address")
-	errInvalidChecksum    = errors.New("invalid Ethereum address checksum")
-	errInvalidSymbol      = errors.New("invalid token symbol")
-	errInvalidDecimals    = errors.New("invalid token decimals")
-	errInvalidAmount      = errors.New("invalid token amount")
-	errInvalidDuration    = errors.New("invalid RFQ duration")
-	errInvalidRequestorId = errors.New("invalid requestor ID")
-	errInvalidTimestamp   = errors.New("invalid timestamp")
+)
+
+// Validation errors of transactions, shared with the API.
+var (
+	ErrInvalidSig         = NewError(CodeInvalidSignature, "invalid transaction v, r, s values")
+	ErrMissingField       = NewError(CodeMissingField, "missing field")
+	ErrInvalidAddress     = NewError(CodeInvalidAddress, "invalid Ethereum address")
+	ErrInvalidChecksum    = NewError(CodeInvalidChecksum, "invalid Ethereum address checksum")
+	ErrInvalidSymbol      = NewError(CodeInvalidSymbol, "invalid token symbol")
+	ErrInvalidDecimals    = NewError(CodeInvalidDecimals, "invalid token decimals")
+	ErrInvalidAmount      = NewError(CodeInvalidAmount, "invalid token amount")
+	ErrInvalidDuration    = NewError(CodeInvalidDuration, "invalid RFQ duration")
+	ErrInvalidRequestorId = NewError(CodeInvalidRequestorId, "invalid requestor ID")
+	ErrInvalidTimestamp   = NewError(CodeInvalidTimestamp, "invalid timestamp")
+	ErrInvalidRecipient   = NewError(CodeInvalidRecipient, "invalid recipient")
 )
 
 const (
@@ -280,33 +286,33 @@ func (tx *Transaction) Sender() (common.Address, error) {
 func (tx *Transaction) Verify() error {
 	v, r, s := tx.inner.rawSignatureValues()
 	if r == nil || s == nil || v == nil {
-		return fmt.Errorf("no signature - invalid transaction")
+		return NewError(CodeInvalidSignature, "no signature - invalid transaction")
 	}
 
 	sig := &cryptoocax.Signature{R: r, S: s, V: v}
 
 	if !cryptoocax.ValidateSignatureValues(byte(v.Uint64()), r, s) {
-		return fmt.Errorf("invalid signature values")
+		return NewError(CodeInvalidSignature, "invalid signature values")
 	}
 	hash := tx.Hash() // Calculate the transaction hash here.
 	recoveredPubKey, err := cryptoocax.Ecrecover(hash.Bytes(), sig.ToBytes())
 	if err != nil {
-		return fmt.Errorf("failed to recover public key: %v", err)
+		return NewError(CodeInvalidSignature, fmt.Sprintf("failed to recover public key: %v", err))
 	}
 
 	pubKey, err := crypto.UnmarshalPubkey(recoveredPubKey)
 	if err != nil {
-		return fmt.Errorf("invalid public key: %v", err)
+		return NewError(CodeInvalidSignature, fmt.Sprintf("invalid public key: %v", err))
 	}
 
 	recoveredAddr := crypto.PubkeyToAddress(*pubKey)
 	fromAddress := tx.inner.from()
 	if fromAddress == nil {
-		return fmt.Errorf("no from address")
+		return NewError(CodeInvalidSignature, "no from address")
 	}
 
 	if !bytes.Equal(fromAddress.Bytes(), recoveredAddr.Bytes()) {
-		return fmt.Errorf("signature does not match sender's public key")
+		return NewError(CodeInvalidSignature, "signature does not match sender's public key")
 	}
 
 	return nil
@@ -350,7 +356,7 @@ func (tx *Transaction) Validate() error {
 	}
 
 	if err := validateAddress(*tx.From()); err != nil {
-		return err
+		return ValidationErrors{NewFieldError("from", err, "")}
 	}
 
 	switch tx.Type() {
@@ -380,20 +386,20 @@ func (r *Transaction) validateRequestorId() error {
 	// Logic here to validate the requestor ID field
 	// match, _ := regexp.MatchString("^[0-9]+$", r.RequestorId)
 	// if !match {
-	// 	return errInvalidRequestorId
+	// 	return ErrInvalidRequestorId
 	// }
 	return nil
 }
 
 // validateAddress validates that the given string is a valid Ethereum address.
-func validateAddress(addr common.Address) error {
+func validateAddress(addr common.Address) *Error {
 	if !common.IsHexAddress(addr.String()) {
-		return errInvalidAddress
+		return ErrInvalidAddress
 	}
 
 	// check if the address has mixed case, then it should be checksum
 	if hasMixedCase(addr.String()) && !common.IsHexAddress(addr.Hex()) {
-		return errInvalidChecksum
+		return ErrInvalidChecksum
 	}
 
 	return nil
@@ -209,3 +209,20 @@ func publicKeyFromBytes(pubKeyBytes []byte) (*ecdsa.PublicKey, error) {
 
 	return pubKey, nil
 }
+
+// RecoverAddress returns the address that produced the 65 byte [R || S || V]
+// signature over hash.
+func RecoverAddress(hash common.Hash, sig []byte) (common.Address, error) {
+	if len(sig) != SignatureLength {
+		return common.Address{}, ErrInvalidSig
+	}
+	pub, err := crypto.Ecrecover(hash[:], sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover public key: %v", err)
+	}
+	pubKey, err := crypto.UnmarshalPubkey(pub)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid public key: %v", err)
+	}
+	return common.BytesToAddress(crypto.PubkeyToAddress(*pubKey).Bytes()), nil
+}
@@ -690,3 +690,34 @@ func TestQuoteDataRLPEncodingDecoding(t *testing.T) {
 		t.Errorf("expected %s, got %s", quoteData.QuoterId, quoteDataDecoded.QuoterId)
 	}
 }
+
+func TestSignableDataValidate(t *testing.T) {
+	data := &SignableData{
+		RequestorId:     "1",
+		BaseTokenAmount: big.NewInt(-1),
+		BaseToken:       &BaseToken{Symbol: "MKR", Decimals: 18},
+		Recipients:      []common.Address{{}},
+	}
+	err := data.Validate()
+
+	var invalid ValidationErrors
+	require.ErrorAs(t, err, &invalid)
+	fields := make(map[string]ErrorCode)
+	for _, fieldErr := range invalid {
+		fields[fieldErr.Field] = fieldErr.Code
+	}
+	assert.Equal(t, map[string]ErrorCode{
+		"baseTokenAmount": CodeInvalidAmount,
+		"quoteToken":      CodeMissingField,
+		"rfqDurationMs":   CodeMissingField,
+		"recipients":      CodeInvalidRecipient,
+	}, fields)
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+	assert.Equal(t, "baseTokenAmount must be positive; quoteToken is required; rfqDurationMs is required; recipients must not contain the zero address", err.Error())
+
+	data.BaseTokenAmount = big.NewInt(1)
+	data.QuoteToken = data.BaseToken
+	data.RFQDurationMs = 5000
+	data.Recipients = nil
+	assert.NoError(t, data.Validate())
+}
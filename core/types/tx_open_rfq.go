@@ -21,6 +21,10 @@ const (
 	RFQStatusClosed  RFQStatus = "CLOSED"
 	RFQStatusMatched RFQStatus = "MATCHED"
 	RFQStatusSettled RFQStatus = "SETTLED"
+
+	// RFQStatusRequested is the status of an RFQ request no validator has
+	// opened yet
+	RFQStatusRequested RFQStatus = "REQUESTED"
 )
 
 type RFQData struct {
@@ -32,6 +36,32 @@ type RFQData struct {
 	SettlementContract common.Address `json:"settlementContract"`
 	MatchingContract   common.Address `json:"matchingContract"`
 	Status             RFQStatus      `json:"status"`
+	// QuotesRoot is the Merkle root of the quote hashes, set when the auction closes
+	QuotesRoot common.Hash `json:"quotesRoot"`
+	// Recipients are the only market makers allowed to quote on a private
+	// RFQ, resolved by the validator from the request's recipients and dealer
+	// group. Empty for public RFQs.
+	Recipients []common.Address `json:"recipients,omitempty"`
+}
+
+var ErrNotRecipient = errors.New("quoter is not a recipient of this private RFQ")
+
+// IsPrivate reports whether only the recipients may quote on the RFQ.
+func (d *RFQData) IsPrivate() bool {
+	return len(d.Recipients) > 0
+}
+
+// CheckQuoter reports whether quoter may quote on the RFQ.
+func (d *RFQData) CheckQuoter(quoter common.Address) error {
+	if !d.IsPrivate() {
+		return nil
+	}
+	for _, recipient := range d.Recipients {
+		if recipient == quoter {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrNotRecipient, quoter.Hex())
 }
 
 func (d RFQData) String() string {
@@ -54,8 +84,10 @@ func (d RFQData) String() string {
 	)
 }
 
+// Close closes the auction and commits to the quotes it received.
 func (d *RFQData) Close() {
 	d.Status = RFQStatusClosed
+	d.QuotesRoot = d.QuotesMerkleRoot()
 }
 func (d *RFQData) Matched() {
 	d.Status = RFQStatusMatched
@@ -174,7 +206,7 @@ func (tx *OpenRFQ) from() *common.Address { return &tx.From }
 func (tx *OpenRFQ) txType() byte          { return OpenRFQTxType }
 
 func (tx *OpenRFQ) data() []byte {
-	txDataBytes, err := tx.Data.ToBytes()
+	txDataBytes, err := tx.Data.signingData().ToBytes()
 	if err != nil {
 		panic(fmt.Sprintf("failed to marshal tx data: %v", err))
 	}
@@ -271,8 +303,10 @@ func (r *OpenRFQ) DecodeRLP(s *rlp.Stream) error {
 }
 
 func (rfqData *RFQData) FromInterfaces(data []interface{}) error {
-	if len(data) != 8 {
-		return fmt.Errorf("wrong number of elements: expected 7, got %d", len(data))
+	// the quotes root is only present once the auction has closed or when
+	// followed by the recipients of a private RFQ
+	if len(data) < 8 || len(data) > 10 {
+		return fmt.Errorf("wrong number of elements: expected 8 to 10, got %d", len(data))
 	}
 
 	rfqTxHashBytes, ok := data[0].([]byte)
@@ -375,6 +409,22 @@ func (rfqData *RFQData) FromInterfaces(data []interface{}) error {
 		return fmt.Errorf("invalid status type %T", data[7])
 	}
 	status := string(statusBytes) // convert bytes to string
+
+	var quotesRoot common.Hash
+	if len(data) > 8 {
+		quotesRootBytes, ok := data[8].([]byte)
+		if !ok || len(quotesRootBytes) != common.HashLength {
+			return fmt.Errorf("invalid quotesRoot %v", data[8])
+		}
+		copy(quotesRoot[:], quotesRootBytes)
+	}
+	var recipients []common.Address
+	if len(data) > 9 {
+		var err error
+		if recipients, err = addressesFromInterface(data[9]); err != nil {
+			return fmt.Errorf("invalid recipients: %w", err)
+		}
+	}
 	rfqData.RFQTxHash = rfqTxHash
 	rfqData.RFQRequest = rfqRequest
 	rfqData.RFQStartTime = int64(rfqStartTime)
@@ -383,6 +433,8 @@ func (rfqData *RFQData) FromInterfaces(data []interface{}) error {
 	rfqData.SettlementContract = settlementContractAddress
 	rfqData.MatchingContract = matchingContractAddress
 	rfqData.Status = RFQStatus(status)
+	rfqData.QuotesRoot = quotesRoot
+	rfqData.Recipients = recipients
 
 	return nil
 }
@@ -425,6 +477,8 @@ func (src *RFQData) EncodeRLP(w io.Writer) error {
 		SettlementContract common.Address
 		MatchingContract   common.Address
 		Status             RFQStatus
+		QuotesRoot         common.Hash      `rlp:"optional"`
+		Recipients         []common.Address `rlp:"optional"`
 	}{
 		RFQTxHash:          src.RFQTxHash,
 		RFQRequest:         src.RFQRequest,
@@ -434,6 +488,8 @@ func (src *RFQData) EncodeRLP(w io.Writer) error {
 		SettlementContract: src.SettlementContract,
 		MatchingContract:   src.MatchingContract,
 		Status:             src.Status,
+		QuotesRoot:         src.QuotesRoot,
+		Recipients:         src.Recipients,
 	}
 	return rlp.Encode(w, &dataToEncode)
 }
@@ -448,6 +504,8 @@ func (src *RFQData) DecodeRLP(s *rlp.Stream) error {
 		SettlementContract common.Address
 		MatchingContract   common.Address
 		Status             RFQStatus
+		QuotesRoot         common.Hash      `rlp:"optional"`
+		Recipients         []common.Address `rlp:"optional"`
 	}
 
 	if err := s.Decode(&dataToDecode); err != nil {
@@ -462,6 +520,8 @@ func (src *RFQData) DecodeRLP(s *rlp.Stream) error {
 	src.SettlementContract = dataToDecode.SettlementContract
 	src.MatchingContract = dataToDecode.MatchingContract
 	src.Status = dataToDecode.Status
+	src.QuotesRoot = dataToDecode.QuotesRoot
+	src.Recipients = dataToDecode.Recipients
 	return nil
 }
 
@@ -474,6 +534,8 @@ func (src *RFQData) deepCopy() (*RFQData, error) {
 		SettlementContract: src.SettlementContract, // Address is a value type
 		MatchingContract:   src.MatchingContract,
 		Status:             src.Status, // Address is a value type
+		QuotesRoot:         src.QuotesRoot,
+		Recipients:         append([]common.Address(nil), src.Recipients...),
 	}
 
 	// Deep copy the slices// Deep copy the slices
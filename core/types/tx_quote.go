@@ -3,17 +3,16 @@ package types
 import (
 	"bytes"
 	"encoding/binary"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"log"
 	"math/big"
-	"strings"
 
 	"github.com/OCAX-labs/rfqrelayer/common"
 	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
@@ -27,6 +26,13 @@ type QuoteData struct {
 	BidPrice             *big.Int                `json:"bidPrice"`
 	AskPrice             *big.Int                `json:"askPrice"`
 	EncryptionPublicKeys []*cryptoocax.PublicKey `json:"encryptionPublicKeys"`
+	// EncryptedQuote holds the bid and ask prices sealed to the validators'
+	// threshold key - see SealPrices. Empty for plaintext quotes.
+	EncryptedQuote hexutil.Bytes `json:"encryptedQuote,omitempty"`
+	// DecryptionShares are the validators' decryption shares the sealed
+	// prices were revealed with, kept so the reveal can be checked - see
+	// VerifyReveal. Like the revealed prices they are not signed.
+	DecryptionShares []*threshold.DecryptionShare `json:"decryptionShares,omitempty"`
 }
 
 type Quote struct {
@@ -71,11 +77,6 @@ func (tx *Quote) UnmarshalJSON(data []byte) error {
 		S    *big.Int        `json:"s"`
 	}
 
-	_, err := hex.DecodeString(strings.TrimPrefix(string(data), "0x"))
-	if err != nil {
-		log.Fatal(err)
-	}
-
 	var quoteJSON QuoteJSON
 	if err := json.Unmarshal(data, &quoteJSON); err != nil {
 		return err
@@ -163,8 +164,8 @@ func (q *Quote) FromInterfaces(data []interface{}) error {
 	if !ok {
 		return fmt.Errorf("invalid v type %T", data[2])
 	}
-	if len(vBytes) != 1 {
-		return fmt.Errorf("incorrect length for v, expected %d, got %d", 1, len(vBytes))
+	if len(vBytes) > 1 {
+		return fmt.Errorf("incorrect length for v, expected at most %d, got %d", 1, len(vBytes))
 	}
 	q.V = new(big.Int).SetBytes(vBytes)
 
@@ -172,8 +173,8 @@ func (q *Quote) FromInterfaces(data []interface{}) error {
 	if !ok {
 		return fmt.Errorf("invalid r type %T", data[3])
 	}
-	if len(rBytes) != common.HashLength {
-		return fmt.Errorf("incorrect length for r, expected %d, got %d", common.HashLength, len(rBytes))
+	if len(rBytes) > common.HashLength {
+		return fmt.Errorf("incorrect length for r, expected at most %d, got %d", common.HashLength, len(rBytes))
 	}
 	q.R = new(big.Int).SetBytes(rBytes)
 
@@ -181,8 +182,8 @@ func (q *Quote) FromInterfaces(data []interface{}) error {
 	if !ok {
 		return fmt.Errorf("invalid s type %T", data[4])
 	}
-	if len(sBytes) != common.HashLength {
-		return fmt.Errorf("incorrect length for s, expected %d, got %d", common.HashLength, len(sBytes))
+	if len(sBytes) > common.HashLength {
+		return fmt.Errorf("incorrect length for s, expected at most %d, got %d", common.HashLength, len(sBytes))
 	}
 	q.S = new(big.Int).SetBytes(sBytes)
 
@@ -190,8 +191,10 @@ func (q *Quote) FromInterfaces(data []interface{}) error {
 }
 
 func (qd *QuoteData) FromInterfaces(data []interface{}) error {
-	if len(data) != 8 {
-		return fmt.Errorf("wrong number of elements: expected 8, got %d", len(data))
+	// the encrypted quote and, once revealed, its decryption shares are
+	// optional trailing elements
+	if len(data) < 9 || len(data) > 11 {
+		return fmt.Errorf("wrong number of elements: expected 9 to 11, got %d", len(data))
 	}
 
 	quoterIdBytes, ok := data[0].([]byte)
@@ -297,9 +300,58 @@ func (qd *QuoteData) FromInterfaces(data []interface{}) error {
 	qd.AskPrice = askPrice
 	qd.EncryptionPublicKeys = encryptionPublicKeys
 
+	if len(data) >= 10 {
+		encryptedQuote, ok := data[9].([]byte)
+		if !ok {
+			return fmt.Errorf("invalid encryptedQuote type %T", data[9])
+		}
+		qd.EncryptedQuote = encryptedQuote
+	}
+
+	if len(data) == 11 {
+		sharesInterface, ok := data[10].([]interface{})
+		if !ok {
+			return fmt.Errorf("invalid decryptionShares type %T", data[10])
+		}
+		for _, shareInterface := range sharesInterface {
+			share, err := decryptionShareFromInterface(shareInterface)
+			if err != nil {
+				return err
+			}
+			qd.DecryptionShares = append(qd.DecryptionShares, share)
+		}
+	}
+
 	return nil
 }
 
+func decryptionShareFromInterface(data interface{}) (*threshold.DecryptionShare, error) {
+	fields, ok := data.([]interface{})
+	if !ok || len(fields) != 3 {
+		return nil, fmt.Errorf("invalid decryptionShare %T", data)
+	}
+	indexBytes, ok := fields[0].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("invalid decryptionShare index type %T", fields[0])
+	}
+	if len(indexBytes) > 8 {
+		return nil, errors.New("invalid decryptionShare index length")
+	}
+	point, ok := fields[1].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("invalid decryptionShare point type %T", fields[1])
+	}
+	proof, ok := fields[2].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("invalid decryptionShare proof type %T", fields[2])
+	}
+	return &threshold.DecryptionShare{
+		Index: new(big.Int).SetBytes(indexBytes).Uint64(),
+		Point: point,
+		Proof: proof,
+	}, nil
+}
+
 func (q *Quote) DataString() string {
 	dataBytes, err := json.Marshal(q.Data)
 	if err != nil {
@@ -385,7 +437,7 @@ func (tx *Quote) copy() TxData {
 func (tx *Quote) from() *common.Address { return &tx.From }
 func (tx *Quote) txType() byte          { return QuoteTxType }
 func (tx *Quote) data() []byte {
-	txDataBytes, err := tx.Data.ToBytes()
+	txDataBytes, err := tx.Data.signingData().ToBytes()
 	if err != nil {
 		panic(fmt.Sprintf("failed to encode tx data: %v", err))
 	}
@@ -413,7 +465,7 @@ func (tx *Quote) referenceTxHash() common.Hash {
 }
 
 func (qd *QuoteData) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, []interface{}{
+	fields := []interface{}{
 		qd.QuoterId,
 		qd.RFQTxHash,
 		qd.QuoteExpiryTime,
@@ -423,7 +475,16 @@ func (qd *QuoteData) EncodeRLP(w io.Writer) error {
 		qd.BidPrice,
 		qd.AskPrice,
 		qd.EncryptionPublicKeys,
-	})
+	}
+	// only sealed quotes carry the trailing element so that the encoding (and
+	// hash) of plaintext quotes is unchanged
+	if len(qd.EncryptedQuote) > 0 {
+		fields = append(fields, qd.EncryptedQuote)
+		if len(qd.DecryptionShares) > 0 {
+			fields = append(fields, qd.DecryptionShares)
+		}
+	}
+	return rlp.Encode(w, fields)
 }
 
 func (qd *QuoteData) DecodeRLP(s *rlp.Stream) error {
@@ -437,6 +498,8 @@ func (qd *QuoteData) DecodeRLP(s *rlp.Stream) error {
 		BidPrice             *big.Int
 		AskPrice             *big.Int
 		EncryptionPublicKeys []*cryptoocax.PublicKey
+		EncryptedQuote       []byte                       `rlp:"optional"`
+		DecryptionShares     []*threshold.DecryptionShare `rlp:"optional"`
 	}
 	if err := s.Decode(&dataToDecode); err != nil {
 		return err
@@ -451,6 +514,8 @@ func (qd *QuoteData) DecodeRLP(s *rlp.Stream) error {
 	qd.BidPrice = dataToDecode.BidPrice
 	qd.AskPrice = dataToDecode.AskPrice
 	qd.EncryptionPublicKeys = dataToDecode.EncryptionPublicKeys
+	qd.EncryptedQuote = dataToDecode.EncryptedQuote
+	qd.DecryptionShares = dataToDecode.DecryptionShares
 	return nil
 }
 
@@ -462,7 +527,40 @@ func (tx *QuoteData) ToBytes() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Validate checks the fields of a quote, reporting every invalid field as a
+// ValidationErrors.
 func (q *QuoteData) Validate() error {
+	var errs ValidationErrors
+	if q.BaseToken == nil {
+		errs.add("baseToken", ErrMissingField, "baseToken is required")
+	}
+	if q.QuoteToken == nil {
+		errs.add("quoteToken", ErrMissingField, "quoteToken is required")
+	}
+	if q.BaseTokenAmount == nil || q.BaseTokenAmount.Sign() <= 0 {
+		errs.add("baseTokenAmount", ErrInvalidAmount, "baseTokenAmount must be positive")
+	}
+	return errs.err()
+}
+
+var (
+	ErrQuoteTokenMismatch = errors.New("quote token does not match the RFQ")
+	ErrQuoteAmount        = errors.New("quote amount exceeds the RFQ amount")
+)
+
+// CheckRFQ reports whether the quote answers rfq: both tokens, including their
+// decimals, must be the ones requested and the quoted size, in base token
+// units, may not exceed the requested size.
+func (q *QuoteData) CheckRFQ(rfq *SignableData) error {
+	if !q.BaseToken.Equal(rfq.BaseToken) {
+		return fmt.Errorf("%w: base token %s, requested %s", ErrQuoteTokenMismatch, q.BaseToken, rfq.BaseToken)
+	}
+	if !q.QuoteToken.Equal(rfq.QuoteToken) {
+		return fmt.Errorf("%w: quote token %s, requested %s", ErrQuoteTokenMismatch, q.QuoteToken, rfq.QuoteToken)
+	}
+	if rfq.BaseTokenAmount != nil && q.BaseTokenAmount.Cmp(rfq.BaseTokenAmount) > 0 {
+		return fmt.Errorf("%w: %s > %s", ErrQuoteAmount, q.BaseTokenAmount, rfq.BaseTokenAmount)
+	}
 	return nil
 }
 
@@ -477,6 +575,8 @@ func (q *QuoteData) deepCopy() (*QuoteData, error) {
 		BidPrice:             q.BidPrice,
 		AskPrice:             q.AskPrice,
 		EncryptionPublicKeys: []*cryptoocax.PublicKey{},
+		EncryptedQuote:       common.CopyBytes(q.EncryptedQuote),
+		DecryptionShares:     append([]*threshold.DecryptionShare(nil), q.DecryptionShares...),
 	}
 	cpy.EncryptionPublicKeys = make([]*cryptoocax.PublicKey, len(q.EncryptionPublicKeys))
 	copy(cpy.EncryptionPublicKeys, q.EncryptionPublicKeys)
@@ -45,6 +45,15 @@ func (t Token) Validate() bool {
 	return true
 }
 
+// Equal reports whether t and other are the same token with the same
+// decimals. Symbols are compared case insensitively.
+func (t *Token) Equal(other *Token) bool {
+	if t == nil || other == nil {
+		return t == other
+	}
+	return t.Address == other.Address && t.Decimals == other.Decimals && strings.EqualFold(t.Symbol, other.Symbol)
+}
+
 func (t *Token) FromInterfaces(data []interface{}) error {
 	if len(data) != 3 {
 		return fmt.Errorf("invalid data length %d", len(data))
@@ -96,6 +105,15 @@ type SignableData struct {
 	BaseToken       *BaseToken  `json:"baseToken"`
 	QuoteToken      *QuoteToken `json:"quoteToken"`
 	RFQDurationMs   uint64      `json:"rfqDurationMs"`
+	// Recipients and DealerGroup make the RFQ private to the named market
+	// makers, the RFQ is public when both are empty
+	Recipients  []common.Address `json:"recipients,omitempty" rlp:"optional"`
+	DealerGroup string           `json:"dealerGroup,omitempty" rlp:"optional"`
+}
+
+// IsPrivate reports whether the RFQ is only open to chosen market makers.
+func (d *SignableData) IsPrivate() bool {
+	return len(d.Recipients) > 0 || d.DealerGroup != ""
 }
 
 func (t *Token) EncodeRLP(w io.Writer) error {
@@ -159,7 +177,10 @@ func (t *Token) UnmarshalJSON(input []byte) error {
 		return err
 	}
 	if !strings.HasPrefix(tokenJson.Address, "0x") || len(tokenJson.Address) != 42 {
-		return fmt.Errorf("invalid ethereum address: %s", tokenJson.Address)
+		return fmt.Errorf("%w: %s", ErrInvalidAddress, tokenJson.Address)
+	}
+	if err := validateChecksum(tokenJson.Address); err != nil {
+		return err
 	}
 
 	t.Address = common.HexToAddress(tokenJson.Address)
@@ -168,6 +189,19 @@ func (t *Token) UnmarshalJSON(input []byte) error {
 	return nil
 }
 
+// validateChecksum rejects mixed case addresses that are not EIP-55
+// checksummed. All lower or all upper case addresses carry no checksum.
+func validateChecksum(addr string) error {
+	hex := addr[2:]
+	if hex == strings.ToLower(hex) || hex == strings.ToUpper(hex) {
+		return nil
+	}
+	if common.HexToAddress(addr).Hex() != addr {
+		return fmt.Errorf("%w: %s", ErrInvalidChecksum, addr)
+	}
+	return nil
+}
+
 func (d *SignableData) MarshalJSON() ([]byte, error) {
 	data := struct {
 		RequestorId     string     `json:"requestorId"`
@@ -175,12 +209,17 @@ func (d *SignableData) MarshalJSON() ([]byte, error) {
 		BaseToken       *BaseToken `json:"baseToken"`
 		QuoteToken      *BaseToken `json:"quoteToken"`
 		RFQDurationMs   uint64     `json:"rfqDurationMs"`
+
+		Recipients  []common.Address `json:"recipients,omitempty"`
+		DealerGroup string           `json:"dealerGroup,omitempty"`
 	}{
 		RequestorId:     d.RequestorId,
 		BaseTokenAmount: d.BaseTokenAmount,
 		BaseToken:       d.BaseToken,
 		QuoteToken:      d.QuoteToken,
 		RFQDurationMs:   d.RFQDurationMs,
+		Recipients:      d.Recipients,
+		DealerGroup:     d.DealerGroup,
 	}
 
 	// Marshal the struct to JSON without escaping
@@ -199,6 +238,9 @@ func (d *SignableData) UnmarshalJSON(input []byte) error {
 		BaseToken       *BaseToken `json:"baseToken"`
 		QuoteToken      *BaseToken `json:"quoteToken"`
 		RFQDurationMs   uint64     `json:"rfqDurationMs"`
+
+		Recipients  []common.Address `json:"recipients"`
+		DealerGroup string           `json:"dealerGroup"`
 	}
 	var signableDataJSON SignableDataJSON
 	if err := json.Unmarshal(input, &signableDataJSON); err != nil {
@@ -210,6 +252,8 @@ func (d *SignableData) UnmarshalJSON(input []byte) error {
 	d.BaseToken = signableDataJSON.BaseToken
 	d.QuoteToken = signableDataJSON.QuoteToken
 	d.RFQDurationMs = signableDataJSON.RFQDurationMs
+	d.Recipients = signableDataJSON.Recipients
+	d.DealerGroup = signableDataJSON.DealerGroup
 	return nil
 }
 
@@ -275,23 +319,34 @@ func (s *SignableData) JSON() ([]byte, error) {
 	return buffer.Bytes(), err
 }
 
+// Validate checks the fields of an RFQ request, reporting every invalid
+// field as a ValidationErrors.
 func (s *SignableData) Validate() error {
+	var errs ValidationErrors
 	if s.RequestorId == "" {
-		return errors.New("requestorId is required")
+		errs.add("requestorId", ErrMissingField, "requestorId is required")
 	}
 	if s.BaseTokenAmount == nil {
-		return errors.New("baseTokenAmount is required")
+		errs.add("baseTokenAmount", ErrMissingField, "baseTokenAmount is required")
+	} else if s.BaseTokenAmount.Sign() <= 0 {
+		errs.add("baseTokenAmount", ErrInvalidAmount, "baseTokenAmount must be positive")
 	}
 	if s.BaseToken == nil {
-		return errors.New("baseToken is required")
+		errs.add("baseToken", ErrMissingField, "baseToken is required")
 	}
 	if s.QuoteToken == nil {
-		return errors.New("quoteToken is required")
+		errs.add("quoteToken", ErrMissingField, "quoteToken is required")
 	}
 	if s.RFQDurationMs == 0 {
-		return errors.New("rfqDurationMs is required")
+		errs.add("rfqDurationMs", ErrMissingField, "rfqDurationMs is required")
 	}
-	return nil
+	for _, recipient := range s.Recipients {
+		if recipient == (common.Address{}) {
+			errs.add("recipients", ErrInvalidRecipient, "recipients must not contain the zero address")
+			break
+		}
+	}
+	return errs.err()
 }
 
 func NewRFQRequest(from common.Address, data *SignableData) *RFQRequest {
@@ -449,7 +504,8 @@ func (s *SignableData) ToHex() (hexutil.Bytes, error) {
 }
 
 func (s *SignableData) FromInterfaces(data []interface{}) error {
-	if len(data) != 5 {
+	// recipients and the dealer group are only present for private RFQs
+	if len(data) < 5 || len(data) > 7 {
 		return fmt.Errorf("invalid data length %d", len(data))
 	}
 
@@ -507,9 +563,42 @@ func (s *SignableData) FromInterfaces(data []interface{}) error {
 	s.QuoteToken = quoteToken
 	s.RFQDurationMs = rfqDuration
 
+	s.Recipients = nil
+	if len(data) > 5 {
+		recipients, err := addressesFromInterface(data[5])
+		if err != nil {
+			return fmt.Errorf("invalid recipients: %w", err)
+		}
+		s.Recipients = recipients
+	}
+	s.DealerGroup = ""
+	if len(data) > 6 {
+		dealerGroupBytes, ok := data[6].([]byte)
+		if !ok {
+			return fmt.Errorf("invalid dealerGroup type %T", data[6])
+		}
+		s.DealerGroup = string(dealerGroupBytes)
+	}
+
 	return nil
 }
 
+func addressesFromInterface(data interface{}) ([]common.Address, error) {
+	items, ok := data.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid address list type %T", data)
+	}
+	var addresses []common.Address
+	for _, item := range items {
+		addrBytes, ok := item.([]byte)
+		if !ok || len(addrBytes) != common.AddressLength {
+			return nil, fmt.Errorf("invalid address %v", item)
+		}
+		addresses = append(addresses, common.BytesToAddress(addrBytes))
+	}
+	return addresses, nil
+}
+
 func (src *SignableData) deepCopy() (SignableData, error) {
 	buf := new(bytes.Buffer)
 	enc := gob.NewEncoder(buf)
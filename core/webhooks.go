@@ -0,0 +1,80 @@
+package core
+
+import (
+	"fmt"
+)
+
+// deadLetterKey keys a dead letter by its webhook so a webhook's letters can
+// be listed with a prefix scan.
+func deadLetterKey(webhookID, deliveryID string) []byte {
+	return []byte(webhookID + "/" + deliveryID)
+}
+
+// WriteWebhook stores an encoded webhook registration.
+func (bc *Blockchain) WriteWebhook(id string, data []byte) error {
+	if err := bc.webhooksTable.Put([]byte(id), data); err != nil {
+		return fmt.Errorf("error writing webhook to kv store tables: %s", err.Error())
+	}
+	return nil
+}
+
+// DeleteWebhook removes a webhook registration and its dead letters.
+func (bc *Blockchain) DeleteWebhook(id string) error {
+	it := bc.deadLettersTable.NewIterator([]byte(id+"/"), nil)
+	var keys [][]byte
+	for it.Next() {
+		keys = append(keys, append([]byte(nil), it.Key()...))
+	}
+	it.Release()
+	if err := it.Error(); err != nil {
+		return err
+	}
+
+	batch := bc.deadLettersTable.NewBatch()
+	for _, key := range keys {
+		if err := batch.Delete(key); err != nil {
+			return err
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	return bc.webhooksTable.Delete([]byte(id))
+}
+
+// GetWebhooks returns the stored webhook registrations.
+func (bc *Blockchain) GetWebhooks() ([][]byte, error) {
+	it := bc.webhooksTable.NewIterator(nil, nil)
+	defer it.Release()
+
+	var webhooks [][]byte
+	for it.Next() {
+		webhooks = append(webhooks, append([]byte(nil), it.Value()...))
+	}
+	return webhooks, it.Error()
+}
+
+// WriteDeadLetter stores an event that could not be delivered to a webhook.
+func (bc *Blockchain) WriteDeadLetter(webhookID, deliveryID string, data []byte) error {
+	if err := bc.deadLettersTable.Put(deadLetterKey(webhookID, deliveryID), data); err != nil {
+		return fmt.Errorf("error writing dead letter to kv store tables: %s", err.Error())
+	}
+	return nil
+}
+
+// DeleteDeadLetter removes a dead letter once it has been redelivered.
+func (bc *Blockchain) DeleteDeadLetter(webhookID, deliveryID string) error {
+	return bc.deadLettersTable.Delete(deadLetterKey(webhookID, deliveryID))
+}
+
+// GetDeadLetters returns the dead letters of a webhook.
+func (bc *Blockchain) GetDeadLetters(webhookID string) ([][]byte, error) {
+	it := bc.deadLettersTable.NewIterator([]byte(webhookID+"/"), nil)
+	defer it.Release()
+
+	var letters [][]byte
+	for it.Next() {
+		letters = append(letters, append([]byte(nil), it.Value()...))
+	}
+	return letters, it.Error()
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookStore(t *testing.T) {
+	bc, teardown := newBlockchainWithGenesis(t, dbPath+"webhooks")
+	defer teardown()
+
+	require.NoError(t, bc.WriteWebhook("a", []byte("hook a")))
+	require.NoError(t, bc.WriteWebhook("ab", []byte("hook ab")))
+	require.NoError(t, bc.WriteDeadLetter("a", "a-1", []byte("a-1")))
+	require.NoError(t, bc.WriteDeadLetter("a", "a-2", []byte("a-2")))
+	require.NoError(t, bc.WriteDeadLetter("ab", "ab-1", []byte("ab-1")))
+
+	// a webhook's dead letters don't include those of webhooks whose ID it
+	// prefixes
+	letters, err := bc.GetDeadLetters("a")
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a-1"), []byte("a-2")}, letters)
+
+	require.NoError(t, bc.DeleteDeadLetter("a", "a-1"))
+	letters, err = bc.GetDeadLetters("a")
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a-2")}, letters)
+
+	// deleting a webhook drops its dead letters
+	require.NoError(t, bc.DeleteWebhook("a"))
+	webhooks, err := bc.GetWebhooks()
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("hook ab")}, webhooks)
+	letters, err = bc.GetDeadLetters("a")
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+	letters, err = bc.GetDeadLetters("ab")
+	require.NoError(t, err)
+	assert.Len(t, letters, 1)
+}
@@ -0,0 +1,98 @@
+package threshold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var ErrInvalidKeyShare = errors.New("invalid key share")
+
+// keyShareJSON is the file format of a key share, with hex encoded keys.
+type keyShareJSON struct {
+	Index            uint64            `json:"index"`
+	Threshold        int               `json:"threshold"`
+	Secret           *hexutil.Big      `json:"secret"`
+	PublicKey        hexutil.Bytes     `json:"publicKey"`
+	VerificationKeys map[string]string `json:"verificationKeys"`
+}
+
+// WriteKeyShare saves ks to path, readable by its owner only.
+func WriteKeyShare(path string, ks *KeyShare) error {
+	out := keyShareJSON{
+		Index:            ks.Index,
+		Threshold:        ks.Threshold,
+		Secret:           (*hexutil.Big)(ks.Secret),
+		PublicKey:        ks.PublicKey.Bytes(),
+		VerificationKeys: make(map[string]string, len(ks.VerificationKeys)),
+	}
+	for index, vk := range ks.VerificationKeys {
+		out.VerificationKeys[strconv.FormatUint(index, 10)] = hexutil.Encode(vk.Bytes())
+	}
+	data, err := json.MarshalIndent(&out, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// ReadKeyShare loads a key share saved by WriteKeyShare and checks that its
+// secret matches its verification key.
+func ReadKeyShare(path string) (*KeyShare, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var in keyShareJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeyShare, err)
+	}
+	if in.Index == 0 || in.Secret == nil {
+		return nil, fmt.Errorf("%w: missing index or secret", ErrInvalidKeyShare)
+	}
+	if in.Threshold < 1 || in.Threshold > len(in.VerificationKeys) {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKeyShare, ErrInvalidThreshold)
+	}
+	pub, err := PublicKeyFromBytes(in.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("%w: public key: %v", ErrInvalidKeyShare, err)
+	}
+
+	ks := &KeyShare{
+		Index:            in.Index,
+		Secret:           (*big.Int)(in.Secret),
+		Threshold:        in.Threshold,
+		PublicKey:        pub,
+		VerificationKeys: make(map[uint64]*PublicKey, len(in.VerificationKeys)),
+	}
+	for key, value := range in.VerificationKeys {
+		index, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || index == 0 {
+			return nil, fmt.Errorf("%w: verification key index %q", ErrInvalidKeyShare, key)
+		}
+		b, err := hexutil.Decode(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: verification key %d: %v", ErrInvalidKeyShare, index, err)
+		}
+		vk, err := PublicKeyFromBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("%w: verification key %d: %v", ErrInvalidKeyShare, index, err)
+		}
+		ks.VerificationKeys[index] = vk
+	}
+
+	own, ok := ks.VerificationKeys[ks.Index]
+	if !ok {
+		return nil, fmt.Errorf("%w: no verification key for share %d", ErrInvalidKeyShare, ks.Index)
+	}
+	X, Y := curve.ScalarBaseMult(ks.Secret.Bytes())
+	if X.Cmp(own.X) != 0 || Y.Cmp(own.Y) != 0 {
+		return nil, fmt.Errorf("%w: secret does not match verification key %d", ErrInvalidKeyShare, ks.Index)
+	}
+	return ks, nil
+}
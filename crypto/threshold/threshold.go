@@ -0,0 +1,363 @@
+// Package threshold implements a t-of-n threshold encryption scheme over the
+// secp256k1 curve that is used to seal quotes for the duration of an auction.
+//
+// A dealer splits a group secret x into n Shamir shares x_i and publishes the
+// group public key P = x·G. Quoters encrypt to P using an ephemeral key r
+// (R = r·G, S = r·P) and AES-GCM keyed with keccak256(S). Once the auction has
+// closed each validator releases a decryption share D_i = x_i·R and any t of
+// those shares can be combined with Lagrange interpolation in the exponent to
+// recover S = Σ λ_i·D_i without any single node ever learning x.
+//
+// Every share comes with a verification key V_i = x_i·G, and every decryption
+// share with a Chaum-Pedersen proof that log_G(V_i) = log_R(D_i), so a share
+// that was not computed with x_i is detected before it is combined.
+package threshold
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	ErrInvalidThreshold  = errors.New("threshold must be between 1 and the number of shares")
+	ErrNotEnoughShares   = errors.New("not enough decryption shares to reach the threshold")
+	ErrDuplicateShare    = errors.New("duplicate decryption share index")
+	ErrInvalidPoint      = errors.New("invalid curve point")
+	ErrInvalidCiphertext = errors.New("invalid ciphertext")
+	ErrDecryptionFailed  = errors.New("failed to decrypt ciphertext with the combined shares")
+	ErrInvalidShareIndex = errors.New("share index must be greater than zero")
+	ErrInvalidShare      = errors.New("decryption share does not match its verification key")
+)
+
+// curve is the secp256k1 curve shared with the rest of the relayer's keys.
+var curve = crypto.S256()
+
+// PublicKey is the group public key quotes are encrypted to.
+type PublicKey struct {
+	X, Y *big.Int
+}
+
+// Bytes returns the uncompressed encoding of the public key.
+func (pk *PublicKey) Bytes() []byte {
+	return crypto.FromECDSAPub(&ecdsa.PublicKey{Curve: curve, X: pk.X, Y: pk.Y})
+}
+
+// PublicKeyFromBytes decodes an uncompressed group public key.
+func PublicKeyFromBytes(b []byte) (*PublicKey, error) {
+	X, Y, err := unmarshalPoint(b)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{X: X, Y: Y}, nil
+}
+
+// KeyShare is a single validator's share of the group secret.
+type KeyShare struct {
+	Index     uint64     // 1-based evaluation point of the share polynomial
+	Secret    *big.Int   // f(Index)
+	Threshold int        // number of shares required to decrypt
+	PublicKey *PublicKey // group public key
+	// VerificationKeys are the public keys f(j)·G of every share j, by
+	// index, the decryption shares of the other validators are checked
+	// against
+	VerificationKeys map[uint64]*PublicKey
+}
+
+// DecryptionShare is a validator's contribution towards decrypting one
+// ciphertext.
+type DecryptionShare struct {
+	Index uint64
+	Point []byte // x_i·R, uncompressed
+	Proof []byte // c || z proving Point was computed with x_i
+}
+
+// Ciphertext is a sealed payload encrypted to the group public key.
+type Ciphertext struct {
+	Ephemeral []byte // R = r·G, uncompressed
+	Nonce     []byte
+	Data      []byte
+}
+
+// Bytes returns the RLP encoding of the ciphertext.
+func (ct *Ciphertext) Bytes() ([]byte, error) {
+	return rlp.EncodeToBytes(ct)
+}
+
+// CiphertextFromBytes decodes an RLP encoded ciphertext.
+func CiphertextFromBytes(b []byte) (*Ciphertext, error) {
+	ct := new(Ciphertext)
+	if err := rlp.DecodeBytes(b, ct); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCiphertext, err)
+	}
+	if _, _, err := unmarshalPoint(ct.Ephemeral); err != nil {
+		return nil, err
+	}
+	return ct, nil
+}
+
+// Deal generates a fresh group key and splits it into n shares, any t of which
+// are sufficient to decrypt. It is intended for trusted setups and tests; a
+// production deployment would replace it with a distributed key generation.
+func Deal(t, n int) (*PublicKey, []*KeyShare, error) {
+	if t < 1 || t > n {
+		return nil, nil, ErrInvalidThreshold
+	}
+	N := curve.Params().N
+
+	// f(z) = a_0 + a_1·z + ... + a_{t-1}·z^{t-1} with a_0 the group secret
+	coeffs := make([]*big.Int, t)
+	for i := range coeffs {
+		c, err := randScalar()
+		if err != nil {
+			return nil, nil, err
+		}
+		coeffs[i] = c
+	}
+
+	X, Y := curve.ScalarBaseMult(coeffs[0].Bytes())
+	pub := &PublicKey{X: X, Y: Y}
+
+	shares := make([]*KeyShare, n)
+	verificationKeys := make(map[uint64]*PublicKey, n)
+	for i := 0; i < n; i++ {
+		z := big.NewInt(int64(i + 1))
+		y := new(big.Int)
+		for j := len(coeffs) - 1; j >= 0; j-- {
+			y.Mul(y, z)
+			y.Add(y, coeffs[j])
+			y.Mod(y, N)
+		}
+		Vx, Vy := curve.ScalarBaseMult(y.Bytes())
+		verificationKeys[uint64(i+1)] = &PublicKey{X: Vx, Y: Vy}
+		shares[i] = &KeyShare{
+			Index:            uint64(i + 1),
+			Secret:           y,
+			Threshold:        t,
+			PublicKey:        pub,
+			VerificationKeys: verificationKeys,
+		}
+	}
+	return pub, shares, nil
+}
+
+// Encrypt seals msg to the group public key.
+func Encrypt(pub *PublicKey, msg []byte) (*Ciphertext, error) {
+	r, err := randScalar()
+	if err != nil {
+		return nil, err
+	}
+	Rx, Ry := curve.ScalarBaseMult(r.Bytes())
+	Sx, Sy := curve.ScalarMult(pub.X, pub.Y, r.Bytes())
+
+	ephemeral := marshalPoint(Rx, Ry)
+	aead, err := newAEAD(Sx, Sy)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &Ciphertext{
+		Ephemeral: ephemeral,
+		Nonce:     nonce,
+		Data:      aead.Seal(nil, nonce, msg, ephemeral),
+	}, nil
+}
+
+// DecryptionShare computes this share's contribution towards decrypting ct
+// with the proof that it was computed with the share's secret.
+func (ks *KeyShare) DecryptionShare(ct *Ciphertext) (*DecryptionShare, error) {
+	if ks.Index == 0 {
+		return nil, ErrInvalidShareIndex
+	}
+	Rx, Ry, err := unmarshalPoint(ct.Ephemeral)
+	if err != nil {
+		return nil, err
+	}
+	Dx, Dy := curve.ScalarMult(Rx, Ry, ks.Secret.Bytes())
+
+	// Chaum-Pedersen: commit to k·G and k·R, answer the challenge c with
+	// z = k + c·x_i
+	k, err := randScalar()
+	if err != nil {
+		return nil, err
+	}
+	Vx, Vy := curve.ScalarBaseMult(ks.Secret.Bytes())
+	A1x, A1y := curve.ScalarBaseMult(k.Bytes())
+	A2x, A2y := curve.ScalarMult(Rx, Ry, k.Bytes())
+	c := challenge(Vx, Vy, Rx, Ry, Dx, Dy, A1x, A1y, A2x, A2y)
+	N := curve.Params().N
+	z := new(big.Int).Mul(c, ks.Secret)
+	z.Add(z, k).Mod(z, N)
+
+	proof := make([]byte, 64)
+	c.FillBytes(proof[:32])
+	z.FillBytes(proof[32:])
+	return &DecryptionShare{Index: ks.Index, Point: marshalPoint(Dx, Dy), Proof: proof}, nil
+}
+
+// VerifyShare checks that share was computed for ct with the secret of the
+// share whose verification key is vk.
+func VerifyShare(ct *Ciphertext, share *DecryptionShare, vk *PublicKey) error {
+	if share.Index == 0 {
+		return ErrInvalidShareIndex
+	}
+	if vk == nil || len(share.Proof) != 64 {
+		return ErrInvalidShare
+	}
+	Rx, Ry, err := unmarshalPoint(ct.Ephemeral)
+	if err != nil {
+		return err
+	}
+	Dx, Dy, err := unmarshalPoint(share.Point)
+	if err != nil {
+		return err
+	}
+	N := curve.Params().N
+	c := new(big.Int).SetBytes(share.Proof[:32])
+	z := new(big.Int).SetBytes(share.Proof[32:])
+	if c.Sign() == 0 || z.Sign() == 0 || c.Cmp(N) >= 0 || z.Cmp(N) >= 0 {
+		return ErrInvalidShare
+	}
+
+	// A1 = z·G - c·V_i and A2 = z·R - c·D_i
+	negC := new(big.Int).Sub(N, c)
+	zGx, zGy := curve.ScalarBaseMult(z.Bytes())
+	cVx, cVy := curve.ScalarMult(vk.X, vk.Y, negC.Bytes())
+	A1x, A1y := curve.Add(zGx, zGy, cVx, cVy)
+	zRx, zRy := curve.ScalarMult(Rx, Ry, z.Bytes())
+	cDx, cDy := curve.ScalarMult(Dx, Dy, negC.Bytes())
+	A2x, A2y := curve.Add(zRx, zRy, cDx, cDy)
+
+	if challenge(vk.X, vk.Y, Rx, Ry, Dx, Dy, A1x, A1y, A2x, A2y).Cmp(c) != 0 {
+		return ErrInvalidShare
+	}
+	return nil
+}
+
+// challenge is the Fiat-Shamir challenge of a decryption share proof.
+func challenge(points ...*big.Int) *big.Int {
+	data := make([]byte, 0, len(points)*32)
+	for _, p := range points {
+		data = append(data, p.FillBytes(make([]byte, 32))...)
+	}
+	c := new(big.Int).SetBytes(crypto.Keccak256(data))
+	return c.Mod(c, curve.Params().N)
+}
+
+// Combine recovers the plaintext of ct from at least t decryption shares,
+// using the t with the lowest indices. The shares must have been checked
+// with VerifyShare.
+func Combine(ct *Ciphertext, shares []*DecryptionShare, t int) ([]byte, error) {
+	if t < 1 {
+		return nil, ErrInvalidThreshold
+	}
+	if len(shares) < t {
+		return nil, ErrNotEnoughShares
+	}
+	shares = append([]*DecryptionShare(nil), shares...)
+	sort.SliceStable(shares, func(i, j int) bool { return shares[i].Index < shares[j].Index })
+	shares = shares[:t]
+
+	indices := make([]*big.Int, len(shares))
+	seen := make(map[uint64]bool, len(shares))
+	for i, share := range shares {
+		if share.Index == 0 {
+			return nil, ErrInvalidShareIndex
+		}
+		if seen[share.Index] {
+			return nil, ErrDuplicateShare
+		}
+		seen[share.Index] = true
+		indices[i] = new(big.Int).SetUint64(share.Index)
+	}
+
+	var Sx, Sy *big.Int
+	for i, share := range shares {
+		Dx, Dy, err := unmarshalPoint(share.Point)
+		if err != nil {
+			return nil, err
+		}
+		lambda := lagrangeAtZero(indices, i)
+		Px, Py := curve.ScalarMult(Dx, Dy, lambda.Bytes())
+		if Sx == nil {
+			Sx, Sy = Px, Py
+		} else {
+			Sx, Sy = curve.Add(Sx, Sy, Px, Py)
+		}
+	}
+
+	aead, err := newAEAD(Sx, Sy)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, ct.Nonce, ct.Data, ct.Ephemeral)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+// lagrangeAtZero returns the Lagrange coefficient λ_i for interpolating the
+// share polynomial at zero from the given evaluation points.
+func lagrangeAtZero(indices []*big.Int, i int) *big.Int {
+	N := curve.Params().N
+	num := big.NewInt(1)
+	den := big.NewInt(1)
+	for j, xj := range indices {
+		if j == i {
+			continue
+		}
+		num.Mul(num, new(big.Int).Neg(xj))
+		num.Mod(num, N)
+		den.Mul(den, new(big.Int).Sub(indices[i], xj))
+		den.Mod(den, N)
+	}
+	den.ModInverse(den, N)
+	return num.Mul(num, den).Mod(num, N)
+}
+
+func newAEAD(Sx, Sy *big.Int) (cipher.AEAD, error) {
+	key := crypto.Keccak256(marshalPoint(Sx, Sy))
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func randScalar() (*big.Int, error) {
+	N := curve.Params().N
+	for {
+		k, err := rand.Int(rand.Reader, N)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() > 0 {
+			return k, nil
+		}
+	}
+}
+
+func marshalPoint(X, Y *big.Int) []byte {
+	return crypto.FromECDSAPub(&ecdsa.PublicKey{Curve: curve, X: X, Y: Y})
+}
+
+func unmarshalPoint(b []byte) (*big.Int, *big.Int, error) {
+	pub, err := crypto.UnmarshalPubkey(b)
+	if err != nil {
+		return nil, nil, ErrInvalidPoint
+	}
+	return pub.X, pub.Y, nil
+}
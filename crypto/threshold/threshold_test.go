@@ -0,0 +1,168 @@
+package threshold
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDealEncryptCombine(t *testing.T) {
+	pub, shares, err := Deal(3, 5)
+	require.NoError(t, err)
+	assert.Len(t, shares, 5)
+
+	msg := []byte("bid=1500,ask=1600")
+	ct, err := Encrypt(pub, msg)
+	require.NoError(t, err)
+
+	// any 3 of the 5 shares decrypt
+	subsets := [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}}
+	for _, subset := range subsets {
+		var decShares []*DecryptionShare
+		for _, i := range subset {
+			ds, err := shares[i].DecryptionShare(ct)
+			require.NoError(t, err)
+			decShares = append(decShares, ds)
+		}
+		plaintext, err := Combine(ct, decShares, 3)
+		require.NoError(t, err)
+		assert.Equal(t, msg, plaintext)
+	}
+}
+
+func TestCombineNotEnoughShares(t *testing.T) {
+	pub, shares, err := Deal(2, 3)
+	require.NoError(t, err)
+
+	ct, err := Encrypt(pub, []byte("sealed"))
+	require.NoError(t, err)
+
+	ds, err := shares[0].DecryptionShare(ct)
+	require.NoError(t, err)
+
+	_, err = Combine(ct, []*DecryptionShare{ds}, 2)
+	assert.ErrorIs(t, err, ErrNotEnoughShares)
+
+	// the same share twice does not count towards the threshold
+	_, err = Combine(ct, []*DecryptionShare{ds, ds}, 2)
+	assert.ErrorIs(t, err, ErrDuplicateShare)
+}
+
+func TestCombineWrongShares(t *testing.T) {
+	pub, _, err := Deal(2, 3)
+	require.NoError(t, err)
+	_, otherShares, err := Deal(2, 3)
+	require.NoError(t, err)
+
+	ct, err := Encrypt(pub, []byte("sealed"))
+	require.NoError(t, err)
+
+	var decShares []*DecryptionShare
+	for _, share := range otherShares[:2] {
+		ds, err := share.DecryptionShare(ct)
+		require.NoError(t, err)
+		decShares = append(decShares, ds)
+	}
+	_, err = Combine(ct, decShares, 2)
+	assert.ErrorIs(t, err, ErrDecryptionFailed)
+}
+
+func TestCiphertextEncoding(t *testing.T) {
+	pub, _, err := Deal(1, 1)
+	require.NoError(t, err)
+
+	ct, err := Encrypt(pub, []byte("sealed"))
+	require.NoError(t, err)
+
+	enc, err := ct.Bytes()
+	require.NoError(t, err)
+	decoded, err := CiphertextFromBytes(enc)
+	require.NoError(t, err)
+	assert.Equal(t, ct, decoded)
+
+	decodedPub, err := PublicKeyFromBytes(pub.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, pub.X, decodedPub.X)
+	assert.Equal(t, pub.Y, decodedPub.Y)
+
+	_, _, err = Deal(3, 2)
+	assert.ErrorIs(t, err, ErrInvalidThreshold)
+}
+
+func TestVerifyShare(t *testing.T) {
+	pub, shares, err := Deal(2, 3)
+	require.NoError(t, err)
+	ct, err := Encrypt(pub, []byte("sealed"))
+	require.NoError(t, err)
+	vks := shares[0].VerificationKeys
+
+	good, err := shares[1].DecryptionShare(ct)
+	require.NoError(t, err)
+	require.NoError(t, VerifyShare(ct, good, vks[good.Index]))
+	// a share is only valid for its own index
+	assert.ErrorIs(t, VerifyShare(ct, good, vks[1]), ErrInvalidShare)
+
+	// a share computed with another secret, or for another ciphertext, is
+	// detected
+	_, otherShares, err := Deal(2, 3)
+	require.NoError(t, err)
+	forged, err := otherShares[1].DecryptionShare(ct)
+	require.NoError(t, err)
+	assert.ErrorIs(t, VerifyShare(ct, forged, vks[forged.Index]), ErrInvalidShare)
+	otherCt, err := Encrypt(pub, []byte("other"))
+	require.NoError(t, err)
+	assert.ErrorIs(t, VerifyShare(otherCt, good, vks[good.Index]), ErrInvalidShare)
+
+	// a point swapped under a valid proof is detected
+	tampered := *good
+	tampered.Point = forged.Point
+	assert.ErrorIs(t, VerifyShare(ct, &tampered, vks[good.Index]), ErrInvalidShare)
+	tampered = *good
+	tampered.Proof = nil
+	assert.ErrorIs(t, VerifyShare(ct, &tampered, vks[good.Index]), ErrInvalidShare)
+}
+
+func TestCombineUsesLowestIndices(t *testing.T) {
+	pub, shares, err := Deal(2, 3)
+	require.NoError(t, err)
+	msg := []byte("sealed")
+	ct, err := Encrypt(pub, msg)
+	require.NoError(t, err)
+
+	var decShares []*DecryptionShare
+	for _, i := range []int{2, 1, 0} {
+		ds, err := shares[i].DecryptionShare(ct)
+		require.NoError(t, err)
+		decShares = append(decShares, ds)
+	}
+	// the share with the highest index is left out whatever the order
+	decShares[0].Point = decShares[1].Point
+	plaintext, err := Combine(ct, decShares, 2)
+	require.NoError(t, err)
+	assert.Equal(t, msg, plaintext)
+}
+
+func TestKeyShareFile(t *testing.T) {
+	_, shares, err := Deal(2, 3)
+	require.NoError(t, err)
+	path := t.TempDir() + "/share.json"
+	require.NoError(t, WriteKeyShare(path, shares[1]))
+
+	loaded, err := ReadKeyShare(path)
+	require.NoError(t, err)
+	assert.Equal(t, shares[1].Index, loaded.Index)
+	assert.Equal(t, shares[1].Threshold, loaded.Threshold)
+	assert.Equal(t, 0, shares[1].Secret.Cmp(loaded.Secret))
+	assert.Equal(t, shares[1].PublicKey.Bytes(), loaded.PublicKey.Bytes())
+	require.Len(t, loaded.VerificationKeys, 3)
+	for index, vk := range shares[1].VerificationKeys {
+		assert.Equal(t, vk.Bytes(), loaded.VerificationKeys[index].Bytes())
+	}
+
+	// a secret that doesn't match the share's verification key is refused
+	shares[1].Secret = shares[0].Secret
+	require.NoError(t, WriteKeyShare(path, shares[1]))
+	_, err = ReadKeyShare(path)
+	assert.ErrorIs(t, err, ErrInvalidKeyShare)
+}
@@ -1,12 +1,23 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"log"
 	"os"
-
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
 	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
 	"github.com/OCAX-labs/rfqrelayer/keystore"
 	"github.com/OCAX-labs/rfqrelayer/network"
+	"github.com/OCAX-labs/rfqrelayer/tokens"
 	"github.com/joho/godotenv"
 )
 
@@ -16,45 +27,30 @@ func main() {
 		log.Fatal("Error loading .env file")
 	}
 
-	ks := keystore.NewKeyStore()
-
-	// Keystore path
-	keyStorePath := ".keystore/keystore.json"
-
-	// Create .keystore directory if not exist
-	if _, err := os.Stat(".keystore/"); os.IsNotExist(err) {
-		err = os.Mkdir(".keystore/", 0755)
-		if err != nil {
-			log.Fatal(err)
-		}
-	}
-
 	// Load the passphrase from the .env file
 	passphrase := os.Getenv("PASSPHRASE")
 
-	// Load or create key
-	var validatorPrivKey cryptoocax.PrivateKey
-	if _, err := os.Stat(keyStorePath); os.IsNotExist(err) {
-		// If the keystore does not exist, generate a new key
-		err := ks.GenerateKeyToFile(passphrase, keyStorePath)
-		if err != nil {
-			log.Fatal(err)
-		}
-		validatorPrivKey = *ks.PrivateKey
-	} else {
-		// If the keystore exists, load the key
-		err := ks.LoadKeyFromFile(passphrase, keyStorePath)
-		if err != nil {
-			log.Fatal(err)
-		}
-		validatorPrivKey = *ks.PrivateKey
+	// Load or create the validator key
+	validatorPrivKey := loadOrCreateKey(".keystore/keystore.json", passphrase)
+
+	// Quotes are sealed to a threshold key so they can't be read before the
+	// auction closes. Each validator's share is dealt offline with cmd/dealer.
+	// REMOTE_A does not produce blocks but holds a share of its own, signing
+	// its decryption shares with a separate key.
+	keyShare := loadKeyShare("THRESHOLD_KEY_SHARE")
+	remoteKeyShare := loadKeyShare("REMOTE_A_KEY_SHARE")
+	var remoteShareKey *cryptoocax.PrivateKey
+	if remoteKeyShare != nil {
+		key := loadOrCreateKey(".keystore/remote_a.json", passphrase)
+		remoteShareKey = &key
+		log.Printf("REMOTE_A signs the decryption shares of share %d with %s", remoteKeyShare.Index, key.PublicKey().Address().Hex())
 	}
 
-	localNode := makeServer("LOCAL_NODE", &validatorPrivKey, ":3000", []string{":4000"}, ":9999")
+	localNode := makeServer("LOCAL_NODE", &validatorPrivKey, nil, keyShare, ":3000", []string{":4000"}, ":9999", ":50051")
 
 	go localNode.Start()
 
-	remoteNode := makeServer("REMOTE_A", nil, ":4000", []string{":4000"}, ":9998")
+	remoteNode := makeServer("REMOTE_A", nil, remoteShareKey, remoteKeyShare, ":4000", []string{":4000"}, ":9998", "")
 	go remoteNode.Start()
 
 	// remoteNodeB := makeServer("REMOTE_B", nil, ":5000", nil, "")
@@ -67,16 +63,38 @@ func main() {
 
 	// }()
 
-	select {}
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	stop()
+
+	log.Println("shutting down")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout())
+	defer cancel()
+	for _, node := range []*network.Server{localNode, remoteNode} {
+		if err := node.Stop(ctx); err != nil {
+			log.Printf("failed to stop node: %v", err)
+		}
+	}
 }
 
-func makeServer(id string, pk *cryptoocax.PrivateKey, addr string, seedNodes []string, apiListenAddr string) *network.Server {
+func makeServer(id string, pk *cryptoocax.PrivateKey, shareKey *cryptoocax.PrivateKey, keyShare *threshold.KeyShare, addr string, seedNodes []string, apiListenAddr string, grpcListenAddr string) *network.Server {
 	options := network.ServerOptions{
-		APIListenAddr: apiListenAddr,
-		SeedNodes:     seedNodes,
-		ListenAddr:    addr,
-		PrivateKey:    pk,
-		ID:            id,
+		APIListenAddr:  apiListenAddr,
+		GRPCListenAddr: grpcListenAddr,
+		SeedNodes:      seedNodes,
+		ListenAddr:     addr,
+		PrivateKey:     pk,
+		ShareKey:       shareKey,
+		KeyShare:       keyShare,
+		Validators:     loadValidators(),
+		ID:             id,
+
+		AdminAddress:     common.HexToAddress(os.Getenv("ADMIN_ADDRESS")),
+		EnforceWhitelist: envBool("ENFORCE_WHITELIST"),
+		RequireAPIAuth:   envBool("REQUIRE_API_AUTH"),
+		TokenRegistry:    loadTokenRegistry(),
+		DealerGroups:     loadDealerGroups(),
 	}
 	log.Default().Println("options", options, "server:", options.ID)
 	s, err := network.NewServer(options)
@@ -85,3 +103,121 @@ func makeServer(id string, pk *cryptoocax.PrivateKey, addr string, seedNodes []s
 	}
 	return s
 }
+
+// envBool reads a boolean setting from the environment, defaulting to off so
+// a local network runs without onboarding participants or signing requests.
+func envBool(key string) bool {
+	enabled, err := strconv.ParseBool(os.Getenv(key))
+	return err == nil && enabled
+}
+
+// shutdownTimeout reads how long the nodes have to stop from
+// SHUTDOWN_TIMEOUT, a duration such as 10s, defaulting to 30 seconds.
+func shutdownTimeout() time.Duration {
+	value := os.Getenv("SHUTDOWN_TIMEOUT")
+	if value == "" {
+		return 30 * time.Second
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("invalid SHUTDOWN_TIMEOUT: %v", err)
+	}
+	return timeout
+}
+
+// loadOrCreateKey loads the key in the keystore at path, generating it on
+// first start.
+func loadOrCreateKey(path string, passphrase string) cryptoocax.PrivateKey {
+	ks := keystore.NewKeyStore()
+
+	// Create the keystore directory if not exist
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// If the keystore does not exist, generate a new key
+		if err := ks.GenerateKeyToFile(passphrase, path); err != nil {
+			log.Fatal(err)
+		}
+	} else if err := ks.LoadKeyFromFile(passphrase, path); err != nil {
+		// If the keystore exists, load the key
+		log.Fatal(err)
+	}
+	return *ks.PrivateKey
+}
+
+// loadKeyShare loads a validator's share of the threshold key from the file
+// named by the env variable key. Without one for LOCAL_NODE quotes are
+// stored in the clear.
+func loadKeyShare(key string) *threshold.KeyShare {
+	path := os.Getenv(key)
+	if path == "" {
+		log.Printf("%s is not set", key)
+		return nil
+	}
+	share, err := threshold.ReadKeyShare(path)
+	if err != nil {
+		log.Fatalf("failed to load key share: %v", err)
+	}
+	return share
+}
+
+// loadValidators loads the addresses of the validators holding the other
+// shares of the threshold key from the JSON file at THRESHOLD_VALIDATORS, a
+// map of share index to address.
+func loadValidators() map[uint64]common.Address {
+	path := os.Getenv("THRESHOLD_VALIDATORS")
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("failed to read validators: %v", err)
+	}
+	var validators map[uint64]common.Address
+	if err := json.Unmarshal(data, &validators); err != nil {
+		log.Fatalf("failed to decode validators: %v", err)
+	}
+	return validators
+}
+
+// loadTokenRegistry loads the token list named by TOKEN_LIST for CHAIN_ID
+// (default 1). Without a token list any token is accepted.
+func loadTokenRegistry() *tokens.Registry {
+	path := os.Getenv("TOKEN_LIST")
+	if path == "" {
+		return nil
+	}
+	chainID := uint64(1)
+	if id := os.Getenv("CHAIN_ID"); id != "" {
+		parsed, err := strconv.ParseUint(id, 10, 64)
+		if err != nil {
+			log.Fatalf("invalid CHAIN_ID: %v", err)
+		}
+		chainID = parsed
+	}
+	registry, err := tokens.LoadRegistry(path, chainID)
+	if err != nil {
+		log.Fatalf("failed to load token list: %v", err)
+	}
+	return registry
+}
+
+// loadDealerGroups loads the dealer groups private RFQs may name from the
+// JSON file at DEALER_GROUPS, a map of group name to member addresses.
+func loadDealerGroups() types.DealerGroups {
+	path := os.Getenv("DEALER_GROUPS")
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		log.Fatalf("failed to read dealer groups: %v", err)
+	}
+	var groups types.DealerGroups
+	if err := json.Unmarshal(data, &groups); err != nil {
+		log.Fatalf("invalid dealer groups: %v", err)
+	}
+	return groups
+}
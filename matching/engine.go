@@ -0,0 +1,100 @@
+// Package matching determines the winning quotes of closed auctions and
+// verifies recorded results by replaying them.
+package matching
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+const BestPriceEngineName = "best-price"
+
+var (
+	ErrAuctionNotClosed = errors.New("auction has not closed")
+	ErrQuotesSealed     = errors.New("auction has sealed quotes that have not been revealed")
+	ErrUnknownEngine    = errors.New("unknown matching engine")
+)
+
+// Engine matches the quotes of a closed auction. Implementations must be
+// deterministic: matching the same closed auction always produces the same
+// result, so that auditors can replay it.
+type Engine interface {
+	Name() string
+	Match(closedRFQ *types.OpenRFQ) (*types.MatchResult, error)
+}
+
+// NewEngine returns the matching engine registered under name.
+func NewEngine(name string) (Engine, error) {
+	switch name {
+	case BestPriceEngineName:
+		return NewBestPriceEngine(), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEngine, name)
+	}
+}
+
+// BestPriceEngine selects the highest bid and the lowest ask of an auction.
+// Ties are broken by the lowest quote hash so the result does not depend on
+// the order quotes were received in.
+type BestPriceEngine struct{}
+
+func NewBestPriceEngine() *BestPriceEngine {
+	return &BestPriceEngine{}
+}
+
+func (e *BestPriceEngine) Name() string {
+	return BestPriceEngineName
+}
+
+func (e *BestPriceEngine) Match(closedRFQ *types.OpenRFQ) (*types.MatchResult, error) {
+	data := closedRFQ.Data
+	if data.Status != types.RFQStatusClosed && data.Status != types.RFQStatusMatched {
+		return nil, ErrAuctionNotClosed
+	}
+
+	result := &types.MatchResult{
+		RFQTxHash:  data.RFQTxHash,
+		QuotesRoot: data.QuotesRoot,
+		Engine:     e.Name(),
+	}
+	for _, quote := range data.Quotes {
+		if isUnrevealed(quote.Data) {
+			return nil, ErrQuotesSealed
+		}
+		hash := quote.Hash()
+		if better(quote.Data.BidPrice, hash, result.BestBid, 1) {
+			result.BestBid = &types.MatchedQuote{QuoteHash: hash, Quoter: quote.From, Price: new(big.Int).Set(quote.Data.BidPrice)}
+		}
+		if better(quote.Data.AskPrice, hash, result.BestAsk, -1) {
+			result.BestAsk = &types.MatchedQuote{QuoteHash: hash, Quoter: quote.From, Price: new(big.Int).Set(quote.Data.AskPrice)}
+		}
+	}
+	return result, nil
+}
+
+// better reports whether price beats the current best, where sign is 1 when
+// higher prices win and -1 when lower prices win. Zero prices are treated as
+// no quote on that side.
+func better(price *big.Int, hash common.Hash, best *types.MatchedQuote, sign int) bool {
+	if price == nil || price.Sign() <= 0 {
+		return false
+	}
+	if best == nil {
+		return true
+	}
+	if cmp := price.Cmp(best.Price) * sign; cmp != 0 {
+		return cmp > 0
+	}
+	return bytes.Compare(hash[:], best.QuoteHash[:]) < 0
+}
+
+// isUnrevealed reports whether a quote is still sealed to the threshold key.
+func isUnrevealed(q *types.QuoteData) bool {
+	zero := func(x *big.Int) bool { return x == nil || x.Sign() == 0 }
+	return q.IsSealed() && zero(q.BidPrice) && zero(q.AskPrice)
+}
@@ -0,0 +1,219 @@
+package matching
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// validatorKey signs the closed auctions and match results of the tests.
+var validatorKey = cryptoocax.GeneratePrivateKey()
+
+// sign sets the signature of tx by key on inner.
+func sign(t *testing.T, inner types.TxData, key cryptoocax.PrivateKey) {
+	t.Helper()
+	signed, err := types.NewTx(inner).Sign(key)
+	require.NoError(t, err)
+	v, r, s := signed.RawSignatureValues()
+	switch inner := inner.(type) {
+	case *types.Quote:
+		inner.V, inner.R, inner.S = v, r, s
+	case *types.OpenRFQ:
+		inner.V, inner.R, inner.S = v, r, s
+	}
+}
+
+func newQuote(t *testing.T, rfqTxHash common.Hash, bid, ask int64) *types.Quote {
+	privateKey := cryptoocax.GeneratePrivateKey()
+	quote := &types.Quote{
+		From: privateKey.PublicKey().Address(),
+		Data: &types.QuoteData{
+			QuoterId:  privateKey.PublicKey().Address().Hex(),
+			RFQTxHash: rfqTxHash,
+			BaseToken: &types.BaseToken{
+				Address:  common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"),
+				Symbol:   "MKR",
+				Decimals: 18,
+			},
+			QuoteToken: &types.QuoteToken{
+				Address:  common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+				Symbol:   "USDC",
+				Decimals: 6,
+			},
+			BaseTokenAmount:      big.NewInt(1000),
+			BidPrice:             big.NewInt(bid),
+			AskPrice:             big.NewInt(ask),
+			EncryptionPublicKeys: []*cryptoocax.PublicKey{},
+		},
+	}
+	sign(t, quote, privateKey)
+	return quote
+}
+
+func newClosedRFQ(t *testing.T, quotes ...*types.Quote) *types.OpenRFQ {
+	rfqTxHash := common.HexToHash("0x1234567890")
+	data := &types.RFQData{
+		RFQTxHash: rfqTxHash,
+		RFQRequest: &types.SignableData{
+			RequestorId:     "1234",
+			BaseTokenAmount: big.NewInt(1000),
+			BaseToken:       quotes[0].Data.BaseToken,
+			QuoteToken:      quotes[0].Data.QuoteToken,
+			RFQDurationMs:   10_000,
+		},
+		RFQStartTime: 1609459200000,
+		RFQEndTime:   1609459210000,
+		Quotes:       quotes,
+		Status:       types.RFQStatusOpen,
+	}
+	data.Close()
+	closedRFQ := &types.OpenRFQ{From: validatorKey.PublicKey().Address(), Data: data}
+	sign(t, closedRFQ, validatorKey)
+	return closedRFQ
+}
+
+func TestBestPriceEngine(t *testing.T) {
+	rfqTxHash := common.HexToHash("0x1234567890")
+	q1 := newQuote(t, rfqTxHash, 100, 120)
+	q2 := newQuote(t, rfqTxHash, 105, 125)
+	q3 := newQuote(t, rfqTxHash, 0, 110) // ask only
+
+	engine := NewBestPriceEngine()
+	result, err := engine.Match(newClosedRFQ(t, q1, q2, q3))
+	require.NoError(t, err)
+	assert.Equal(t, q2.Hash(), result.BestBid.QuoteHash)
+	assert.Equal(t, big.NewInt(105), result.BestBid.Price)
+	assert.Equal(t, q3.Hash(), result.BestAsk.QuoteHash)
+	assert.Equal(t, big.NewInt(110), result.BestAsk.Price)
+
+	// the result does not depend on the order quotes were received in
+	reordered, err := engine.Match(newClosedRFQ(t, q3, q2, q1))
+	require.NoError(t, err)
+	assert.Equal(t, result.BestBid, reordered.BestBid)
+	assert.Equal(t, result.BestAsk, reordered.BestAsk)
+}
+
+func TestBestPriceEngineRejectsOpenAndSealedAuctions(t *testing.T) {
+	rfqTxHash := common.HexToHash("0x1234567890")
+	closedRFQ := newClosedRFQ(t, newQuote(t, rfqTxHash, 100, 120))
+	closedRFQ.Data.Status = types.RFQStatusOpen
+	_, err := NewBestPriceEngine().Match(closedRFQ)
+	assert.ErrorIs(t, err, ErrAuctionNotClosed)
+
+	pub, _, err := threshold.Deal(1, 1)
+	require.NoError(t, err)
+	sealed := newQuote(t, rfqTxHash, 100, 120)
+	require.NoError(t, sealed.Data.SealPrices(pub))
+	_, err = NewBestPriceEngine().Match(newClosedRFQ(t, sealed))
+	assert.ErrorIs(t, err, ErrQuotesSealed)
+}
+
+func TestVerify(t *testing.T) {
+	rfqTxHash := common.HexToHash("0x1234567890")
+	closedRFQ := newClosedRFQ(t, newQuote(t, rfqTxHash, 100, 120), newQuote(t, rfqTxHash, 105, 125))
+
+	engine := NewBestPriceEngine()
+	result, err := engine.Match(closedRFQ)
+	require.NoError(t, err)
+	require.NoError(t, result.Sign(validatorKey))
+
+	// verification survives exporting the snapshot to JSON
+	exported, err := json.Marshal(&Snapshot{ClosedRFQ: closedRFQ, MatchResult: result})
+	require.NoError(t, err)
+	snapshot := new(Snapshot)
+	require.NoError(t, json.Unmarshal(exported, snapshot))
+
+	report := Verify(engine, snapshot)
+	assert.True(t, report.Verified, report.Errors)
+	assert.Empty(t, report.Diff)
+
+	// a tampered result no longer matches the replay or its signature
+	snapshot.MatchResult.BestAsk.Price = big.NewInt(1)
+	report = Verify(engine, snapshot)
+	assert.False(t, report.Verified)
+	assert.False(t, report.SignatureValid)
+	assert.Equal(t, []Difference{{Field: "bestAsk.price", Recorded: "1", Replayed: "120"}}, report.Diff)
+
+	// a result signed by another key than the one that closed the auction
+	// is rejected
+	snapshot.MatchResult.BestAsk.Price = big.NewInt(120)
+	require.NoError(t, snapshot.MatchResult.Sign(cryptoocax.GeneratePrivateKey()))
+	report = Verify(engine, snapshot)
+	assert.False(t, report.Verified)
+	assert.False(t, report.SignatureValid)
+	assert.True(t, report.ResultMatches)
+
+	// as is an auction whose quotes or closing signature were forged, even
+	// when the quotes root is recomputed to match
+	require.NoError(t, snapshot.MatchResult.Sign(validatorKey))
+	require.True(t, Verify(engine, snapshot).Verified)
+	snapshot.ClosedRFQ.Data.Quotes[1].Data.BidPrice = big.NewInt(200)
+	snapshot.ClosedRFQ.Data.QuotesRoot = snapshot.ClosedRFQ.Data.QuotesMerkleRoot()
+	report = Verify(engine, snapshot)
+	assert.False(t, report.Verified)
+	assert.True(t, report.QuotesRootValid)
+	assert.False(t, report.QuoteSignaturesValid)
+	assert.False(t, report.AuctionSignatureValid)
+}
+
+func TestVerifyRevealedQuotes(t *testing.T) {
+	rfqTxHash := common.HexToHash("0x1234567890")
+	pub, shares, err := threshold.Deal(2, 3)
+	require.NoError(t, err)
+
+	quoterKey := cryptoocax.GeneratePrivateKey()
+	sealed := newQuote(t, rfqTxHash, 105, 125)
+	sealed.From = quoterKey.PublicKey().Address()
+	require.NoError(t, sealed.Data.SealPrices(pub))
+	sign(t, sealed, quoterKey)
+	closedRFQ := newClosedRFQ(t, newQuote(t, rfqTxHash, 100, 120), sealed)
+
+	ct, err := sealed.Data.Ciphertext()
+	require.NoError(t, err)
+	d1, err := shares[0].DecryptionShare(ct)
+	require.NoError(t, err)
+	d2, err := shares[1].DecryptionShare(ct)
+	require.NoError(t, err)
+	require.NoError(t, sealed.Data.RevealPrices([]*threshold.DecryptionShare{d1, d2}))
+
+	engine := NewBestPriceEngine()
+	result, err := engine.Match(closedRFQ)
+	require.NoError(t, err)
+	require.NoError(t, result.Sign(validatorKey))
+
+	exported, err := json.Marshal(&Snapshot{ClosedRFQ: closedRFQ, MatchResult: result})
+	require.NoError(t, err)
+	snapshot := new(Snapshot)
+	require.NoError(t, json.Unmarshal(exported, snapshot))
+
+	report := Verify(engine, snapshot)
+	assert.True(t, report.Verified, report.Errors)
+	assert.True(t, report.RevealsValid)
+
+	// a revealed price is covered by neither the quoter's nor the
+	// validator's signature, but no longer decrypts from the ciphertext
+	snapshot.ClosedRFQ.Data.Quotes[1].Data.BidPrice = big.NewInt(200)
+	result, err = engine.Match(snapshot.ClosedRFQ)
+	require.NoError(t, err)
+	require.NoError(t, result.Sign(validatorKey))
+	snapshot.MatchResult = result
+	report = Verify(engine, snapshot)
+	assert.False(t, report.Verified)
+	assert.False(t, report.RevealsValid)
+	assert.True(t, report.QuoteSignaturesValid)
+	assert.True(t, report.AuctionSignatureValid)
+	assert.True(t, report.ResultMatches)
+
+	// as are revealed prices without the shares they were decrypted with
+	snapshot.ClosedRFQ.Data.Quotes[1].Data.BidPrice = big.NewInt(105)
+	require.True(t, Verify(engine, snapshot).RevealsValid)
+	snapshot.ClosedRFQ.Data.Quotes[1].Data.DecryptionShares = nil
+	assert.False(t, Verify(engine, snapshot).RevealsValid)
+}
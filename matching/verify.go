@@ -0,0 +1,142 @@
+package matching
+
+import (
+	"fmt"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+// Snapshot is an exported closed auction together with its recorded match
+// result, sufficient to replay and verify the match offline.
+type Snapshot struct {
+	ClosedRFQ   *types.OpenRFQ     `json:"closedRFQ"`
+	MatchResult *types.MatchResult `json:"matchResult"`
+}
+
+// Difference is a field whose recorded value disagrees with the replay.
+type Difference struct {
+	Field    string `json:"field"`
+	Recorded string `json:"recorded"`
+	Replayed string `json:"replayed"`
+}
+
+// Report is the machine-readable outcome of verifying a recorded match.
+// AuctionSignatureValid covers the validator's signature of the closed RFQ,
+// QuoteSignaturesValid the quoters' signatures of every quote in it,
+// RevealsValid the revealed prices of its sealed quotes against their signed
+// ciphertexts and SignatureValid the signature of the match result by the
+// same validator.
+type Report struct {
+	RFQTxHash             common.Hash        `json:"rfqTxHash"`
+	Engine                string             `json:"engine"`
+	Verified              bool               `json:"verified"`
+	QuotesRootValid       bool               `json:"quotesRootValid"`
+	AuctionSignatureValid bool               `json:"auctionSignatureValid"`
+	QuoteSignaturesValid  bool               `json:"quoteSignaturesValid"`
+	RevealsValid          bool               `json:"revealsValid"`
+	SignatureValid        bool               `json:"signatureValid"`
+	ResultMatches         bool               `json:"resultMatches"`
+	Errors                []string           `json:"errors,omitempty"`
+	Diff                  []Difference       `json:"diff,omitempty"`
+	Replayed              *types.MatchResult `json:"replayed,omitempty"`
+}
+
+// Verify checks the signatures of the closed auction in the snapshot and of
+// its quotes, decrypts sealed quotes again with the decryption shares kept
+// with them to check their revealed prices, replays it with engine and compares the outcome with the
+// recorded match result, which must be signed by the validator that closed
+// the auction.
+func Verify(engine Engine, snapshot *Snapshot) *Report {
+	report := &Report{Engine: engine.Name()}
+	if snapshot.ClosedRFQ == nil || snapshot.ClosedRFQ.Data == nil {
+		report.Errors = append(report.Errors, "snapshot has no closed RFQ")
+		return report
+	}
+	data := snapshot.ClosedRFQ.Data
+	report.RFQTxHash = data.RFQTxHash
+
+	report.QuotesRootValid = data.QuotesMerkleRoot() == data.QuotesRoot
+	if !report.QuotesRootValid {
+		report.Errors = append(report.Errors, "quotes do not match the committed quotes root")
+	}
+	if err := types.NewTx(snapshot.ClosedRFQ).Verify(); err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("closed RFQ: %v", err))
+	} else {
+		report.AuctionSignatureValid = true
+	}
+	report.QuoteSignaturesValid = true
+	report.RevealsValid = true
+	for i, quote := range data.Quotes {
+		if quote == nil || quote.Data == nil {
+			report.QuoteSignaturesValid = false
+			report.Errors = append(report.Errors, fmt.Sprintf("quote %d is empty", i))
+			continue
+		}
+		if err := types.NewTx(quote).Verify(); err != nil {
+			report.QuoteSignaturesValid = false
+			report.Errors = append(report.Errors, fmt.Sprintf("quote %s: %v", quote.Hash().Hex(), err))
+		}
+		if err := quote.Data.VerifyReveal(); err != nil {
+			report.RevealsValid = false
+			report.Errors = append(report.Errors, fmt.Sprintf("quote %s: %v", quote.Hash().Hex(), err))
+		}
+	}
+
+	recorded := snapshot.MatchResult
+	if recorded == nil {
+		report.Errors = append(report.Errors, "snapshot has no recorded match result")
+		return report
+	}
+	if err := recorded.Verify(); err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	} else if recorded.Validator != snapshot.ClosedRFQ.From {
+		report.Errors = append(report.Errors, fmt.Sprintf("match result signed by %s, not by the validator %s that closed the auction", recorded.Validator.Hex(), snapshot.ClosedRFQ.From.Hex()))
+	} else {
+		report.SignatureValid = true
+	}
+
+	replayed, err := engine.Match(snapshot.ClosedRFQ)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("replay failed: %v", err))
+		return report
+	}
+	report.Replayed = replayed
+	report.Diff = diff(recorded, replayed)
+	report.ResultMatches = len(report.Diff) == 0
+
+	report.Verified = report.QuotesRootValid && report.AuctionSignatureValid && report.QuoteSignaturesValid &&
+		report.RevealsValid && report.SignatureValid && report.ResultMatches
+	return report
+}
+
+func diff(recorded, replayed *types.MatchResult) []Difference {
+	var diffs []Difference
+	add := func(field, a, b string) {
+		if a != b {
+			diffs = append(diffs, Difference{Field: field, Recorded: a, Replayed: b})
+		}
+	}
+	add("rfqTxHash", recorded.RFQTxHash.Hex(), replayed.RFQTxHash.Hex())
+	add("quotesRoot", recorded.QuotesRoot.Hex(), replayed.QuotesRoot.Hex())
+	add("engine", recorded.Engine, replayed.Engine)
+	diffQuote := func(side string, a, b *types.MatchedQuote) {
+		if a == nil || b == nil {
+			add(side, quoteString(a), quoteString(b))
+			return
+		}
+		add(side+".quoteHash", a.QuoteHash.Hex(), b.QuoteHash.Hex())
+		add(side+".quoter", a.Quoter.Hex(), b.Quoter.Hex())
+		add(side+".price", a.Price.String(), b.Price.String())
+	}
+	diffQuote("bestBid", recorded.BestBid, replayed.BestBid)
+	diffQuote("bestAsk", recorded.BestAsk, replayed.BestAsk)
+	return diffs
+}
+
+func quoteString(q *types.MatchedQuote) string {
+	if q == nil {
+		return "none"
+	}
+	return fmt.Sprintf("%s@%s", q.QuoteHash.Hex(), q.Price)
+}
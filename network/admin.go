@@ -0,0 +1,109 @@
+package network
+
+import (
+	"github.com/OCAX-labs/rfqrelayer/api"
+)
+
+// NodeStatus reports the state of the node to the admin API. Counting the
+// database records reads every key so it is only done on request.
+func (s *Server) NodeStatus() (*api.NodeStatus, error) {
+	records, err := s.db.Size()
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	peers := len(s.peerMap)
+	s.mu.RUnlock()
+
+	status := &api.NodeStatus{
+		ID:                    s.ID,
+		Validator:             s.isValidator,
+		Height:                s.chain.Height(),
+		BlockProductionPaused: s.blockProductionPaused.Load(),
+		Peers:                 peers,
+		Mempool:               s.mempoolCount(),
+		DatabaseRecords:       records,
+	}
+	if head := s.chain.CurrentBlock(); head != nil {
+		hash := head.Hash()
+		status.Head = &hash
+	}
+	status.AuctionQueue.Depth, status.AuctionQueue.NextExpiry = s.chain.AuctionQueueStatus()
+	return status, nil
+}
+
+// Peers lists the connected peers with the traffic exchanged with them.
+func (s *Server) Peers() []*api.PeerInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	peers := make([]*api.PeerInfo, 0, len(s.peerMap))
+	for addr, peer := range s.peerMap {
+		peers = append(peers, &api.PeerInfo{
+			Addr:             addr.String(),
+			Outgoing:         peer.Outgoing,
+			ConnectedAt:      peer.connectedAt,
+			MessagesSent:     peer.messagesSent.Load(),
+			MessagesReceived: peer.messagesReceived.Load(),
+			BytesSent:        peer.bytesSent.Load(),
+			BytesReceived:    peer.bytesReceived.Load(),
+		})
+	}
+	return peers
+}
+
+// DisconnectPeer closes the connection to the peer at addr. A seed node is
+// only dialled again when the node restarts.
+func (s *Server) DisconnectPeer(addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for netAddr, peer := range s.peerMap {
+		if netAddr.String() != addr {
+			continue
+		}
+		delete(s.peerMap, netAddr)
+		s.TCPTransport.RemovePeer(peer)
+		peer.disconnect()
+		s.Logger.Log("msg", "peer disconnected by admin", "addr", addr)
+		return nil
+	}
+	return api.ErrPeerNotFound
+}
+
+func (s *Server) mempoolCount() api.MempoolCount {
+	return api.MempoolCount{
+		Pending:   s.memPool.PendingCount(),
+		All:       s.memPool.Count(),
+		MaxLength: s.memPool.maxLength,
+	}
+}
+
+// Mempool returns the transactions waiting for the next block.
+func (s *Server) Mempool() *api.Mempool {
+	return &api.Mempool{
+		MempoolCount: s.mempoolCount(),
+		Transactions: s.memPool.Pending(),
+	}
+}
+
+// PauseBlockProduction stops the validator creating blocks, transactions
+// keep collecting in the mempool until production resumes.
+func (s *Server) PauseBlockProduction() error {
+	if !s.isValidator {
+		return api.ErrNotValidator
+	}
+	s.blockProductionPaused.Store(true)
+	s.Logger.Log("msg", "block production paused")
+	return nil
+}
+
+// ResumeBlockProduction lets the validator create blocks again.
+func (s *Server) ResumeBlockProduction() error {
+	if !s.isValidator {
+		return api.ErrNotValidator
+	}
+	s.blockProductionPaused.Store(false)
+	s.Logger.Log("msg", "block production resumed")
+	return nil
+}
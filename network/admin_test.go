@@ -0,0 +1,90 @@
+package network
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/api"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminPeers(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	accepted := make(chan net.Conn, 2)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	remote := <-accepted
+	defer remote.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	peer := &TCPPeer{conn: conn, Outgoing: true, ctx: ctx, cancelFunc: cancel, connectedAt: time.Now()}
+	s := &Server{
+		TCPTransport:  NewTCPTransport("node", ":0", nil, nil),
+		peerMap:       map[net.Addr]*TCPPeer{conn.RemoteAddr(): peer},
+		ServerOptions: ServerOptions{Logger: log.NewNopLogger()},
+	}
+
+	// a message each way
+	rpcCh := make(chan RPC, 1)
+	stopped := make(chan struct{})
+	go func() {
+		peer.readLoop(rpcCh, make(chan error, 1))
+		close(stopped)
+	}()
+	require.NoError(t, peer.SendBytesPayload([]byte("ping")))
+	_, err = remote.Write([]byte("pong!"))
+	require.NoError(t, err)
+	<-rpcCh
+
+	peers := s.Peers()
+	require.Len(t, peers, 1)
+	assert.Equal(t, ln.Addr().String(), peers[0].Addr)
+	assert.True(t, peers[0].Outgoing)
+	assert.Equal(t, uint64(1), peers[0].MessagesSent)
+	assert.Equal(t, uint64(4), peers[0].BytesSent)
+	assert.Equal(t, uint64(1), peers[0].MessagesReceived)
+	assert.Equal(t, uint64(5), peers[0].BytesReceived)
+
+	assert.ErrorIs(t, s.DisconnectPeer("127.0.0.1:1"), api.ErrPeerNotFound)
+	require.NoError(t, s.DisconnectPeer(ln.Addr().String()))
+	assert.Empty(t, s.Peers())
+
+	// the read loop stops rather than dialling the peer again
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("read loop still running after disconnect")
+	}
+	select {
+	case <-accepted:
+		t.Fatal("disconnected peer was dialled again")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAdminBlockProduction(t *testing.T) {
+	s := &Server{ServerOptions: ServerOptions{Logger: log.NewNopLogger()}}
+	assert.ErrorIs(t, s.PauseBlockProduction(), api.ErrNotValidator)
+
+	s.isValidator = true
+	require.NoError(t, s.PauseBlockProduction())
+	assert.True(t, s.blockProductionPaused.Load())
+	require.NoError(t, s.ResumeBlockProduction())
+	assert.False(t, s.blockProductionPaused.Load())
+}
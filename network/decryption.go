@@ -0,0 +1,404 @@
+package network
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	errKeyShareWithoutKey = errors.New("a validator holding a key share needs a private key to sign its decryption shares")
+	errSharesSigner       = errors.New("decryption shares are not signed by the validator of their key share")
+	errMissingShare       = errors.New("decryption shares are missing a sealed quote")
+)
+
+// quoteDecryptor tracks closed auctions whose sealed quotes are waiting to be
+// decrypted and the decryption shares gathered for them from the validators.
+type quoteDecryptor struct {
+	mu    sync.Mutex
+	share *threshold.KeyShare
+	// validators are the addresses allowed to release the shares of each
+	// key share index
+	validators map[uint64]common.Address
+
+	// closed auctions committed to the chain whose shares have not been
+	// released yet, keyed by RFQ request hash with the auction end time in
+	// milliseconds
+	pending map[common.Hash]int64
+	// shares received per auction, keyed by validator share index
+	shares   map[common.Hash]map[uint64]*DecryptionSharesMessage
+	revealed map[common.Hash]bool
+	// signed shares that arrived before the block closing their auction,
+	// keyed by RFQ request hash and validator share index
+	early map[common.Hash]map[uint64]*DecryptionSharesMessage
+}
+
+func newQuoteDecryptor(share *threshold.KeyShare, validators map[uint64]common.Address) *quoteDecryptor {
+	return &quoteDecryptor{
+		share:      share,
+		validators: validators,
+		pending:    make(map[common.Hash]int64),
+		shares:     make(map[common.Hash]map[uint64]*DecryptionSharesMessage),
+		revealed:   make(map[common.Hash]bool),
+		early:      make(map[common.Hash]map[uint64]*DecryptionSharesMessage),
+	}
+}
+
+func (d *quoteDecryptor) schedule(rfqTxHash common.Hash, endTime int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.revealed[rfqTxHash] {
+		return
+	}
+	d.pending[rfqTxHash] = endTime
+}
+
+// hold keeps shares whose auction this node has not seen close yet, one
+// message per validator, until the closing block is committed.
+func (d *quoteDecryptor) hold(msg *DecryptionSharesMessage) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.revealed[msg.RFQTxHash] {
+		return
+	}
+	byIndex, ok := d.early[msg.RFQTxHash]
+	if !ok {
+		byIndex = make(map[uint64]*DecryptionSharesMessage)
+		d.early[msg.RFQTxHash] = byIndex
+	}
+	byIndex[msg.Index] = msg
+}
+
+// held removes and returns the shares held for an auction.
+func (d *quoteDecryptor) held(rfqTxHash common.Hash) []*DecryptionSharesMessage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	byIndex := d.early[rfqTxHash]
+	delete(d.early, rfqTxHash)
+	msgs := make([]*DecryptionSharesMessage, 0, len(byIndex))
+	for _, msg := range byIndex {
+		msgs = append(msgs, msg)
+	}
+	return msgs
+}
+
+// due removes and returns the auctions whose end time has been reached by a
+// block with the given timestamp (nanoseconds).
+func (d *quoteDecryptor) due(blockTime uint64) []common.Hash {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var hashes []common.Hash
+	for hash, endTime := range d.pending {
+		if uint64(endTime)*uint64(time.Millisecond) <= blockTime {
+			hashes = append(hashes, hash)
+			delete(d.pending, hash)
+		}
+	}
+	return hashes
+}
+
+// add records a validator's verified shares and returns all shares collected
+// for the auction once enough have arrived to reach the threshold.
+func (d *quoteDecryptor) add(msg *DecryptionSharesMessage) []*DecryptionSharesMessage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.revealed[msg.RFQTxHash] {
+		return nil
+	}
+	byIndex, ok := d.shares[msg.RFQTxHash]
+	if !ok {
+		byIndex = make(map[uint64]*DecryptionSharesMessage)
+		d.shares[msg.RFQTxHash] = byIndex
+	}
+	byIndex[msg.Index] = msg
+
+	if len(byIndex) < d.share.Threshold {
+		return nil
+	}
+	collected := make([]*DecryptionSharesMessage, 0, len(byIndex))
+	for _, m := range byIndex {
+		collected = append(collected, m)
+	}
+	return collected
+}
+
+// hash returns the hash the validator signs, covering everything but the
+// node ID.
+func (m *DecryptionSharesMessage) hash() (common.Hash, error) {
+	data, err := rlp.EncodeToBytes([]interface{}{m.RFQTxHash, m.Index, m.Shares})
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(cryptoocax.Keccak256Hash(data)), nil
+}
+
+func (m *DecryptionSharesMessage) sign(key cryptoocax.PrivateKey) error {
+	hash, err := m.hash()
+	if err != nil {
+		return err
+	}
+	sig, err := key.Sign(hash.Bytes())
+	if err != nil {
+		return err
+	}
+	m.Signature = sig.ToBytes()
+	return nil
+}
+
+// verify checks that msg is signed by the validator of its key share and
+// carries a valid decryption share for each of the sealed quotes.
+func (d *quoteDecryptor) verify(msg *DecryptionSharesMessage, ciphertexts map[common.Hash]*threshold.Ciphertext) error {
+	if err := d.verifySigner(msg); err != nil {
+		return err
+	}
+
+	vk := d.share.VerificationKeys[msg.Index]
+	shares := make(map[common.Hash]*QuoteDecryptionShare, len(msg.Shares))
+	for _, share := range msg.Shares {
+		shares[share.QuoteHash] = share
+	}
+	for quoteHash, ct := range ciphertexts {
+		share, ok := shares[quoteHash]
+		if !ok {
+			return fmt.Errorf("quote [%x]: %w", quoteHash, errMissingShare)
+		}
+		err := threshold.VerifyShare(ct, &threshold.DecryptionShare{Index: msg.Index, Point: share.Point, Proof: share.Proof}, vk)
+		if err != nil {
+			return fmt.Errorf("quote [%x]: %w", quoteHash, err)
+		}
+	}
+	return nil
+}
+
+// verifySigner checks that msg is signed by the validator of its key share.
+func (d *quoteDecryptor) verifySigner(msg *DecryptionSharesMessage) error {
+	validator, ok := d.validators[msg.Index]
+	if !ok || len(msg.Signature) == 0 {
+		return errSharesSigner
+	}
+	hash, err := msg.hash()
+	if err != nil {
+		return err
+	}
+	signer, err := types.RecoverAddress(hash, msg.Signature)
+	if err != nil || signer != validator {
+		return errSharesSigner
+	}
+	return nil
+}
+
+func (d *quoteDecryptor) markRevealed(rfqTxHash common.Hash) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.revealed[rfqTxHash] = true
+	delete(d.shares, rfqTxHash)
+	delete(d.early, rfqTxHash)
+}
+
+// recordClosedAuctions stores the closed auctions committed in a block and
+// schedules the release of this validator's decryption shares for them. Only
+// the validator whose auction queue closed an auction has it stored already,
+// so every other share holder learns of it here. Shares that arrived before
+// the block are processed once the auction is stored.
+func (s *Server) recordClosedAuctions(b *types.Block) {
+	for _, tx := range b.Transactions() {
+		if tx.Type() != types.OpenRFQTxType {
+			continue
+		}
+		data, ok := tx.EmbeddedData().(*types.RFQData)
+		if !ok || data.Status != types.RFQStatusClosed {
+			continue
+		}
+		if _, err := s.chain.GetClosedRFQByHash(data.RFQTxHash); err != nil {
+			if err := s.chain.WriteRFQTxs(tx); err != nil {
+				s.Logger.Log("msg", "failed to store closed RFQ", "rfq", data.RFQTxHash, "err", err)
+				continue
+			}
+		}
+		s.scheduleDecryption(tx)
+		if s.decryptor == nil {
+			continue
+		}
+		for _, msg := range s.decryptor.held(data.RFQTxHash) {
+			if err := s.processDecryptionSharesMessage(msg); err != nil {
+				s.Logger.Log("msg", "failed to decrypt quotes", "rfq", data.RFQTxHash, "err", err)
+			}
+		}
+	}
+}
+
+// scheduleDecryption queues a closed auction so this validator releases its
+// decryption shares once a block past the auction end time is committed.
+func (s *Server) scheduleDecryption(tx *types.Transaction) {
+	if s.decryptor == nil {
+		return
+	}
+	data, ok := tx.EmbeddedData().(*types.RFQData)
+	if !ok {
+		return
+	}
+	s.decryptor.schedule(data.RFQTxHash, data.RFQEndTime)
+}
+
+// releaseDecryptionShares computes and gossips this validator's decryption
+// shares for every closed auction whose end time has passed in block time.
+func (s *Server) releaseDecryptionShares(header *types.Header) {
+	if s.decryptor == nil {
+		return
+	}
+	for _, rfqTxHash := range s.decryptor.due(header.Timestamp) {
+		msg, err := s.createDecryptionSharesMessage(rfqTxHash)
+		if err != nil {
+			s.Logger.Log("msg", "failed to create decryption shares", "rfq", rfqTxHash, "err", err)
+			continue
+		}
+		if len(msg.Shares) == 0 {
+			continue
+		}
+
+		buf := new(bytes.Buffer)
+		if err := gob.NewEncoder(buf).Encode(msg); err != nil {
+			s.Logger.Log("err", err)
+			continue
+		}
+		s.broadcast(NewMessage(MessageTypeDecryptionShares, buf.Bytes(), s.ID).Bytes())
+
+		if err := s.processDecryptionSharesMessage(msg); err != nil {
+			s.Logger.Log("msg", "failed to decrypt quotes", "rfq", rfqTxHash, "err", err)
+		}
+	}
+}
+
+func (s *Server) createDecryptionSharesMessage(rfqTxHash common.Hash) (*DecryptionSharesMessage, error) {
+	closedRFQ, err := s.chain.GetClosedRFQByHash(rfqTxHash)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := &DecryptionSharesMessage{
+		ID:        s.ID,
+		RFQTxHash: rfqTxHash,
+		Index:     s.decryptor.share.Index,
+	}
+	for _, quote := range closedRFQ.Data.Quotes {
+		if !quote.Data.IsSealed() {
+			continue
+		}
+		ct, err := quote.Data.Ciphertext()
+		if err != nil {
+			return nil, err
+		}
+		share, err := s.decryptor.share.DecryptionShare(ct)
+		if err != nil {
+			return nil, err
+		}
+		msg.Shares = append(msg.Shares, &QuoteDecryptionShare{QuoteHash: quote.Hash(), Point: share.Point, Proof: share.Proof})
+	}
+	if err := msg.sign(*s.ShareKey); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// sealedCiphertexts returns the ciphertexts of the sealed quotes of a closed
+// auction by quote hash.
+func sealedCiphertexts(closedRFQ *types.OpenRFQ) (map[common.Hash]*threshold.Ciphertext, error) {
+	ciphertexts := make(map[common.Hash]*threshold.Ciphertext)
+	for _, quote := range closedRFQ.Data.Quotes {
+		if !quote.Data.IsSealed() {
+			continue
+		}
+		ct, err := quote.Data.Ciphertext()
+		if err != nil {
+			return nil, err
+		}
+		ciphertexts[quote.Hash()] = ct
+	}
+	return ciphertexts, nil
+}
+
+// processDecryptionSharesMessage collects decryption shares released by the
+// validators and, once the threshold is reached, decrypts the sealed quotes of
+// the auction and stores the revealed prices. Shares are only counted once
+// the message is authenticated and every share in it is verified, so a
+// faulty or impersonated validator can't hold up or corrupt the reveal.
+// Shares for an auction this node has not seen close are held until the
+// closing block is committed - see recordClosedAuctions.
+func (s *Server) processDecryptionSharesMessage(msg *DecryptionSharesMessage) error {
+	if s.decryptor == nil {
+		return nil
+	}
+	closedRFQ, err := s.chain.GetClosedRFQByHash(msg.RFQTxHash)
+	if errors.Is(err, core.ErrRFQNotFound) {
+		// the block closing the auction has not reached this node yet
+		if err := s.decryptor.verifySigner(msg); err != nil {
+			return fmt.Errorf("rejected decryption shares of validator %d: %w", msg.Index, err)
+		}
+		s.decryptor.hold(msg)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ciphertexts, err := sealedCiphertexts(closedRFQ)
+	if err != nil {
+		return err
+	}
+	if err := s.decryptor.verify(msg, ciphertexts); err != nil {
+		return fmt.Errorf("rejected decryption shares of validator %d: %w", msg.Index, err)
+	}
+	collected := s.decryptor.add(msg)
+	if collected == nil {
+		return nil
+	}
+
+	// keep exactly the threshold shares with the lowest indices, the ones
+	// Combine would use, so the shares stored with each quote reproduce it
+	t := s.decryptor.share.Threshold
+	revealed := make(map[common.Hash][]*threshold.DecryptionShare)
+	for quoteHash := range ciphertexts {
+		shares := make([]*threshold.DecryptionShare, 0, len(collected))
+		for _, m := range collected {
+			for _, share := range m.Shares {
+				if share.QuoteHash == quoteHash {
+					shares = append(shares, &threshold.DecryptionShare{Index: m.Index, Point: share.Point, Proof: share.Proof})
+					break
+				}
+			}
+		}
+		if len(shares) < t {
+			return fmt.Errorf("quote [%x]: %w", quoteHash, threshold.ErrNotEnoughShares)
+		}
+		sort.Slice(shares, func(i, j int) bool { return shares[i].Index < shares[j].Index })
+		revealed[quoteHash] = shares[:t]
+	}
+
+	if _, err := s.chain.RevealQuotes(msg.RFQTxHash, revealed); err != nil {
+		return err
+	}
+	s.decryptor.markRevealed(msg.RFQTxHash)
+	s.Logger.Log("msg", "revealed sealed quotes", "rfq", msg.RFQTxHash, "quotes", len(revealed))
+
+	if s.isValidator {
+		s.matchAuction(msg.RFQTxHash)
+	}
+
+	return nil
+}
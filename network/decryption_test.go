@@ -0,0 +1,163 @@
+package network
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/OCAX-labs/rfqrelayer/rfqdb/pebble"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closedSealedAuction returns an auction with one quote sealed to pub,
+// closed and signed by key, and the quote's bid price.
+func closedSealedAuction(t *testing.T, key cryptoocax.PrivateKey, pub *threshold.PublicKey) (*types.Transaction, *big.Int) {
+	t.Helper()
+	rfqTxHash := common.HexToHash("0x01")
+	request := &types.SignableData{
+		RequestorId:     "1",
+		BaseTokenAmount: big.NewInt(1000),
+		BaseToken:       &types.BaseToken{Address: common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"), Symbol: "MKR", Decimals: 18},
+		QuoteToken:      &types.QuoteToken{Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), Symbol: "USDC", Decimals: 6},
+		RFQDurationMs:   60000,
+	}
+	bid := big.NewInt(1500)
+	quote := &types.QuoteData{
+		RFQTxHash:       rfqTxHash,
+		BaseToken:       request.BaseToken,
+		QuoteToken:      request.QuoteToken,
+		BaseTokenAmount: request.BaseTokenAmount,
+		BidPrice:        new(big.Int).Set(bid),
+		AskPrice:        big.NewInt(1600),
+	}
+	require.NoError(t, quote.SealPrices(pub))
+	signedQuote, err := types.NewTx(types.NewQuote(testKey.PublicKey().Address(), quote)).Sign(testKey)
+	require.NoError(t, err)
+	v, r, s := signedQuote.RawSignatureValues()
+	data := &types.RFQData{
+		RFQTxHash:  rfqTxHash,
+		RFQRequest: request,
+		Status:     types.RFQStatusClosed,
+		Quotes:     []*types.Quote{{From: testKey.PublicKey().Address(), Data: quote, V: v, R: r, S: s}},
+	}
+	tx, err := types.NewTx(types.NewOpenRFQ(key.PublicKey().Address(), data)).Sign(key)
+	require.NoError(t, err)
+	return tx, bid
+}
+
+// newDecryptionNode returns a node holding share with a chain of its own
+// starting at genesis.
+func newDecryptionNode(t *testing.T, genesis *types.Block, key *cryptoocax.PrivateKey, share *threshold.KeyShare, validators map[uint64]common.Address) *Server {
+	t.Helper()
+	db, err := pebble.New(t.TempDir(), cache, handles, "rfq", readonly)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	chain, err := core.NewBlockchain(log.NewNopLogger(), genesis, db, false)
+	require.NoError(t, err)
+	t.Cleanup(func() { chain.Stop(context.Background()) })
+
+	return &Server{
+		ServerOptions: ServerOptions{Logger: log.NewNopLogger(), ShareKey: key},
+		chain:         chain,
+		decryptor:     newQuoteDecryptor(share, validators),
+	}
+}
+
+func TestDecryptionShares(t *testing.T) {
+	// three validators hold the shares of a 2-of-3 key, each with its own
+	// chain
+	pub, shares, err := threshold.Deal(2, 3)
+	require.NoError(t, err)
+	keys := []cryptoocax.PrivateKey{cryptoocax.GeneratePrivateKey(), cryptoocax.GeneratePrivateKey(), cryptoocax.GeneratePrivateKey()}
+	validators := make(map[uint64]common.Address)
+	for i := range keys {
+		validators[shares[i].Index] = keys[i].PublicKey().Address()
+	}
+	genesis := genesisBlock()
+	nodes := make([]*Server, len(keys))
+	for i := range keys {
+		nodes[i] = newDecryptionNode(t, genesis, &keys[i], shares[i], validators)
+	}
+
+	// the first validator closes the auction and stores it, the others only
+	// learn of it from the block recording it
+	closedTx, bid := closedSealedAuction(t, keys[0], pub)
+	rfqTxHash := closedTx.ReferenceTxHash()
+	node := nodes[0]
+	require.NoError(t, node.chain.WriteRFQTxs(closedTx))
+	for _, other := range nodes[1:] {
+		_, err := other.chain.GetClosedRFQByHash(rfqTxHash)
+		require.ErrorIs(t, err, core.ErrRFQNotFound)
+	}
+	block, err := types.NewBlockFromPrevHeader(genesis.Header(), types.Transactions{closedTx})
+	require.NoError(t, err)
+	require.NoError(t, block.Sign(keys[0]))
+
+	// the second validator commits the block first and releases its shares,
+	// which reach the third before the block does
+	require.NoError(t, nodes[1].processBlock(block))
+	second, err := nodes[1].createDecryptionSharesMessage(rfqTxHash)
+	require.NoError(t, err)
+	require.Len(t, second.Shares, 1)
+	require.NoError(t, nodes[2].processDecryptionSharesMessage(second))
+
+	// shares signed by another validator than the one holding the index, or
+	// altered after signing, are rejected
+	impersonated := *second
+	require.NoError(t, impersonated.sign(keys[2]))
+	assert.ErrorIs(t, node.processDecryptionSharesMessage(&impersonated), errSharesSigner)
+	assert.ErrorIs(t, nodes[2].processDecryptionSharesMessage(&impersonated), errSharesSigner)
+	altered := *second
+	altered.Index = 3
+	assert.ErrorIs(t, node.processDecryptionSharesMessage(&altered), errSharesSigner)
+
+	// the third validator stores the auction once it commits the block, then
+	// combines the shares it held with its own
+	require.NoError(t, nodes[2].processBlock(block))
+	assertRevealed(t, nodes[2], rfqTxHash, bid)
+
+	// a validator signing a share it didn't compute with its key share is
+	// caught by the share's proof, as is one leaving a quote out
+	third, err := nodes[2].createDecryptionSharesMessage(rfqTxHash)
+	require.NoError(t, err)
+	forged := *third
+	forged.Shares = []*QuoteDecryptionShare{{QuoteHash: third.Shares[0].QuoteHash, Point: second.Shares[0].Point, Proof: third.Shares[0].Proof}}
+	require.NoError(t, forged.sign(keys[2]))
+	assert.ErrorIs(t, node.processDecryptionSharesMessage(&forged), threshold.ErrInvalidShare)
+	forged.Shares = nil
+	require.NoError(t, forged.sign(keys[2]))
+	assert.ErrorIs(t, node.processDecryptionSharesMessage(&forged), errMissingShare)
+
+	// the first validator releases its shares on the block too, none of the
+	// rejected messages counted towards the threshold
+	require.NoError(t, node.processBlock(block))
+	closed, err := node.chain.GetClosedRFQByHash(rfqTxHash)
+	require.NoError(t, err)
+	assert.Zero(t, closed.Data.Quotes[0].Data.BidPrice.Sign())
+	require.NoError(t, node.processDecryptionSharesMessage(second))
+	assertRevealed(t, node, rfqTxHash, bid)
+
+	// and the second validator reveals with the shares of either other one
+	first, err := node.createDecryptionSharesMessage(rfqTxHash)
+	require.NoError(t, err)
+	require.NoError(t, nodes[1].processDecryptionSharesMessage(first))
+	assertRevealed(t, nodes[1], rfqTxHash, bid)
+}
+
+// assertRevealed checks that node stored the revealed bid of the auction's
+// quote with the shares it was revealed with.
+func assertRevealed(t *testing.T, node *Server, rfqTxHash common.Hash, bid *big.Int) {
+	t.Helper()
+	closed, err := node.chain.GetClosedRFQByHash(rfqTxHash)
+	require.NoError(t, err)
+	assert.Equal(t, bid, closed.Data.Quotes[0].Data.BidPrice)
+	assert.Len(t, closed.Data.Quotes[0].Data.DecryptionShares, 2)
+	assert.NoError(t, closed.Data.Quotes[0].Data.VerifyReveal())
+}
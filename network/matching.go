@@ -0,0 +1,41 @@
+package network
+
+import (
+	"errors"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/matching"
+)
+
+// matchAuction runs the matching engine over a closed auction whose quotes
+// are all readable and stores the result signed by this validator.
+func (s *Server) matchAuction(rfqTxHash common.Hash) {
+	closedRFQ, err := s.chain.GetClosedRFQByHash(rfqTxHash)
+	if err != nil {
+		s.Logger.Log("msg", "failed to load closed RFQ for matching", "rfq", rfqTxHash, "err", err)
+		return
+	}
+
+	result, err := s.MatchingEngine.Match(closedRFQ)
+	if errors.Is(err, matching.ErrQuotesSealed) {
+		// matched once the validators have released their decryption shares
+		return
+	}
+	if err != nil {
+		s.Logger.Log("msg", "failed to match auction", "rfq", rfqTxHash, "err", err)
+		return
+	}
+	if err := result.Sign(*s.PrivateKey); err != nil {
+		s.Logger.Log("msg", "failed to sign match result", "rfq", rfqTxHash, "err", err)
+		return
+	}
+	if err := s.chain.WriteMatchResult(result); err != nil {
+		s.Logger.Log("msg", "failed to store match result", "rfq", rfqTxHash, "err", err)
+		return
+	}
+	s.Logger.Log("msg", "auction matched", "rfq", rfqTxHash, "engine", result.Engine)
+
+	for _, callback := range s.MatchCallbacks {
+		callback(result)
+	}
+}
@@ -1,6 +1,9 @@
 package network
 
-import "github.com/OCAX-labs/rfqrelayer/core/types"
+import (
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
 
 type GetBlocksMessage struct {
 	ID   string
@@ -29,3 +32,27 @@ type FullBlock struct {
 	Block  *types.Block
 	Header *types.Header
 }
+
+// DecryptionSharesMessage carries one validator's decryption shares for the
+// sealed quotes of a closed auction, signed by the validator holding the key
+// share Index.
+type DecryptionSharesMessage struct {
+	ID        string
+	RFQTxHash common.Hash
+	Index     uint64
+	Shares    []*QuoteDecryptionShare
+	Signature []byte
+}
+
+type QuoteDecryptionShare struct {
+	QuoteHash common.Hash
+	Point     []byte
+	// Proof shows Point was computed with the key share Index
+	Proof []byte
+}
+
+// ParticipantMessage carries an admin signed participant registry record.
+type ParticipantMessage struct {
+	ID          string
+	Participant *types.Participant
+}
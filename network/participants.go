@@ -0,0 +1,60 @@
+package network
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+// broadcastParticipant gossips an accepted participant record to every peer.
+func (s *Server) broadcastParticipant(p *types.Participant) error {
+	msg, err := s.participantMessage(p)
+	if err != nil {
+		return err
+	}
+	return s.broadcast(msg)
+}
+
+// sendParticipants brings a newly connected peer's registry up to date.
+func (s *Server) sendParticipants(peer *TCPPeer) error {
+	participants, err := s.chain.GetParticipants()
+	if err != nil {
+		return err
+	}
+	for _, p := range participants {
+		msg, err := s.participantMessage(p)
+		if err != nil {
+			return err
+		}
+		if err := peer.SendBytesPayload(msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) participantMessage(p *types.Participant) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(&ParticipantMessage{ID: s.ID, Participant: p}); err != nil {
+		return nil, err
+	}
+	return NewMessage(MessageTypeParticipant, buf.Bytes(), s.ID).Bytes(), nil
+}
+
+// processParticipantMessage stores a participant record received from a peer.
+// Records carry the admin's signature so they are verified rather than trusted,
+// and only records newer than ours are passed on so gossip dies out.
+func (s *Server) processParticipantMessage(msg *ParticipantMessage) error {
+	if err := s.chain.WriteParticipant(msg.Participant); err != nil {
+		if errors.Is(err, core.ErrStaleParticipant) {
+			return nil
+		}
+		return err
+	}
+	s.Logger.Log("msg", "participant updated", "address", msg.Participant.Address, "status", msg.Participant.Status)
+
+	return s.broadcastParticipant(msg.Participant)
+}
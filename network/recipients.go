@@ -0,0 +1,20 @@
+package network
+
+import (
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+// verifyTxRecipient rejects quotes on a private RFQ from market makers that
+// are not among its recipients. Quotes on RFQs this node has not opened are
+// left to the validator that did.
+func (s *Server) verifyTxRecipient(tx *types.Transaction) error {
+	if tx.Type() != types.QuoteTxType {
+		return nil
+	}
+	data := tx.EmbeddedData().(*types.QuoteData)
+	openRFQ, err := s.chain.GetOpenRFQByHash(data.RFQTxHash)
+	if err != nil {
+		return nil
+	}
+	return openRFQ.Data.CheckQuoter(*tx.From())
+}
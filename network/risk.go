@@ -0,0 +1,20 @@
+package network
+
+import (
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/OCAX-labs/rfqrelayer/risk"
+)
+
+// participantLimits looks up requestors' risk limits in the participant
+// registry. Requestors that are not onboarded have no limits.
+func participantLimits(chain *core.Blockchain) risk.LimitsFunc {
+	return func(requestor common.Address) *types.ParticipantLimits {
+		participant, err := chain.GetParticipant(requestor)
+		if err != nil {
+			return nil
+		}
+		return &participant.Limits
+	}
+}
@@ -22,6 +22,9 @@ const (
 	MessageTypeStatus    MessageType = 0x4
 	MessageTypeGetStatus MessageType = 0x5
 	MessageTypeBlocks    MessageType = 0x6
+
+	MessageTypeDecryptionShares MessageType = 0x7
+	MessageTypeParticipant      MessageType = 0x8
 )
 
 type RPC struct {
@@ -137,6 +140,30 @@ func DefaultRPCDecodeFunc(rpc RPC) (*DecodeMessage, error) {
 			Data: blocks,
 		}, nil
 
+	case MessageTypeDecryptionShares:
+		shares := new(DecryptionSharesMessage)
+		if err := gob.NewDecoder(bytes.NewReader(msg.Data)).Decode(shares); err != nil {
+			return nil, err
+		}
+
+		return &DecodeMessage{
+			ID:   msg.ID,
+			From: rpc.From,
+			Data: shares,
+		}, nil
+
+	case MessageTypeParticipant:
+		participant := new(ParticipantMessage)
+		if err := gob.NewDecoder(bytes.NewReader(msg.Data)).Decode(participant); err != nil {
+			return nil, err
+		}
+
+		return &DecodeMessage{
+			ID:   msg.ID,
+			From: rpc.From,
+			Data: participant,
+		}, nil
+
 	default:
 		return nil, fmt.Errorf("unknown message header type: %x", msg.Header)
 	}
@@ -10,6 +10,7 @@ import (
 	"net"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/OCAX-labs/rfqrelayer/api"
@@ -17,7 +18,11 @@ import (
 	"github.com/OCAX-labs/rfqrelayer/core"
 	"github.com/OCAX-labs/rfqrelayer/core/types"
 	cryptoocax "github.com/OCAX-labs/rfqrelayer/crypto/ocax"
+	"github.com/OCAX-labs/rfqrelayer/crypto/threshold"
+	"github.com/OCAX-labs/rfqrelayer/matching"
 	"github.com/OCAX-labs/rfqrelayer/rfqdb/pebble"
+	"github.com/OCAX-labs/rfqrelayer/risk"
+	"github.com/OCAX-labs/rfqrelayer/tokens"
 	"github.com/go-kit/log"
 )
 
@@ -56,82 +61,125 @@ type ServerOptions struct {
 	RPCProcessor  RPCProcessor
 	BlockTime     time.Duration
 	PrivateKey    *cryptoocax.PrivateKey
+	// KeyShare is this validator's share of the threshold key quotes are
+	// sealed to. When nil quotes are accepted and stored in the clear.
+	KeyShare *threshold.KeyShare
+	// ShareKey signs this node's decryption shares, defaults to PrivateKey.
+	// It lets a node hold a key share without producing blocks.
+	ShareKey *cryptoocax.PrivateKey
+	// Validators are the addresses of the validators holding the shares of
+	// the threshold key, by share index. Decryption shares are only accepted
+	// when signed by the validator of their index. The node's own share is
+	// held by its ShareKey.
+	Validators map[uint64]common.Address
+	// MatchingEngine determines the winning quotes of closed auctions,
+	// defaults to the best price engine.
+	MatchingEngine matching.Engine
+	// AdminAddress is the key allowed to sign participant registry records
+	// and to use the node's admin API
+	AdminAddress common.Address
+	// EnforceWhitelist rejects RFQs and quotes from participants that are not
+	// onboarded in the registry
+	EnforceWhitelist bool
+	// TokenRegistry lists the tokens RFQs and quotes may use
+	TokenRegistry *tokens.Registry
+	// DealerGroups are the named sets of market makers private RFQs may be
+	// sent to
+	DealerGroups types.DealerGroups
+	// RateLimits limits API submissions, defaults to api.DefaultRateLimits
+	RateLimits *api.RateLimits
+	// RequireAPIAuth rejects API requests that are not signed by the caller
+	RequireAPIAuth bool
+	// IdempotencyWindow is how long API responses are kept for retries that
+	// carry the same Idempotency-Key, defaults to 24 hours
+	IdempotencyWindow time.Duration
+	// GRPCListenAddr is where the gRPC API is served next to the JSON API
+	GRPCListenAddr string
+	// WebhookPolicy configures webhook retries, defaults to
+	// api.DefaultWebhookPolicy
+	WebhookPolicy *api.WebhookPolicy
 }
 
 type Server struct {
 	TCPTransport *TCPTransport
 	peerCh       chan (*TCPPeer)
 
-	mu      sync.RWMutex
-	peerMap map[net.Addr]*TCPPeer
-	txChan  chan *types.Transaction
+	mu            sync.RWMutex
+	peerMap       map[net.Addr]*TCPPeer
+	txChan        chan *types.Transaction
+	participantCh chan *types.Participant
 
 	ServerOptions
 	memPool     *TxPool
+	db          *pebble.Database
 	chain       *core.Blockchain
 	isValidator bool
-	rpcCh       chan RPC
-	quitCh      chan struct{} // options
+	// blockProductionPaused stops the validator loop creating blocks
+	blockProductionPaused atomic.Bool
+	rpcCh                 chan RPC
+	quitCh                chan struct{} // options
+	// started is set by Start, which closes stopped when it returns
+	started atomic.Bool
+	stopped chan struct{}
 
 	ctx        context.Context
 	cancelFunc context.CancelFunc
+	// loops tracks the goroutines that write to the chain so Stop can wait
+	// for them before closing the database
+	loops sync.WaitGroup
 
-	Callbacks []func(*types.Transaction, byte)
+	apiServer *api.Server
+
+	Callbacks      []func(*types.Transaction, byte)
+	MatchCallbacks []func(*types.MatchResult)
+
+	decryptor   *quoteDecryptor
+	riskChecker *risk.Checker
 }
 
 func NewServer(options ServerOptions) (*Server, error) {
 	if options.BlockTime == 0 {
 		options.BlockTime = defaultBlockTime
 	}
+	if options.MatchingEngine == nil {
+		options.MatchingEngine = matching.NewBestPriceEngine()
+	}
 	if options.RPCDecodeFunc == nil {
 		options.RPCDecodeFunc = DefaultRPCDecodeFunc
 	}
+	// the decryption shares of a validator are signed with its key
+	if options.ShareKey == nil {
+		options.ShareKey = options.PrivateKey
+	}
+	if options.KeyShare != nil && options.ShareKey == nil {
+		return nil, errKeyShareWithoutKey
+	}
 	if options.Logger == nil {
 		options.Logger = log.NewLogfmtLogger(os.Stderr)
 		options.Logger = log.With(options.Logger, "addr", options.ID)
 	}
 	log.Logger(options.Logger).Log("msg", "starting node", "id", options.ID)
-	// Delete the database directory if it exists
+	// a node restarting on its database picks up the chain, the open
+	// auctions and the mempool saved by Stop
 	dbPath := fmt.Sprintf("./.%s.db", options.ID)
-	err := deleteDirectoryIfExists(dbPath)
+	db, err := pebble.New(dbPath, cache, handles, "rfq", readonly)
 	if err != nil {
-		panic(fmt.Sprintf("failed to delete test database directory: %v", err))
+		return nil, err
 	}
 
-	db, err := func() (*pebble.Database, error) {
-		err := deleteDirectoryIfExists(dbPath)
-		if err != nil {
-			return nil, fmt.Errorf("failed to delete test database directory: %v", err)
-		}
-		return pebble.New(dbPath, cache, handles, "rfq", readonly)
-	}()
+	chain, err := core.NewBlockchain(options.Logger, genesisBlock(), db, options.PrivateKey != nil)
 	if err != nil {
 		return nil, err
 	}
-	defer db.Close()
-
-	chain, err := core.NewBlockchain(options.Logger, genesisBlock(), db, options.PrivateKey != nil)
+	chain.SetParticipantRegistry(options.AdminAddress, options.EnforceWhitelist)
+	riskChecker, err := risk.NewChecker(participantLimits(chain), chain)
 	if err != nil {
 		return nil, err
 	}
 
 	// channel used between json rpc api and the node server
 	txChan := make(chan *types.Transaction)
-	//
-	var apiServer *api.Server
-	if len(options.APIListenAddr) > 0 {
-		apiServerCfg := api.ServerConfig{
-			Logger:     options.Logger,
-			ListenAddr: options.APIListenAddr,
-			PrivateKey: options.PrivateKey,
-		}
-
-		apiServer = api.NewServer(apiServerCfg, chain, txChan)
-
-		go apiServer.Start()
-
-		options.Logger.Log("msg", "JSON API running", "addr", options.APIListenAddr)
-	}
+	participantCh := make(chan *types.Participant)
 
 	peerCh := make(chan *TCPPeer)
 	rpcCh := make(chan RPC, 2048)
@@ -144,12 +192,16 @@ func NewServer(options ServerOptions) (*Server, error) {
 		peerCh:        peerCh,
 		peerMap:       make(map[net.Addr]*TCPPeer),
 		ServerOptions: options,
+		db:            db,
 		chain:         chain,
 		memPool:       NewTxPool(1000),
 		isValidator:   options.PrivateKey != nil,
 		rpcCh:         rpcCh,
 		quitCh:        make(chan struct{}, 1),
+		stopped:       make(chan struct{}),
 		txChan:        txChan,
+		participantCh: participantCh,
+		riskChecker:   riskChecker,
 
 		// for broadcasting status messages
 		ctx:        ctx,
@@ -159,13 +211,74 @@ func NewServer(options ServerOptions) (*Server, error) {
 
 	s.TCPTransport.peerCh = peerCh
 
+	if err := s.restoreMempool(); err != nil {
+		s.Logger.Log("msg", "failed to restore mempool", "err", err)
+	}
+
+	var apiServer *api.Server
+	if len(options.APIListenAddr) > 0 {
+		apiServerCfg := api.ServerConfig{
+			Logger:     options.Logger,
+			ListenAddr: options.APIListenAddr,
+			PrivateKey: options.PrivateKey,
+
+			GRPCListenAddr: options.GRPCListenAddr,
+
+			ParticipantCh: participantCh,
+			RequireAuth:   options.RequireAPIAuth,
+			TokenRegistry: options.TokenRegistry,
+			DealerGroups:  options.DealerGroups,
+			RiskChecker:   riskChecker,
+			EventStore:    chain,
+			RFQIndex:      chain,
+			MarketData:    chain,
+			WebhookStore:  chain,
+			RateLimits:    options.RateLimits,
+			NodeAdmin:     s,
+			AdminAddress:  options.AdminAddress,
+
+			IdempotencyWindow: options.IdempotencyWindow,
+			WebhookPolicy:     options.WebhookPolicy,
+		}
+		if options.KeyShare != nil {
+			apiServerCfg.ThresholdKey = options.KeyShare.PublicKey
+			apiServerCfg.DecryptionThreshold = options.KeyShare.Threshold
+		}
+
+		apiServer = api.NewServer(apiServerCfg, chain, txChan)
+		s.apiServer = apiServer
+
+		go func() {
+			if err := apiServer.Start(); err != nil {
+				options.Logger.Log("msg", "JSON API stopped", "err", err)
+			}
+		}()
+
+		options.Logger.Log("msg", "JSON API running", "addr", options.APIListenAddr)
+		if len(options.GRPCListenAddr) > 0 {
+			options.Logger.Log("msg", "gRPC API running", "addr", options.GRPCListenAddr)
+		}
+	}
+
+	if options.KeyShare != nil {
+		validators := make(map[uint64]common.Address, len(options.Validators)+1)
+		for index, addr := range options.Validators {
+			validators[index] = addr
+		}
+		validators[options.KeyShare.Index] = options.ShareKey.PublicKey().Address()
+		s.decryptor = newQuoteDecryptor(options.KeyShare, validators)
+	}
+
 	if s.RPCProcessor == nil {
 		s.RPCProcessor = s
 	}
 	if s.isValidator {
 		s.RegisterCallback(apiServer.BroadcastTx)
+		s.RegisterMatchCallback(apiServer.BroadcastMatchResult)
 
+		s.loops.Add(1)
 		go func() {
+			defer s.loops.Done()
 			s.validatorLoop()
 		}()
 		go func() {
@@ -180,6 +293,12 @@ func (s *Server) RegisterCallback(cb func(*types.Transaction, byte)) {
 	s.Callbacks = append(s.Callbacks, cb)
 }
 
+// RegisterMatchCallback registers cb to be called with the result of every
+// auction this validator matches.
+func (s *Server) RegisterMatchCallback(cb func(*types.MatchResult)) {
+	s.MatchCallbacks = append(s.MatchCallbacks, cb)
+}
+
 func (s *Server) bootstrapNetwork() {
 	for _, addr := range s.SeedNodes {
 
@@ -192,9 +311,11 @@ func (s *Server) bootstrapNetwork() {
 			}
 			peerCtx, cancel := context.WithCancel(context.Background()) // or pass in the server's context if it exists
 			peer := &TCPPeer{
-				conn:       conn,
-				ctx:        peerCtx,
-				cancelFunc: cancel,
+				conn:        conn,
+				Outgoing:    true,
+				ctx:         peerCtx,
+				cancelFunc:  cancel,
+				connectedAt: time.Now(),
 			}
 
 			s.peerCh <- peer
@@ -206,7 +327,11 @@ func (s *Server) bootstrapNetwork() {
 
 var ErrBlockKnown = errors.New("block already known")
 
+// Start runs the node until Stop.
 func (s *Server) Start() {
+	s.started.Store(true)
+	defer close(s.stopped)
+
 	s.TCPTransport.Start()
 
 	time.Sleep(time.Second * 2)
@@ -218,7 +343,11 @@ func (s *Server) Start() {
 
 	if len(s.Callbacks) > 0 && s.isValidator {
 		fmt.Printf("XXXX Starting callback loop\n")
-		go s.listenToTxEvents()
+		s.loops.Add(1)
+		go func() {
+			defer s.loops.Done()
+			s.listenToTxEvents()
+		}()
 	}
 
 	errors := make(chan error)
@@ -227,7 +356,9 @@ free:
 	for {
 		select {
 		case peer := <-s.peerCh:
+			s.mu.Lock()
 			s.peerMap[peer.conn.RemoteAddr()] = peer
+			s.mu.Unlock()
 			peer.transport = s.TCPTransport
 
 			s.Logger.Log("msg", "new peer added", "outgoing", peer.Outgoing, "addr", peer.conn.RemoteAddr())
@@ -239,6 +370,10 @@ free:
 			}()
 			go handleErrors(errors, s.Logger)
 
+			if err := s.sendParticipants(peer); err != nil {
+				s.Logger.Log("msg", "failed to send participants", "addr", peer.conn.RemoteAddr(), "err", err)
+			}
+
 		case tx := <-s.txChan:
 			fmt.Printf("XXXX Received tx [%+v]\n", tx)
 
@@ -247,6 +382,10 @@ free:
 			}
 			fmt.Printf("XXXX Processed tx [%+v]\n", tx)
 			s.Logger.Log("msg", "new transaction received", "tx", tx)
+		case p := <-s.participantCh:
+			if err := s.broadcastParticipant(p); err != nil {
+				s.Logger.Log("msg", "failed to broadcast participant", "err", err)
+			}
 		case rpc := <-s.rpcCh:
 			msg, err := s.RPCDecodeFunc(rpc)
 			fmt.Printf(Purple+"XXXX Received msg [%+v]"+Reset+"\n", msg.Data)
@@ -276,14 +415,14 @@ func handleErrors(errors <-chan error, logger log.Logger) {
 }
 
 func (s *Server) listenToTxEvents() {
-	go func() {
-		for {
-			select {
-			case event := <-s.chain.EventChan:
-				s.handleTxEvent(event)
-			}
+	for {
+		select {
+		case event := <-s.chain.EventChan:
+			s.handleTxEvent(event)
+		case <-s.ctx.Done():
+			return
 		}
-	}()
+	}
 }
 
 func (s *Server) handleTxEvent(event types.TxEvent) {
@@ -315,10 +454,22 @@ func (s *Server) handleRFQRequest(event types.TxEvent) {
 	// Create an OpenRFQ transaction and broadcast it to the network
 
 	openRFQData := createOpenRFQData(tx, event.TxHash)
+	// private RFQs are only shown to, and only accept quotes from, their recipients
+	recipients, err := s.DealerGroups.Recipients(openRFQData.RFQRequest)
+	if err != nil {
+		s.Logger.Log("msg", "Failed to resolve RFQ recipients", "hash", event.TxHash, "err", err)
+		return
+	}
+	openRFQData.Recipients = recipients
 
 	currentTime := time.Now().UnixNano() / int64(time.Millisecond)
 	openRFQData.RFQStartTime = currentTime
 	openRFQData.RFQEndTime = currentTime + int64(tx.EmbeddedData().(*types.SignableData).RFQDurationMs)
+	// pre-trade risk checks, the RFQ counts against the requestor's limits once opened
+	if err := s.riskChecker.Open(*tx.From(), event.TxHash, openRFQData.RFQRequest, currentTime, openRFQData.RFQEndTime); err != nil {
+		s.Logger.Log("msg", "RFQ rejected by risk checks", "hash", event.TxHash, "err", err)
+		return
+	}
 	openRFQData.Status = types.RFQStatusOpen
 	newOpenRfq := types.NewOpenRFQ(s.ServerOptions.PrivateKey.PublicKey().Address(), openRFQData)
 	txOpenRfq := types.NewTx(newOpenRfq)
@@ -342,7 +493,7 @@ func (s *Server) handleRFQRequest(event types.TxEvent) {
 	s.chain.WriteRFQTxs(signedTx)
 
 	// we submit it to the blockchain to provide a decentralized record of the RFQ Opening for bids
-	s.txChan <- signedTx
+	s.submitTx(signedTx)
 
 }
 
@@ -354,8 +505,47 @@ func (s *Server) handleQuoteEvent(event types.TxEvent) {
 }
 
 func (s *Server) handleCloseRFQ(event types.TxEvent) {
+	tx, ok := event.Transaction.(*types.Transaction)
+	if !ok {
+		s.Logger.Log("msg", "Failed to cast Transaction to Transaction", "hash", event.TxHash)
+		return
+	}
+
+	// The closed auction carries the Merkle root of the quotes it received. Signing it
+	// and recording it on chain lets quoters prove their quote was part of the auction.
+	signedTx, err := tx.Sign(*s.ServerOptions.PrivateKey)
+	if err != nil {
+		s.Logger.Log("msg", "Failed to sign ClosedRFQ", "err", err)
+		return
+	}
+	if err := s.chain.WriteRFQTxs(signedTx); err != nil {
+		s.Logger.Log("msg", "Failed to store ClosedRFQ", "err", err)
+		return
+	}
+
 	for _, callback := range s.Callbacks {
-		callback(event.Transaction.(*types.Transaction), types.OpenRFQTxType)
+		callback(signedTx, types.OpenRFQTxType)
+	}
+	s.matchAuction(tx.ReferenceTxHash())
+
+	s.submitTx(signedTx)
+}
+
+// submitTx hands a transaction created by the validator to the node loop.
+// Once the node is stopping it goes straight to the mempool for Stop to save.
+func (s *Server) submitTx(tx *types.Transaction) {
+	select {
+	case s.txChan <- tx:
+	case <-s.ctx.Done():
+		s.memPool.Add(tx)
+	}
+}
+
+// publishTxEvent passes ev to listenToTxEvents unless the node is stopping.
+func (s *Server) publishTxEvent(ev types.TxEvent) {
+	select {
+	case s.chain.EventChan <- ev:
+	case <-s.ctx.Done():
 	}
 }
 
@@ -376,8 +566,17 @@ func (s *Server) validatorLoop() {
 
 	s.Logger.Log("msg", "Starting validator loop", "blockTime", s.BlockTime)
 
+	defer ticker.Stop()
+
 	for {
-		<-ticker.C
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return
+		}
+		if s.blockProductionPaused.Load() {
+			continue
+		}
 		s.CreateNewBlock()
 	}
 }
@@ -399,6 +598,10 @@ func (s *Server) ProcessMessage(msg *DecodeMessage) error {
 	case *BlocksMessage:
 		fmt.Printf(Yellow+"PROCESSBLOCKS MESSAGE - RECEIVED[%+v]: => from %+v t: %+v"+Reset+"\n", s.ID, msg.ID, t)
 		return s.processBlocksMessage(msg.From, t)
+	case *DecryptionSharesMessage:
+		return s.processDecryptionSharesMessage(t)
+	case *ParticipantMessage:
+		return s.processParticipantMessage(t)
 	default:
 		fmt.Printf(Yellow+"UNKNOWN MESSAGE TYPE: %+v"+Reset+"\n", t)
 
@@ -439,7 +642,9 @@ func (s *Server) processGetBlocksMessage(from net.Addr, data *GetBlocksMessage)
 
 	msg := NewMessage(MessageTypeBlocks, buf.Bytes(), s.ID)
 
+	s.mu.RLock()
 	peer, ok := s.peerMap[from]
+	s.mu.RUnlock()
 	if !ok {
 		return fmt.Errorf("peer not found")
 	}
@@ -474,6 +679,7 @@ func (s *Server) processBlocksMessage(from net.Addr, data *BlocksMessage) error
 			s.Logger.Log("err", err)
 			continue
 		}
+		s.recordClosedAuctions(newBlock)
 
 	}
 
@@ -507,9 +713,11 @@ func (s *Server) statusLoop() {
 			if currentHeight != lastBroadcastHeight {
 				buf := new(bytes.Buffer)
 				status := s.createStatusMessage(buf) // This should include the current block height
+				s.mu.RLock()
 				for _, peer := range s.peerMap {
 					_ = peer.Send(status)
 				}
+				s.mu.RUnlock()
 				lastBroadcastHeight = currentHeight
 			}
 		}
@@ -552,6 +760,9 @@ func (s *Server) processBlock(b *types.Block) error {
 		return err
 	}
 
+	s.recordClosedAuctions(b)
+	s.releaseDecryptionShares(b.Header())
+
 	go s.broadcastBlock(b)
 
 	return nil
@@ -572,6 +783,15 @@ func (s *Server) processTransaction(tx *types.Transaction) error {
 	if err := tx.Verify(); err != nil {
 		return err
 	}
+	if err := s.chain.VerifyTxParticipant(tx, uint64(time.Now().UnixNano()/int64(time.Millisecond))); err != nil {
+		return err
+	}
+	if err := s.verifyTxTokens(tx); err != nil {
+		return err
+	}
+	if err := s.verifyTxRecipient(tx); err != nil {
+		return err
+	}
 
 	s.Logger.Log(
 		"msg", "added new tx to pool",
@@ -587,10 +807,10 @@ func (s *Server) processTransaction(tx *types.Transaction) error {
 	case types.RFQRequestTxType:
 		// og the RFQRequest has been verified we need to broadcast over websockets the start of a new tfq round
 		s.Logger.Log("msg", "adding RFQRequestTx to event channel")
-		s.chain.EventChan <- types.TxEvent{TxType: tx.Type(), TxHash: tx.Hash(), Transaction: tx}
+		s.publishTxEvent(types.TxEvent{TxType: tx.Type(), TxHash: tx.Hash(), Transaction: tx})
 	case types.QuoteTxType:
 		s.Logger.Log("msg", "adding QuoteTx to event channel")
-		s.chain.EventChan <- types.TxEvent{TxType: tx.Type(), TxHash: tx.Hash(), Transaction: tx}
+		s.publishTxEvent(types.TxEvent{TxType: tx.Type(), TxHash: tx.Hash(), Transaction: tx})
 	}
 
 	return nil
@@ -599,6 +819,7 @@ func (s *Server) processTransaction(tx *types.Transaction) error {
 // TODO: stop syncing when at highest block
 func (s *Server) requestBlocksLoop(peer net.Addr, blocksIndex int64) error {
 	ticker := time.NewTicker(6 * time.Second)
+	defer ticker.Stop()
 
 	for {
 
@@ -620,11 +841,10 @@ func (s *Server) requestBlocksLoop(peer net.Addr, blocksIndex int64) error {
 			return err
 		}
 
-		s.mu.RLock()
-		defer s.mu.RUnlock()
-
 		msg := NewMessage(MessageTypeGetBlocks, buf.Bytes(), s.ID)
+		s.mu.RLock()
 		peer, ok := s.peerMap[peer]
+		s.mu.RUnlock()
 		if !ok {
 			return fmt.Errorf("peer %+s not found", peer.conn.RemoteAddr())
 		}
@@ -633,7 +853,11 @@ func (s *Server) requestBlocksLoop(peer net.Addr, blocksIndex int64) error {
 			s.Logger.Log("error", "failed to send to peer", "err", err, "peer", peer.conn.RemoteAddr())
 		}
 
-		<-ticker.C
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return nil
+		}
 	}
 }
 
@@ -642,8 +866,6 @@ func (s *Server) broadcastBlock(b *types.Block) error {
 	// if err := b.Encode(common.NewGobBlockEncoder(buf)); err != nil {
 	// 	return err
 	// }
-	s.mu.Lock()
-	defer s.mu.Unlock()
 	msg := NewMessage(MessageTypeBlock, buf.Bytes(), s.ID)
 
 	return s.broadcast(msg.Bytes())
@@ -687,13 +909,98 @@ func (s *Server) CreateNewBlock() error {
 
 	s.memPool.ClearPending()
 
+	s.recordClosedAuctions(block)
+	s.releaseDecryptionShares(block.Header())
+
 	go s.broadcastBlock(block)
 
 	return nil
 }
 
-func (s *Server) Stop() {
+// Stop shuts the node down: the API drains its requests while the node
+// still takes their transactions, then the node loops, the transport and the
+// peer connections stop, the mempool and the open auctions are written to
+// the database and the database is closed. Waiting for the API, the node
+// loops and the auction queue is bounded by ctx; when they don't stop in
+// time nothing is saved and the database is left open.
+func (s *Server) Stop(ctx context.Context) error {
+	var errs []error
+	if s.apiServer != nil {
+		if err := s.apiServer.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	s.cancelFunc()
+	s.TCPTransport.Stop()
+	s.mu.Lock()
+	for addr, peer := range s.peerMap {
+		delete(s.peerMap, addr)
+		s.TCPTransport.RemovePeer(peer)
+		peer.disconnect()
+	}
+	s.mu.Unlock()
+
+	// a Start that hasn't reached its loop yet returns as soon as it does
+	select {
+	case s.quitCh <- struct{}{}:
+	default:
+	}
+	if s.started.Load() {
+		select {
+		case <-s.stopped:
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("error waiting for the node to stop: %w", ctx.Err()))
+		}
+	}
+	loopsDone := make(chan struct{})
+	go func() {
+		s.loops.Wait()
+		close(loopsDone)
+	}()
+	select {
+	case <-loopsDone:
+	case <-ctx.Done():
+		errs = append(errs, fmt.Errorf("error waiting for the node loops to stop: %w", ctx.Err()))
+	}
+	// when a wait was cut short the API handlers or the node loops may still
+	// write to the database: it can't be closed under them, nor can the
+	// mempool and the auctions they change be saved
+	if ctx.Err() != nil {
+		s.Logger.Log("level", "error", "msg", "node did not stop in time, database left open")
+		return errors.Join(errs...)
+	}
+
+	if err := s.chain.WriteMempool(s.memPool.Pending()); err != nil {
+		errs = append(errs, fmt.Errorf("error saving mempool: %w", err))
+	}
+	if err := s.chain.Stop(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("error saving open auctions: %w", err))
+		if ctx.Err() != nil {
+			s.Logger.Log("level", "error", "msg", "node did not stop in time, database left open")
+			return errors.Join(errs...)
+		}
+	}
+	if err := s.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("error closing database: %w", err))
+	}
+	s.Logger.Log("msg", "node stopped")
+	return errors.Join(errs...)
+}
+
+// restoreMempool adds the transactions saved by Stop back to the mempool.
+func (s *Server) restoreMempool() error {
+	txs, err := s.chain.ReadMempool()
+	if err != nil {
+		return err
+	}
+	for _, tx := range txs {
+		s.memPool.Add(tx)
+	}
+	if len(txs) > 0 {
+		s.Logger.Log("msg", "restored mempool", "txs", len(txs))
+	}
+	return s.chain.WriteMempool(nil)
 }
 
 func genesisBlock() *types.Block {
@@ -718,9 +1025,3 @@ func genesisBlock() *types.Block {
 	return b
 }
 
-func deleteDirectoryIfExists(path string) error {
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return nil
-	}
-	return os.RemoveAll(path)
-}
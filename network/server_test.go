@@ -0,0 +1,100 @@
+package network
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/OCAX-labs/rfqrelayer/utils"
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startServer runs s until the test stops it.
+func startServer(t *testing.T, s *Server) <-chan struct{} {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		s.Start()
+		close(done)
+	}()
+	require.Eventually(t, s.started.Load, 5*time.Second, 10*time.Millisecond)
+	return done
+}
+
+func stopServer(t *testing.T, s *Server, done <-chan struct{}) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, s.Stop(ctx))
+	select {
+	case <-done:
+	default:
+		t.Fatal("Start still running after Stop")
+	}
+}
+
+func TestServerRestart(t *testing.T) {
+	id := "restart-test"
+	t.Cleanup(func() { os.RemoveAll(fmt.Sprintf("./.%s.db", id)) })
+	// no block is produced during the test so the transaction stays pending
+	options := ServerOptions{
+		ID:         id,
+		ListenAddr: "127.0.0.1:0",
+		PrivateKey: &testKey,
+		BlockTime:  time.Hour,
+		Logger:     log.NewNopLogger(),
+	}
+
+	s, err := NewServer(options)
+	require.NoError(t, err)
+	head := s.chain.CurrentBlock().Hash()
+	tx := utils.NewRandomTransaction(testKey.PublicKey())
+	s.memPool.Add(tx)
+	stopServer(t, s, startServer(t, s))
+
+	// the node restarts on its chain with the saved mempool
+	s, err = NewServer(options)
+	require.NoError(t, err)
+	assert.Equal(t, head, s.chain.CurrentBlock().Hash())
+	pending := s.memPool.Pending()
+	require.Len(t, pending, 1)
+	assert.Equal(t, tx.Hash(), pending[0].Hash())
+	// the saved mempool is only restored once
+	saved, err := s.chain.ReadMempool()
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+	stopServer(t, s, startServer(t, s))
+}
+
+func TestServerStopTimeout(t *testing.T) {
+	id := "stop-timeout-test"
+	t.Cleanup(func() { os.RemoveAll(fmt.Sprintf("./.%s.db", id)) })
+	s, err := NewServer(ServerOptions{
+		ID:         id,
+		ListenAddr: "127.0.0.1:0",
+		PrivateKey: &testKey,
+		BlockTime:  time.Hour,
+		Logger:     log.NewNopLogger(),
+	})
+	require.NoError(t, err)
+	startServer(t, s)
+	s.memPool.Add(utils.NewRandomTransaction(testKey.PublicKey()))
+
+	// a loop that doesn't stop in time keeps the database open, as it may
+	// still write to it, and nothing is saved under it
+	s.loops.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, s.Stop(ctx), context.DeadlineExceeded)
+	saved, err := s.chain.ReadMempool()
+	require.NoError(t, err)
+	assert.Empty(t, saved)
+
+	s.loops.Done()
+	require.NoError(t, s.chain.Stop(context.Background()))
+	require.NoError(t, s.db.Close())
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -20,28 +21,27 @@ type TCPPeer struct {
 	Outgoing   bool
 	ctx        context.Context
 	cancelFunc context.CancelFunc
+
+	// connection stats reported to the admin API
+	connectedAt      time.Time
+	messagesSent     atomic.Uint64
+	messagesReceived atomic.Uint64
+	bytesSent        atomic.Uint64
+	bytesReceived    atomic.Uint64
 }
 
 func (p *TCPPeer) Send(msg *Message) error {
 
-	payload := msg.Bytes()
-
-	p.conn.SetWriteDeadline(time.Now().Add(timeout)) // Set a write deadline
-	_, err := p.conn.Write(payload)
-	if err != nil {
-		fmt.Printf("Failed to send, reconnecting: %v\n", err)
-		err = p.reconnect()
-		if err != nil {
-			fmt.Printf("Failed to reconnect: %v\n", err)
-		}
-	}
-	return err
+	return p.SendBytesPayload(msg.Bytes())
 }
 
 func (p *TCPPeer) SendBytesPayload(payload []byte) error {
 	p.conn.SetWriteDeadline(time.Now().Add(timeout)) // Set a write deadline
-	_, err := p.conn.Write(payload)
-	if err != nil {
+	n, err := p.conn.Write(payload)
+	if err == nil {
+		p.messagesSent.Add(1)
+		p.bytesSent.Add(uint64(n))
+	} else {
 		fmt.Printf("Failed to send, reconnecting: %v\n", err)
 		err = p.reconnect()
 		if err != nil {
@@ -51,6 +51,13 @@ func (p *TCPPeer) SendBytesPayload(payload []byte) error {
 	return err
 }
 
+// disconnect closes the connection for good, the peer's read loop stops
+// instead of reconnecting.
+func (p *TCPPeer) disconnect() {
+	p.cancelFunc()
+	p.conn.Close()
+}
+
 func (p *TCPPeer) reconnect() error {
 	var err error
 	for i := 0; i < 3; i++ { // try to reconnect 3 times
@@ -75,6 +82,10 @@ func (t *TCPTransport) acceptLoop(errors chan<- error) {
 
 		conn, err := t.listener.Accept()
 		if err != nil {
+			if t.ctx.Err() != nil {
+				fmt.Println("TCP TRANSPORT: Stopping accept loop")
+				return
+			}
 			fmt.Printf("Error accepting connection err: [%+v]\n", err)
 			errors <- fmt.Errorf("error accepting connection: %w", err)
 			continue
@@ -83,10 +94,11 @@ func (t *TCPTransport) acceptLoop(errors chan<- error) {
 		defer cancel()
 
 		peer := &TCPPeer{
-			ID:         t.ID,
-			conn:       conn,
-			ctx:        peerCtx,
-			cancelFunc: cancel,
+			ID:          t.ID,
+			conn:        conn,
+			ctx:         peerCtx,
+			cancelFunc:  cancel,
+			connectedAt: time.Now(),
 		}
 
 		t.peerCh <- peer
@@ -131,8 +143,10 @@ func (t *TCPTransport) HasPeer(peer *TCPPeer) bool {
 	return ok
 }
 
+// readLoop passes the messages read from the peer to rpcCh until the peer is
+// disconnected or the connection is lost. errors is shared with the other
+// peers so it is left open.
 func (p *TCPPeer) readLoop(rpcCh chan RPC, errors chan<- error) {
-	defer close(errors)
 	buf := make([]byte, 4096)
 
 	for {
@@ -145,6 +159,10 @@ func (p *TCPPeer) readLoop(rpcCh chan RPC, errors chan<- error) {
 
 		n, err := p.conn.Read(buf)
 		if err != nil {
+			if p.ctx.Err() != nil {
+				fmt.Printf("Stopping read loop for [%+v]\n", p.conn)
+				return
+			}
 			if err == io.EOF {
 				fmt.Printf("Connection closed\n")
 				errors <- fmt.Errorf("connection closed by peer: %w", err)
@@ -161,6 +179,8 @@ func (p *TCPPeer) readLoop(rpcCh chan RPC, errors chan<- error) {
 			}
 		}
 
+		p.messagesReceived.Add(1)
+		p.bytesReceived.Add(uint64(n))
 		msg := buf[:n]
 		rpcCh <- RPC{
 			From:    p.conn.RemoteAddr(),
@@ -173,9 +193,13 @@ type TCPTransport struct {
 	ID         string
 	peerCh     chan *TCPPeer
 	listenAddr string
-	listener   net.Listener
 	rpcCh      chan RPC
 
+	// listenerMu orders Start and Stop, a transport stopped before it
+	// started doesn't listen
+	listenerMu sync.Mutex
+	listener   net.Listener
+
 	activePeers map[*TCPPeer]struct{}
 	peerMutex   sync.Mutex // Protects activePeers
 
@@ -197,6 +221,11 @@ func NewTCPTransport(id string, addr string, peerCh chan *TCPPeer, rpcCh chan RP
 }
 
 func (t *TCPTransport) Start() error {
+	t.listenerMu.Lock()
+	defer t.listenerMu.Unlock()
+	if t.ctx.Err() != nil {
+		return nil
+	}
 	ln, err := net.Listen("tcp", t.listenAddr)
 	if err != nil {
 		return err
@@ -215,5 +244,9 @@ func (t *TCPTransport) Start() error {
 // When stopping, cancel the context.
 func (t *TCPTransport) Stop() {
 	t.cancelFunc()
-	t.listener.Close()
+	t.listenerMu.Lock()
+	defer t.listenerMu.Unlock()
+	if t.listener != nil {
+		t.listener.Close()
+	}
 }
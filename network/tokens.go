@@ -0,0 +1,29 @@
+package network
+
+import (
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+// verifyTxTokens checks the tokens of RFQ requests and quotes received from
+// clients or peers against the token registry, and that quotes answer the
+// RFQ they reference when this node has it open.
+func (s *Server) verifyTxTokens(tx *types.Transaction) error {
+	switch tx.Type() {
+	case types.RFQRequestTxType:
+		data := tx.EmbeddedData().(*types.SignableData)
+		if s.TokenRegistry != nil {
+			return s.TokenRegistry.ValidateRFQ(data)
+		}
+	case types.QuoteTxType:
+		data := tx.EmbeddedData().(*types.QuoteData)
+		if s.TokenRegistry != nil {
+			if err := s.TokenRegistry.ValidateQuote(data); err != nil {
+				return err
+			}
+		}
+		if openRFQ, err := s.chain.GetOpenRFQByHash(data.RFQTxHash); err == nil {
+			return data.CheckRFQ(openRFQ.Data.RFQRequest)
+		}
+	}
+	return nil
+}
@@ -42,7 +42,7 @@ func (p *TxPool) Contains(hash common.Hash) bool {
 
 // Pending returns a slice of transactions that are in the pending pool
 func (p *TxPool) Pending() []*types.Transaction {
-	return p.pending.txx.Data
+	return p.pending.List()
 }
 
 func (p *TxPool) ClearPending() {
@@ -53,6 +53,12 @@ func (p *TxPool) PendingCount() int {
 	return p.pending.Count()
 }
 
+// Count returns the number of transactions in the pool, including those no
+// longer pending.
+func (p *TxPool) Count() int {
+	return p.all.Count()
+}
+
 type TxSortedMap struct {
 	lock   sync.RWMutex
 	lookup map[common.Hash]*types.Transaction
@@ -101,6 +107,14 @@ func (t *TxSortedMap) Remove(h common.Hash) {
 	delete(t.lookup, h)
 }
 
+// List returns a copy of the transactions in the order they were added.
+func (t *TxSortedMap) List() []*types.Transaction {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	return append([]*types.Transaction(nil), t.txx.Data...)
+}
+
 func (t *TxSortedMap) Count() int {
 	t.lock.RLock()
 	defer t.lock.RUnlock()
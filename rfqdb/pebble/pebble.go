@@ -59,6 +59,7 @@ type Database struct {
 
 	quitLock sync.Mutex      // Mutex protecting the quit channel access
 	quitChan chan chan error // Quit channel to stop the metrics collection before closing the database
+	closed   bool            // Set once the database is closed
 
 	log log.Logger // Contextual logger tracking the database path
 
@@ -214,17 +215,20 @@ func (d *Database) Close() error {
 	defer d.quitLock.Unlock()
 
 	// Allow double closing, simplifies things
-	if d.quitChan == nil {
+	if d.closed {
 		return nil
 	}
-	errc := make(chan error)
-	d.quitChan <- errc
-	err := <-errc
-	close(d.quitChan)
-	if err != nil {
-		level.Error(d.log).Log("err", err)
+	d.closed = true
+	if d.quitChan != nil {
+		errc := make(chan error)
+		d.quitChan <- errc
+		err := <-errc
+		close(d.quitChan)
+		if err != nil {
+			level.Error(d.log).Log("err", err)
+		}
+		d.quitChan = nil
 	}
-	d.quitChan = nil
 
 	return d.db.Close()
 }
@@ -0,0 +1,296 @@
+// Package risk applies the pre-trade limits of requestors before their RFQs
+// are opened and tracks how much of each limit is in use.
+package risk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+// dayMs is the window of the daily limit in milliseconds.
+const dayMs = 24 * 60 * 60 * 1000
+
+var (
+	ErrRFQAmount   = errors.New("RFQ amount exceeds the per RFQ limit")
+	ErrOpenAmount  = errors.New("open RFQ amount would exceed the limit")
+	ErrDailyAmount = errors.New("RFQ amount over the last 24 hours would exceed the daily limit")
+	ErrPairAmount  = errors.New("open RFQ amount in the token pair would exceed the limit")
+)
+
+// LimitsFunc returns the limits of a requestor, nil if it has none.
+type LimitsFunc func(requestor common.Address) *types.ParticipantLimits
+
+// Store persists the RFQs counted against the limits so daily limits
+// survive restarts.
+type Store interface {
+	WriteRiskUsage(rfqTxHash common.Hash, data []byte) error
+	DeleteRiskUsage(rfqTxHash common.Hash) error
+	GetRiskUsage() ([][]byte, error)
+}
+
+// PairUtilisation is the open amount of a requestor in one token pair.
+type PairUtilisation struct {
+	BaseToken     common.Address `json:"baseToken"`
+	QuoteToken    common.Address `json:"quoteToken"`
+	OpenAmount    *big.Int       `json:"openAmount"`
+	MaxOpenAmount *big.Int       `json:"maxOpenAmount,omitempty"`
+}
+
+// Utilisation reports a requestor's limits and how much of them is used.
+type Utilisation struct {
+	Address     common.Address           `json:"address"`
+	Limits      *types.ParticipantLimits `json:"limits"`
+	OpenRFQs    int                      `json:"openRFQs"`
+	OpenAmount  *big.Int                 `json:"openAmount"`
+	DailyRFQs   int                      `json:"dailyRFQs"`
+	DailyAmount *big.Int                 `json:"dailyAmount"`
+	Pairs       []*PairUtilisation       `json:"pairs"`
+}
+
+type pair struct {
+	base, quote common.Address
+}
+
+// rfq is an RFQ counted against its requestor's limits. It counts towards
+// the open amount until it ends and the daily amount for 24 hours after it
+// opened.
+type rfq struct {
+	hash     common.Hash
+	pair     pair
+	amount   *big.Int
+	openedAt int64
+	endsAt   int64
+}
+
+// record is the stored form of an rfq.
+type record struct {
+	Requestor  common.Address `json:"requestor"`
+	RFQTxHash  common.Hash    `json:"rfqTxHash"`
+	BaseToken  common.Address `json:"baseToken"`
+	QuoteToken common.Address `json:"quoteToken"`
+	Amount     *big.Int       `json:"amount"`
+	OpenedAt   int64          `json:"openedAt"`
+	EndsAt     int64          `json:"endsAt"`
+}
+
+// Checker holds the RFQs opened by each requestor. All times are unix
+// milliseconds.
+type Checker struct {
+	limits LimitsFunc
+	// store is nil when the RFQs are only kept in memory
+	store Store
+
+	mu   sync.Mutex
+	rfqs map[common.Address][]*rfq
+}
+
+// NewChecker returns a checker holding the RFQs saved in store, which may
+// be nil.
+func NewChecker(limits LimitsFunc, store Store) (*Checker, error) {
+	c := &Checker{
+		limits: limits,
+		store:  store,
+		rfqs:   make(map[common.Address][]*rfq),
+	}
+	if store == nil {
+		return c, nil
+	}
+	stored, err := store.GetRiskUsage()
+	if err != nil {
+		return nil, err
+	}
+	for _, data := range stored {
+		var rec record
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("error decoding risk usage: %w", err)
+		}
+		if rec.Amount == nil {
+			return nil, fmt.Errorf("risk usage of RFQ %s has no amount", rec.RFQTxHash)
+		}
+		c.rfqs[rec.Requestor] = append(c.rfqs[rec.Requestor], &rfq{
+			hash:     rec.RFQTxHash,
+			pair:     pair{rec.BaseToken, rec.QuoteToken},
+			amount:   rec.Amount,
+			openedAt: rec.OpenedAt,
+			endsAt:   rec.EndsAt,
+		})
+	}
+	return c, nil
+}
+
+// Check reports whether requestor may open an RFQ for data at now without
+// exceeding its limits.
+func (c *Checker) Check(requestor common.Address, data *types.SignableData, now int64) error {
+	limits := c.limits(requestor)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.check(requestor, limits, newRFQ(common.Hash{}, data, now, now), now)
+}
+
+// Open checks the limits and counts the RFQ opened at now and ending at
+// endsAt against them, as one step so concurrent RFQs can't both fit under a
+// limit only one of them fits in. Opening an RFQ that is already counted is
+// a no-op.
+func (c *Checker) Open(requestor common.Address, rfqTxHash common.Hash, data *types.SignableData, now, endsAt int64) error {
+	limits := c.limits(requestor)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune(requestor, now)
+	for _, r := range c.rfqs[requestor] {
+		if r.hash == rfqTxHash {
+			return nil
+		}
+	}
+	r := newRFQ(rfqTxHash, data, now, endsAt)
+	if err := c.check(requestor, limits, r, now); err != nil {
+		return err
+	}
+	if c.store != nil {
+		data, err := json.Marshal(&record{
+			Requestor:  requestor,
+			RFQTxHash:  r.hash,
+			BaseToken:  r.pair.base,
+			QuoteToken: r.pair.quote,
+			Amount:     r.amount,
+			OpenedAt:   r.openedAt,
+			EndsAt:     r.endsAt,
+		})
+		if err != nil {
+			return err
+		}
+		if err := c.store.WriteRiskUsage(r.hash, data); err != nil {
+			return err
+		}
+	}
+	c.rfqs[requestor] = append(c.rfqs[requestor], r)
+	return nil
+}
+
+// Utilisation returns requestor's limits and their use at now.
+func (c *Checker) Utilisation(requestor common.Address, now int64) *Utilisation {
+	limits := c.limits(requestor)
+	if limits == nil {
+		limits = new(types.ParticipantLimits)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.prune(requestor, now)
+	u := &Utilisation{
+		Address:     requestor,
+		Limits:      limits,
+		OpenAmount:  new(big.Int),
+		DailyAmount: new(big.Int),
+	}
+	pairs := make(map[pair]*PairUtilisation)
+	pairFor := func(p pair) *PairUtilisation {
+		pu, ok := pairs[p]
+		if !ok {
+			pu = &PairUtilisation{BaseToken: p.base, QuoteToken: p.quote, OpenAmount: new(big.Int)}
+			pairs[p] = pu
+			u.Pairs = append(u.Pairs, pu)
+		}
+		return pu
+	}
+	for _, limit := range limits.Pairs {
+		pairFor(pair{limit.BaseToken, limit.QuoteToken}).MaxOpenAmount = limit.MaxOpenAmount
+	}
+	for _, r := range c.rfqs[requestor] {
+		if r.endsAt > now {
+			u.OpenRFQs++
+			u.OpenAmount.Add(u.OpenAmount, r.amount)
+			pu := pairFor(r.pair)
+			pu.OpenAmount.Add(pu.OpenAmount, r.amount)
+		}
+		if r.openedAt > now-dayMs {
+			u.DailyRFQs++
+			u.DailyAmount.Add(u.DailyAmount, r.amount)
+		}
+	}
+	return u
+}
+
+func newRFQ(hash common.Hash, data *types.SignableData, openedAt, endsAt int64) *rfq {
+	r := &rfq{hash: hash, amount: new(big.Int), openedAt: openedAt, endsAt: endsAt}
+	if data.BaseTokenAmount != nil {
+		r.amount.Set(data.BaseTokenAmount)
+	}
+	if data.BaseToken != nil {
+		r.pair.base = data.BaseToken.Address
+	}
+	if data.QuoteToken != nil {
+		r.pair.quote = data.QuoteToken.Address
+	}
+	return r
+}
+
+// check must be called with the lock held.
+func (c *Checker) check(requestor common.Address, limits *types.ParticipantLimits, next *rfq, now int64) error {
+	if limits == nil {
+		return nil
+	}
+	if exceeds(next.amount, limits.MaxBaseTokenAmount) {
+		return fmt.Errorf("%w: %s > %s", ErrRFQAmount, next.amount, limits.MaxBaseTokenAmount)
+	}
+
+	open := new(big.Int).Set(next.amount)
+	daily := new(big.Int).Set(next.amount)
+	pairOpen := new(big.Int).Set(next.amount)
+	for _, r := range c.rfqs[requestor] {
+		if r.endsAt > now {
+			open.Add(open, r.amount)
+			if r.pair == next.pair {
+				pairOpen.Add(pairOpen, r.amount)
+			}
+		}
+		if r.openedAt > now-dayMs {
+			daily.Add(daily, r.amount)
+		}
+	}
+	if exceeds(open, limits.MaxOpenAmount) {
+		return fmt.Errorf("%w: %s > %s", ErrOpenAmount, open, limits.MaxOpenAmount)
+	}
+	if exceeds(daily, limits.MaxDailyAmount) {
+		return fmt.Errorf("%w: %s > %s", ErrDailyAmount, daily, limits.MaxDailyAmount)
+	}
+	if max := limits.PairLimit(next.pair.base, next.pair.quote); exceeds(pairOpen, max) {
+		return fmt.Errorf("%w: %s > %s", ErrPairAmount, pairOpen, max)
+	}
+	return nil
+}
+
+// prune drops the RFQs of requestor that no longer count against any limit.
+// It must be called with the lock held.
+func (c *Checker) prune(requestor common.Address, now int64) {
+	rfqs := c.rfqs[requestor]
+	kept := rfqs[:0]
+	for _, r := range rfqs {
+		if r.endsAt > now || r.openedAt > now-dayMs {
+			kept = append(kept, r)
+		} else if c.store != nil {
+			// an RFQ left behind is dropped again after the next restart
+			c.store.DeleteRiskUsage(r.hash)
+		}
+	}
+	if len(kept) == 0 {
+		delete(c.rfqs, requestor)
+		return
+	}
+	c.rfqs[requestor] = kept
+}
+
+// exceeds reports whether amount is over max, a nil or zero max is no limit.
+func exceeds(amount, max *big.Int) bool {
+	return max != nil && max.Sign() > 0 && amount.Cmp(max) > 0
+}
@@ -0,0 +1,126 @@
+package risk
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	requestor = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	mkr       = common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2")
+	usdc      = common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+	weth      = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+)
+
+func rfqData(base common.Address, amount int64) *types.SignableData {
+	return &types.SignableData{
+		BaseToken:       &types.BaseToken{Address: base},
+		QuoteToken:      &types.QuoteToken{Address: usdc},
+		BaseTokenAmount: big.NewInt(amount),
+	}
+}
+
+func TestCheckerLimits(t *testing.T) {
+	limits := &types.ParticipantLimits{
+		MaxBaseTokenAmount: big.NewInt(100),
+		MaxOpenAmount:      big.NewInt(150),
+		MaxDailyAmount:     big.NewInt(250),
+		Pairs: []types.PairLimit{
+			{BaseToken: weth, QuoteToken: usdc, MaxOpenAmount: big.NewInt(60)},
+		},
+	}
+	checker, err := NewChecker(func(addr common.Address) *types.ParticipantLimits {
+		if addr == requestor {
+			return limits
+		}
+		return nil
+	}, nil)
+	require.NoError(t, err)
+	now := int64(1_700_000_000_000)
+	end := now + 60_000
+
+	assert.ErrorIs(t, checker.Check(requestor, rfqData(mkr, 101), now), ErrRFQAmount)
+	require.NoError(t, checker.Open(requestor, common.Hash{1}, rfqData(mkr, 100), now, end))
+	// opening the same RFQ twice only counts it once
+	require.NoError(t, checker.Open(requestor, common.Hash{1}, rfqData(mkr, 100), now, end))
+
+	assert.ErrorIs(t, checker.Check(requestor, rfqData(mkr, 51), now), ErrOpenAmount)
+	require.NoError(t, checker.Open(requestor, common.Hash{2}, rfqData(weth, 50), now, end))
+
+	// the open amount is released when the RFQs end, the daily amount is not
+	later := end + 1
+	require.NoError(t, checker.Open(requestor, common.Hash{3}, rfqData(weth, 60), later, later+60_000))
+	assert.ErrorIs(t, checker.Check(requestor, rfqData(weth, 1), later), ErrPairAmount)
+	assert.ErrorIs(t, checker.Check(requestor, rfqData(mkr, 50), later), ErrDailyAmount)
+	assert.NoError(t, checker.Check(requestor, rfqData(mkr, 40), later))
+	assert.NoError(t, checker.Check(requestor, rfqData(mkr, 100), now+dayMs+1))
+
+	// requestors without limits are only tracked
+	other := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	require.NoError(t, checker.Open(other, common.Hash{4}, rfqData(mkr, 1_000_000), now, end))
+
+	u := checker.Utilisation(requestor, later)
+	assert.Equal(t, 1, u.OpenRFQs)
+	assert.Equal(t, big.NewInt(60), u.OpenAmount)
+	assert.Equal(t, 3, u.DailyRFQs)
+	assert.Equal(t, big.NewInt(210), u.DailyAmount)
+	require.Len(t, u.Pairs, 1)
+	assert.Equal(t, weth, u.Pairs[0].BaseToken)
+	assert.Equal(t, big.NewInt(60), u.Pairs[0].OpenAmount)
+	assert.Equal(t, big.NewInt(60), u.Pairs[0].MaxOpenAmount)
+
+	u = checker.Utilisation(other, now)
+	assert.Equal(t, big.NewInt(1_000_000), u.OpenAmount)
+	assert.Empty(t, u.Limits.Pairs)
+}
+
+// memStore keeps risk usage in memory.
+type memStore map[common.Hash][]byte
+
+func (m memStore) WriteRiskUsage(rfqTxHash common.Hash, data []byte) error {
+	m[rfqTxHash] = data
+	return nil
+}
+
+func (m memStore) DeleteRiskUsage(rfqTxHash common.Hash) error {
+	delete(m, rfqTxHash)
+	return nil
+}
+
+func (m memStore) GetRiskUsage() ([][]byte, error) {
+	usage := make([][]byte, 0, len(m))
+	for _, data := range m {
+		usage = append(usage, data)
+	}
+	return usage, nil
+}
+
+func TestCheckerRestoresUsage(t *testing.T) {
+	limits := func(common.Address) *types.ParticipantLimits {
+		return &types.ParticipantLimits{MaxDailyAmount: big.NewInt(100)}
+	}
+	store := memStore{}
+	checker, err := NewChecker(limits, store)
+	require.NoError(t, err)
+	now := int64(1_700_000_000_000)
+	require.NoError(t, checker.Open(requestor, common.Hash{1}, rfqData(mkr, 60), now, now+60_000))
+	assert.Len(t, store, 1)
+
+	// a restarted checker still counts the RFQ against the daily limit
+	checker, err = NewChecker(limits, store)
+	require.NoError(t, err)
+	later := now + 120_000
+	assert.ErrorIs(t, checker.Check(requestor, rfqData(weth, 50), later), ErrDailyAmount)
+	u := checker.Utilisation(requestor, later)
+	assert.Equal(t, big.NewInt(60), u.DailyAmount)
+	assert.Equal(t, 1, u.DailyRFQs)
+
+	// and forgets it once it no longer counts against any limit
+	checker.Utilisation(requestor, now+dayMs+1)
+	assert.Empty(t, store)
+}
@@ -0,0 +1,131 @@
+// Package tokens validates the tokens used in RFQs and quotes against a
+// registry loaded from a token list (https://tokenlists.org).
+package tokens
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+)
+
+var (
+	ErrUnknownToken     = errors.New("token is not listed")
+	ErrTokenMismatch    = errors.New("token details do not match the token list")
+	ErrDuplicateToken   = errors.New("token is listed more than once")
+	ErrInvalidListEntry = errors.New("invalid token list entry")
+)
+
+// TokenInfo is a token list entry.
+type TokenInfo struct {
+	ChainID  uint64 `json:"chainId"`
+	Address  string `json:"address"`
+	Symbol   string `json:"symbol"`
+	Decimals uint64 `json:"decimals"`
+	Name     string `json:"name"`
+}
+
+// TokenList is the token list file format.
+type TokenList struct {
+	Name   string       `json:"name"`
+	Tokens []*TokenInfo `json:"tokens"`
+}
+
+// Registry holds the tokens that may be traded on one chain.
+type Registry struct {
+	chainID uint64
+	tokens  map[common.Address]*TokenInfo
+}
+
+// LoadRegistry reads the token list at path and keeps the tokens of chainID.
+func LoadRegistry(path string, chainID uint64) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	list := new(TokenList)
+	if err := json.Unmarshal(data, list); err != nil {
+		return nil, fmt.Errorf("error decoding token list: %w", err)
+	}
+	return NewRegistry(chainID, list.Tokens)
+}
+
+// NewRegistry builds a registry from the entries of chainID. Addresses must be
+// checksummed.
+func NewRegistry(chainID uint64, entries []*TokenInfo) (*Registry, error) {
+	r := &Registry{
+		chainID: chainID,
+		tokens:  make(map[common.Address]*TokenInfo),
+	}
+	for _, entry := range entries {
+		if entry.ChainID != chainID {
+			continue
+		}
+		if !common.IsHexAddress(entry.Address) || common.HexToAddress(entry.Address).Hex() != entry.Address {
+			return nil, fmt.Errorf("%w: address %q is not checksummed", ErrInvalidListEntry, entry.Address)
+		}
+		if entry.Symbol == "" {
+			return nil, fmt.Errorf("%w: %s has no symbol", ErrInvalidListEntry, entry.Address)
+		}
+		addr := common.HexToAddress(entry.Address)
+		if _, ok := r.tokens[addr]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateToken, entry.Address)
+		}
+		r.tokens[addr] = entry
+	}
+	return r, nil
+}
+
+func (r *Registry) ChainID() uint64 { return r.chainID }
+
+func (r *Registry) Len() int { return len(r.tokens) }
+
+// Lookup returns the listed token at addr.
+func (r *Registry) Lookup(addr common.Address) (*TokenInfo, bool) {
+	info, ok := r.tokens[addr]
+	return info, ok
+}
+
+// ValidateToken checks that token is listed with the same symbol and decimals.
+func (r *Registry) ValidateToken(token *types.Token) error {
+	if token == nil {
+		return fmt.Errorf("%w: missing token", ErrUnknownToken)
+	}
+	info, ok := r.tokens[token.Address]
+	if !ok {
+		return fmt.Errorf("%w: %s on chain %d", ErrUnknownToken, token.Address.Hex(), r.chainID)
+	}
+	if !strings.EqualFold(info.Symbol, token.Symbol) {
+		return fmt.Errorf("%w: %s symbol is %s not %s", ErrTokenMismatch, info.Address, info.Symbol, token.Symbol)
+	}
+	if info.Decimals != token.Decimals {
+		return fmt.Errorf("%w: %s has %d decimals not %d", ErrTokenMismatch, info.Address, info.Decimals, token.Decimals)
+	}
+	return nil
+}
+
+// ValidateRFQ checks both tokens of an RFQ request are listed.
+func (r *Registry) ValidateRFQ(data *types.SignableData) error {
+	if err := r.ValidateToken(data.BaseToken); err != nil {
+		return fmt.Errorf("baseToken: %w", err)
+	}
+	if err := r.ValidateToken(data.QuoteToken); err != nil {
+		return fmt.Errorf("quoteToken: %w", err)
+	}
+	return nil
+}
+
+// ValidateQuote checks both tokens of a quote are listed.
+func (r *Registry) ValidateQuote(data *types.QuoteData) error {
+	if err := r.ValidateToken(data.BaseToken); err != nil {
+		return fmt.Errorf("baseToken: %w", err)
+	}
+	if err := r.ValidateToken(data.QuoteToken); err != nil {
+		return fmt.Errorf("quoteToken: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/OCAX-labs/rfqrelayer/common"
+	"github.com/OCAX-labs/rfqrelayer/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadRegistry(t *testing.T) {
+	r, err := LoadRegistry("../tokens.json", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, r.Len())
+
+	r, err = LoadRegistry("../tokens.json", 5)
+	require.NoError(t, err)
+	assert.Equal(t, 0, r.Len())
+}
+
+func TestNewRegistryRejectsBadEntries(t *testing.T) {
+	_, err := NewRegistry(1, []*TokenInfo{
+		{ChainID: 1, Address: "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", Symbol: "USDC", Decimals: 6},
+	})
+	assert.ErrorIs(t, err, ErrInvalidListEntry)
+
+	usdc := &TokenInfo{ChainID: 1, Address: "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", Symbol: "USDC", Decimals: 6}
+	_, err = NewRegistry(1, []*TokenInfo{usdc, usdc})
+	assert.ErrorIs(t, err, ErrDuplicateToken)
+}
+
+func TestValidateRFQ(t *testing.T) {
+	r, err := LoadRegistry("../tokens.json", 1)
+	require.NoError(t, err)
+
+	mkr := &types.Token{Address: common.HexToAddress("0x9f8F72aA9304c8B593d555F12eF6589cC3A579A2"), Symbol: "MKR", Decimals: 18}
+	usdc := &types.Token{Address: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"), Symbol: "USDC", Decimals: 6}
+	rfq := &types.SignableData{BaseTokenAmount: big.NewInt(1), BaseToken: mkr, QuoteToken: usdc}
+	assert.NoError(t, r.ValidateRFQ(rfq))
+
+	wrongDecimals := *usdc
+	wrongDecimals.Decimals = 18
+	rfq.QuoteToken = &wrongDecimals
+	assert.ErrorIs(t, r.ValidateRFQ(rfq), ErrTokenMismatch)
+
+	rfq.QuoteToken = &types.Token{Address: common.HexToAddress("0x0d1d4e623D10F9FBA5Db95830F7d3839406C6AF2"), Symbol: "XYZ", Decimals: 18}
+	assert.ErrorIs(t, r.ValidateRFQ(rfq), ErrUnknownToken)
+
+	assert.NoError(t, r.ValidateQuote(&types.QuoteData{BaseToken: mkr, QuoteToken: usdc}))
+}